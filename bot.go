@@ -7,17 +7,21 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"reflect"
 	"regexp"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/valyala/fasthttp"
 
 	"github.com/mymmrac/telego/internal/json"
 	ta "github.com/mymmrac/telego/telegoapi"
+	tm "github.com/mymmrac/telego/telegometrics"
 )
 
 const (
@@ -28,6 +32,8 @@ const (
 	attachFile = `attach://`
 
 	botPathPrefix = "/bot"
+
+	meRetryInterval = time.Minute
 )
 
 // ErrEmptyToken bot token is empty
@@ -36,6 +42,25 @@ var ErrEmptyToken = errors.New("telego: empty token")
 // ErrInvalidToken bot token is invalid according to token regexp
 var ErrInvalidToken = errors.New("telego: invalid token format")
 
+// MethodError represents error returned by a failed call of the API method, use [errors.As] to get method name,
+// underlying error can be matched against [ta.Error] or its sentinel errors like [ta.ErrBotBlocked]
+type MethodError struct {
+	// Method name that failed (like sendMessage)
+	Method string
+	// Err underlying error
+	Err error
+}
+
+// Error returns underlying error text, method name is omitted as all methods already include it
+func (e *MethodError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns underlying error
+func (e *MethodError) Unwrap() error {
+	return e.Err
+}
+
 // validateToken validates if token matches format
 func validateToken(token string) bool {
 	reg := regexp.MustCompile(tokenRegexp)
@@ -44,21 +69,30 @@ func validateToken(token string) bool {
 
 // Bot represents Telegram bot
 type Bot struct {
-	token       string
-	apiURL      string
-	log         Logger
-	api         ta.Caller
-	constructor ta.RequestConstructor
+	token         string
+	apiURL        string
+	log           Logger
+	structuredLog *slog.Logger
+	api           ta.Caller
+	constructor   ta.RequestConstructor
 
 	debugMode             bool
 	useTestServerPath     bool
 	reportWarningAsErrors bool
 
+	local             *localAPIServer
+	migration         *chatMigration
+	interceptors      []Interceptor
+	instrumentation   tm.Instrumentation
+	methodCallOptions map[string][]CallOption
+
 	running atomic.Int32
 
-	myOnce     sync.Once
-	myID       int64
-	myUsername string
+	meMutex   sync.Mutex
+	me        *User
+	meUpdated time.Time
+	meRetryAt time.Time
+	meTTL     time.Duration
 }
 
 // Bot actions
@@ -86,6 +120,16 @@ func (b *Bot) run(actionToRun int32) error {
 // BotOption represents an option that can be applied to [Bot]
 type BotOption func(bot *Bot) error
 
+// APICall represents a call of API method with parameters that returns raw response
+type APICall func(ctx context.Context, methodName string, parameters any) (*ta.Response, error)
+
+// Interceptor intercepts every API method call, it can inspect or modify method name and parameters before passing
+// them to the next call in chain and inspect or modify the returned response. Interceptor can also short-circuit the
+// call by not calling next at all, in this case it must return either non-nil response or an error.
+// Note: Parameters are typed parameters of the method (like *[SendMessageParams]), they should not be modified in
+// place as they are owned by the caller, use a copy instead
+type Interceptor func(ctx context.Context, methodName string, parameters any, next APICall) (*ta.Response, error)
+
 // NewBot creates new bots with given options (order is important).
 // If no options are specified, default values are used.
 // Note: Default logger (that logs only errors if not configured) will hide your bot token, but it still may log
@@ -98,12 +142,15 @@ func NewBot(token string, options ...BotOption) (*Bot, error) {
 		return nil, ErrInvalidToken
 	}
 
+	log := newDefaultLogger(token)
 	b := &Bot{
-		token:       token,
-		apiURL:      defaultBotAPIServer,
-		log:         newDefaultLogger(token),
-		api:         ta.FastHTTPCaller{Client: &fasthttp.Client{}},
-		constructor: ta.DefaultConstructor{},
+		token:           token,
+		apiURL:          defaultBotAPIServer,
+		log:             log,
+		structuredLog:   newPrintfLogger(log),
+		api:             ta.FastHTTPCaller{Client: &fasthttp.Client{}},
+		constructor:     ta.DefaultConstructor{},
+		instrumentation: tm.Nop{},
 	}
 
 	for _, option := range options {
@@ -132,48 +179,158 @@ func (b *Bot) Logger() Logger {
 	return b.log
 }
 
-// updateMe updates bot ID and username
-func (b *Bot) updateMe() {
-	me, err := b.GetMe(context.Background())
+// Instrumentation returns bot instrumentation, if not set [tm.Nop] is returned
+func (b *Bot) Instrumentation() tm.Instrumentation {
+	if b.instrumentation == nil {
+		return tm.Nop{}
+	}
+	return b.instrumentation
+}
+
+// Me returns bot user cached from the last successful [Bot.GetMe] call, if there is no cached user or it's older
+// than TTL set by [WithMeTTL], it's requested again. If request fails, outdated cached user is returned and request is
+// retried later (after TTL, but at most in a minute), error is returned only if bot user was never cached.
+func (b *Bot) Me(ctx context.Context) (*User, error) {
+	b.meMutex.Lock()
+	if b.me != nil && (b.meTTL <= 0 || time.Since(b.meUpdated) < b.meTTL || time.Now().Before(b.meRetryAt)) {
+		me := *b.me
+		b.meMutex.Unlock()
+		return &me, nil
+	}
+	b.meMutex.Unlock()
+
+	me, err := b.RefreshMe(ctx)
+	if err == nil {
+		return me, nil
+	}
+
+	b.meMutex.Lock()
+	if b.me == nil {
+		b.meMutex.Unlock()
+		return nil, err
+	}
+	cached := *b.me
+	b.meRetryAt = time.Now().Add(min(b.meTTL, meRetryInterval))
+	b.meMutex.Unlock()
+
+	b.logger().WarnContext(ctx, "Error on refresh me, using cached bot user", slog.Any(logKeyError, err))
+	return &cached, nil
+}
+
+// RefreshMe requests bot user using [Bot.GetMe] and updates cached one used by [Bot.Me]
+func (b *Bot) RefreshMe(ctx context.Context) (*User, error) {
+	requested := time.Now()
+	me, err := b.GetMe(ctx)
 	if err != nil {
-		b.log.Errorf("Error on get me: %s", err)
-	} else {
-		b.myID = me.ID
-		b.myUsername = me.Username
+		return nil, err
 	}
+
+	b.meMutex.Lock()
+	defer b.meMutex.Unlock()
+
+	// Keep bot user from the request that was made later
+	if b.me == nil || !requested.Before(b.meUpdated) {
+		cached := *me
+		b.me = &cached
+		b.meUpdated = requested
+		b.meRetryAt = time.Time{}
+	}
+	return me, nil
 }
 
-// ID returns bot ID by calling [Bot.GetMe] method once, if error occurs ID will be 0
+// ID returns bot ID using [Bot.Me], if bot user was never cached and error occurs, ID will be 0 and bot user will be
+// requested again on the next call
 func (b *Bot) ID() int64 {
-	b.myOnce.Do(b.updateMe)
-	return b.myID
+	me, err := b.Me(context.Background())
+	if err != nil {
+		b.logger().Error("Error on get me", slog.Any(logKeyError, err))
+		return 0
+	}
+	return me.ID
 }
 
-// Username returns bot username by calling [Bot.GetMe] method once, if error occurs username will be empty
+// Username returns bot username using [Bot.Me], if bot user was never cached and error occurs, username will be empty
+// and bot user will be requested again on the next call
 func (b *Bot) Username() string {
-	b.myOnce.Do(b.updateMe)
-	return b.myUsername
+	me, err := b.Me(context.Background())
+	if err != nil {
+		b.logger().Error("Error on get me", slog.Any(logKeyError, err))
+		return ""
+	}
+	return me.Username
 }
 
-// FileDownloadURL returns URL that can be used to download a file by its file path retrieved from [Bot.GetFile] method
+// FileDownloadURL returns URL that can be used to download a file by its file path retrieved from [Bot.GetFile] method,
+// if file is accessible locally (see [Bot.LocalFilePath]), file:// URI of the file is returned, such files can be read
+// using [Bot.DownloadFile] or [Bot.OpenLocalFile], but not by HTTP clients.
+// Note: Local Bot API server doesn't serve files over HTTP, so to download files from it, set [WithLocalFileDirectory]
+// option and use [Bot.DownloadFile] or [Bot.OpenLocalFile].
 func (b *Bot) FileDownloadURL(filepath string) string {
+	if localPath, ok := b.LocalFilePath(filepath); ok {
+		return FileURIScheme + localPath
+	}
+
 	if b.useTestServerPath {
 		return b.apiURL + "/file/bot" + b.token + "/test/" + filepath
 	}
 	return b.apiURL + "/file/bot" + b.token + "/" + filepath
 }
 
-// performRequest executes and parses response of method
+// performRequest executes and parses response of method reporting it to instrumentation
 func (b *Bot) performRequest(ctx context.Context, methodName string, parameters any, vs ...any) error {
-	response, err := b.constructAndCallRequest(ctx, methodName, parameters)
+	ctx = ta.WithDefaultCallOptions(ctx, b.methodCallOptions[methodName]...)
+
+	instrumentation := b.Instrumentation()
+	ctx, span := instrumentation.StartSpan(ctx, tm.SpanAPIPrefix+methodName,
+		tm.L(tm.AttributeRPCSystem, tm.RPCSystemTelegram), tm.L(tm.AttributeRPCMethod, methodName))
+	defer span.End()
+
+	start := time.Now()
+	err := b.executeRequest(ctx, methodName, parameters, vs...)
+
+	instrumentation.ObserveHistogram(tm.MetricAPIRequestDuration, time.Since(start).Seconds(),
+		tm.L(tm.LabelMethod, methodName))
+	if err == nil {
+		instrumentation.AddCounter(tm.MetricAPIRequests, 1,
+			tm.L(tm.LabelMethod, methodName), tm.L(tm.LabelStatus, tm.StatusOK))
+		return nil
+	}
+
+	errorCode := tm.ErrorCodeInternal
+	var apiErr *ta.Error
+	if errors.As(err, &apiErr) {
+		errorCode = strconv.Itoa(apiErr.ErrorCode)
+	}
+
+	instrumentation.AddCounter(tm.MetricAPIRequests, 1,
+		tm.L(tm.LabelMethod, methodName), tm.L(tm.LabelStatus, tm.StatusError))
+	instrumentation.AddCounter(tm.MetricAPIErrors, 1,
+		tm.L(tm.LabelMethod, methodName), tm.L(tm.LabelErrorCode, errorCode))
+	span.RecordError(err)
+
+	return err
+}
+
+// executeRequest executes and parses response of method
+func (b *Bot) executeRequest(ctx context.Context, methodName string, parameters any, vs ...any) error {
+	response, err := b.interceptedCall(ctx, methodName, parameters)
 	if err != nil {
-		b.log.Errorf("Execution error %s: %s", methodName, err)
-		return fmt.Errorf("internal execution: %w", err)
+		b.logger().ErrorContext(ctx, "Execution error", slog.String(logKeyMethod, methodName),
+			slog.Any(logKeyError, err))
+		return &MethodError{Method: methodName, Err: fmt.Errorf("internal execution: %w", err)}
+	}
+	if response == nil {
+		b.logger().ErrorContext(ctx, "Execution error", slog.String(logKeyMethod, methodName),
+			slog.String(logKeyError, "interceptor returned nil response"))
+		return &MethodError{Method: methodName, Err: errors.New("interceptor returned nil response")}
+	}
+	if log := b.logger(); log.Enabled(ctx, slog.LevelDebug) {
+		log.DebugContext(ctx, "API response",
+			slog.String(logKeyMethod, methodName), slog.String(logKeyResponse, response.String()))
 	}
-	b.log.Debugf("API response %s: %s", methodName, response.String())
 
 	if !response.Ok {
-		return fmt.Errorf("api: %w", response.Error)
+		return &MethodError{Method: methodName, Err: fmt.Errorf("api: %w", response.Error)}
 	}
 
 	if response.Result != nil {
@@ -186,17 +343,32 @@ func (b *Bot) performRequest(ctx context.Context, methodName string, parameters
 		}
 
 		if unmarshalErr != nil {
-			return fmt.Errorf("unmarshal to %s: %w", reflect.TypeOf(vs[len(vs)-1]), unmarshalErr)
+			return &MethodError{
+				Method: methodName,
+				Err:    fmt.Errorf("unmarshal to %s: %w", reflect.TypeOf(vs[len(vs)-1]), unmarshalErr),
+			}
 		}
 	}
 
 	if b.reportWarningAsErrors && response.Error != nil {
-		return response.Error
+		return &MethodError{Method: methodName, Err: fmt.Errorf("api warning: %w", response.Error)}
 	}
 
 	return nil
 }
 
+// interceptedCall executes API call through all interceptors, first interceptor is the outermost one
+func (b *Bot) interceptedCall(ctx context.Context, methodName string, parameters any) (*ta.Response, error) {
+	call := APICall(b.constructAndCallRequestWithMigration)
+	for i := len(b.interceptors) - 1; i >= 0; i-- {
+		interceptor, next := b.interceptors[i], call
+		call = func(ctx context.Context, methodName string, parameters any) (*ta.Response, error) {
+			return interceptor(ctx, methodName, parameters, next)
+		}
+	}
+	return call(ctx, methodName, parameters)
+}
+
 // constructAndCallRequest creates and executes request with parsing of parameters
 func (b *Bot) constructAndCallRequest(ctx context.Context, methodName string, parameters any) (*ta.Response, error) {
 	var data *ta.RequestData
@@ -204,6 +376,10 @@ func (b *Bot) constructAndCallRequest(ctx context.Context, methodName string, pa
 
 	filesParams, hasFiles := filesParameters(parameters)
 	if hasFiles { //nolint:nestif
+		if progress := uploadProgressFromContext(ctx); progress != nil {
+			filesParams = trackUploadProgress(filesParams, progress)
+		}
+
 		parsedParameters, err := parseParameters(parameters)
 		if err != nil {
 			return nil, fmt.Errorf("parsing parameters: %w", err)
@@ -233,18 +409,23 @@ func (b *Bot) constructAndCallRequest(ctx context.Context, methodName string, pa
 		defer func() { _ = closer.Close() }() //nolint:errcheck
 	}
 
+	apiURL := b.apiURL
+	if apiServer := ta.CallOptionsFromContext(ctx).APIServer; apiServer != "" {
+		apiURL = apiServer
+	}
+
 	var url string
 	if b.useTestServerPath {
-		url = b.apiURL + botPathPrefix + b.token + "/test/" + methodName
+		url = apiURL + botPathPrefix + b.token + "/test/" + methodName
 	} else {
-		url = b.apiURL + botPathPrefix + b.token + "/" + methodName
+		url = apiURL + botPathPrefix + b.token + "/" + methodName
 	}
 
 	if b.debugMode {
 		debugData := strings.TrimSuffix(debug.String(), "\n")
-		b.log.Debugf("API call to: %q, with data: %s", url, debugData)
+		b.logger().DebugContext(ctx, "API call", slog.String(logKeyURL, url), slog.String(logKeyData, debugData))
 	} else {
-		b.log.Debugf("API call to: %q", url)
+		b.logger().DebugContext(ctx, "API call", slog.String(logKeyURL, url))
 	}
 
 	response, err := b.api.Call(ctx, url, data)
@@ -257,13 +438,21 @@ func (b *Bot) constructAndCallRequest(ctx context.Context, methodName string, pa
 
 // filesParameters gets all files from parameters
 func filesParameters(parameters any) (files map[string]ta.NamedReader, hasFiles bool) {
-	if parametersWithFiles, ok := parameters.(fileCompatible); ok && !isNil(parameters) {
+	if isNil(parameters) {
+		return nil, false
+	}
+
+	switch parametersWithFiles := parameters.(type) {
+	case fileCompatible:
 		files = parametersWithFiles.fileParameters()
-		for _, file := range files {
-			if !isNil(file) {
-				hasFiles = true
-				break
-			}
+	case ParametersWithFiles:
+		files = parametersWithFiles.FileParameters()
+	}
+
+	for _, file := range files {
+		if !isNil(file) {
+			hasFiles = true
+			break
 		}
 	}
 	return files, hasFiles
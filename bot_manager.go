@@ -0,0 +1,362 @@
+package telego
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// DefaultBotManagerBasePath default base path of bot manager webhook requests
+const DefaultBotManagerBasePath = "/bot/"
+
+// ErrBotNotManaged returned when bot with specified key is not registered in the bot manager
+var ErrBotNotManaged = errors.New("telego: bot not managed")
+
+// BotManager manages multiple bots that share one HTTP client and one webhook listener, bots can be added, started,
+// stopped and removed at runtime
+//
+// Incoming webhook requests are routed to bots by path segment (base path followed by bot key) or, if path has no
+// bot key, by secret token header, in both cases secret token header must be equal to [Bot.SecretToken] of the bot
+type BotManager struct {
+	basePath string
+	client   *http.Client
+
+	mutex    sync.RWMutex
+	bots     map[string]*managedBot
+	bySecret map[string]*managedBot
+}
+
+// managedBot represents bot registered in the bot manager
+type managedBot struct {
+	key         string
+	bot         *Bot
+	secretToken string
+
+	mutex sync.RWMutex
+	run   *managedRun
+}
+
+// managedRun represents single run of receiving updates via webhook
+type managedRun struct {
+	handler WebhookHandler
+	calls   sync.WaitGroup
+	cancel  context.CancelFunc
+}
+
+// stop stops receiving updates and waits for in-flight webhook calls, that return as soon as updates are stopped
+func (r *managedRun) stop() {
+	r.cancel()
+	r.calls.Wait()
+}
+
+// BotManagerOption represents an option that can be applied to bot manager
+type BotManagerOption func(manager *BotManager) error
+
+// WithBotManagerBasePath sets base path of webhook requests, bot key is appended to it. Default is "/bot/".
+func WithBotManagerBasePath(basePath string) BotManagerOption {
+	return func(manager *BotManager) error {
+		if !strings.HasPrefix(basePath, "/") {
+			return errors.New("base path should start with /")
+		}
+		if !strings.HasSuffix(basePath, "/") {
+			basePath += "/"
+		}
+		manager.basePath = basePath
+		return nil
+	}
+}
+
+// WithBotManagerHTTPClient sets HTTP client shared by all bots created by [BotManager.NewBot]
+func WithBotManagerHTTPClient(client *http.Client) BotManagerOption {
+	return func(manager *BotManager) error {
+		if client == nil {
+			return errors.New("nil http client")
+		}
+		manager.client = client
+		return nil
+	}
+}
+
+// NewBotManager creates new bot manager with options applied
+func NewBotManager(options ...BotManagerOption) (*BotManager, error) {
+	manager := &BotManager{
+		basePath: DefaultBotManagerBasePath,
+		client:   &http.Client{},
+		bots:     make(map[string]*managedBot),
+		bySecret: make(map[string]*managedBot),
+	}
+
+	for _, option := range options {
+		if err := option(manager); err != nil {
+			return nil, fmt.Errorf("telego: bot manager options: %w", err)
+		}
+	}
+
+	return manager, nil
+}
+
+// NewBot creates new bot using shared HTTP client and adds it to the manager with specified key, bot options are
+// applied after shared client, so they can override it
+func (m *BotManager) NewBot(key, token string, options ...BotOption) (*Bot, error) {
+	bot, err := NewBot(token, append([]BotOption{WithHTTPClient(m.client)}, options...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = m.Add(key, bot); err != nil {
+		return nil, err
+	}
+
+	return bot, nil
+}
+
+// Add adds existing bot to the manager with specified key, key is used as path segment of webhook URL
+func (m *BotManager) Add(key string, bot *Bot) error {
+	if key == "" || strings.Contains(key, "/") {
+		return fmt.Errorf("telego: bot manager: invalid bot key %q", key)
+	}
+	if bot == nil {
+		return errors.New("telego: bot manager: nil bot")
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, ok := m.bots[key]; ok {
+		return fmt.Errorf("telego: bot manager: bot with key %q already added", key)
+	}
+
+	secretToken := bot.SecretToken()
+	if existing, ok := m.bySecret[secretToken]; ok {
+		return fmt.Errorf("telego: bot manager: bot already added with key %q", existing.key)
+	}
+
+	mb := &managedBot{
+		key:         key,
+		bot:         bot,
+		secretToken: secretToken,
+	}
+	m.bots[key] = mb
+	m.bySecret[secretToken] = mb
+
+	return nil
+}
+
+// Remove stops and removes bot from the manager
+func (m *BotManager) Remove(key string) error {
+	m.mutex.Lock()
+	mb, ok := m.bots[key]
+	if ok {
+		delete(m.bots, key)
+		delete(m.bySecret, mb.secretToken)
+	}
+	m.mutex.Unlock()
+
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrBotNotManaged, key)
+	}
+
+	mb.stop()
+	return nil
+}
+
+// Bot returns bot by its key
+func (m *BotManager) Bot(key string) (*Bot, bool) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	mb, ok := m.bots[key]
+	if !ok {
+		return nil, false
+	}
+	return mb.bot, true
+}
+
+// Keys returns sorted keys of all managed bots
+func (m *BotManager) Keys() []string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	keys := make([]string, 0, len(m.bots))
+	for key := range m.bots {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
+
+// WebhookPath returns path of webhook requests for bot with specified key, it should be used as a path of
+// [SetWebhookParams.URL]
+func (m *BotManager) WebhookPath(key string) string {
+	return m.basePath + key
+}
+
+// Start starts receiving updates via webhook for bot with specified key, updates chan is closed once context is
+// done or bot is stopped
+// Note: Webhook is not set on Telegram side, use [WithWebhookSet] option or [Bot.SetWebhook] method to set it, secret
+// token must be [Bot.SecretToken], as requests without it are rejected
+func (m *BotManager) Start(ctx context.Context, key string, options ...WebhookOption) (<-chan Update, error) {
+	mb, ok := m.managed(key)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrBotNotManaged, key)
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	run := &managedRun{cancel: cancel}
+	updates, err := mb.bot.UpdatesViaWebhook(runCtx, func(handler WebhookHandler) error {
+		run.handler = handler
+		mb.mutex.Lock()
+		mb.run = run
+		mb.mutex.Unlock()
+		return nil
+	}, options...)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	go func() {
+		<-runCtx.Done()
+		mb.stopRun(run)
+	}()
+
+	return updates, nil
+}
+
+// Stop stops receiving updates for bot with specified key, does nothing if bot is not running, blocks until in-flight
+// webhook requests are done, requests waiting for space in updates chan are rejected
+func (m *BotManager) Stop(key string) error {
+	mb, ok := m.managed(key)
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrBotNotManaged, key)
+	}
+
+	mb.stop()
+	return nil
+}
+
+// StopAll stops receiving updates for all bots, blocks until in-flight webhook requests are done
+func (m *BotManager) StopAll() {
+	m.mutex.RLock()
+	bots := make([]*managedBot, 0, len(m.bots))
+	for _, mb := range m.bots {
+		bots = append(bots, mb)
+	}
+	m.mutex.RUnlock()
+
+	for _, mb := range bots {
+		mb.stop()
+	}
+}
+
+// managed returns managed bot by its key
+func (m *BotManager) managed(key string) (*managedBot, bool) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	mb, ok := m.bots[key]
+	return mb, ok
+}
+
+// route finds managed bot for request by path segment or secret token, if bot is found by path, secret token must
+// match too, returns status code to respond with if bot is not found
+func (m *BotManager) route(path, secretToken string) (*managedBot, int) {
+	key, ok := strings.CutPrefix(path, m.basePath)
+	if !ok {
+		if path+"/" != m.basePath {
+			return nil, http.StatusNotFound
+		}
+		key = ""
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	if key == "" {
+		mb, found := m.bySecret[secretToken]
+		if !found {
+			return nil, http.StatusUnauthorized
+		}
+		return mb, http.StatusOK
+	}
+
+	mb, found := m.bots[key]
+	if !found {
+		return nil, http.StatusNotFound
+	}
+	if secretToken != mb.secretToken {
+		return nil, http.StatusUnauthorized
+	}
+
+	return mb, http.StatusOK
+}
+
+// ServeHTTP routes webhook request to the bot, responds with not found if bot is not found or not started
+func (m *BotManager) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	defer func() { _ = request.Body.Close() }() //nolint:errcheck
+
+	if request.Method != http.MethodPost {
+		writer.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	mb, status := m.route(request.URL.Path, request.Header.Get(WebhookSecretTokenHeader))
+	if mb == nil {
+		writer.WriteHeader(status)
+		return
+	}
+
+	mb.mutex.RLock()
+	run := mb.run
+	if run != nil {
+		run.calls.Add(1)
+	}
+	mb.mutex.RUnlock()
+	if run == nil {
+		writer.WriteHeader(http.StatusNotFound)
+		return
+	}
+	defer run.calls.Done()
+
+	data, err := io.ReadAll(request.Body)
+	if err != nil {
+		writer.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if err = run.handler(request.Context(), data); err != nil {
+		writer.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+}
+
+// stop stops receiving updates, blocks until in-flight webhook calls are done
+func (mb *managedBot) stop() {
+	mb.mutex.Lock()
+	run := mb.run
+	mb.run = nil
+	mb.mutex.Unlock()
+
+	if run != nil {
+		run.stop()
+	}
+}
+
+// stopRun stops receiving updates if run is still the current one
+func (mb *managedBot) stopRun(run *managedRun) {
+	mb.mutex.Lock()
+	if mb.run == run {
+		mb.run = nil
+	}
+	mb.mutex.Unlock()
+
+	run.stop()
+}
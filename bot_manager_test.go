@@ -0,0 +1,251 @@
+package telego
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const validToken2 = "1234567891:aaaabbbbaaaabbbbaaaabbbbaaaabbbbccc"
+
+func TestNewBotManager(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		client := &http.Client{}
+		manager, err := NewBotManager(WithBotManagerBasePath("/hook"), WithBotManagerHTTPClient(client))
+		require.NoError(t, err)
+		assert.Equal(t, "/hook/", manager.basePath)
+		assert.Equal(t, client, manager.client)
+		assert.Equal(t, "/hook/test", manager.WebhookPath("test"))
+	})
+
+	t.Run("error_base_path", func(t *testing.T) {
+		_, err := NewBotManager(WithBotManagerBasePath("hook"))
+		require.Error(t, err)
+	})
+
+	t.Run("error_client", func(t *testing.T) {
+		_, err := NewBotManager(WithBotManagerHTTPClient(nil))
+		require.Error(t, err)
+	})
+}
+
+func TestBotManager_Add(t *testing.T) {
+	manager, err := NewBotManager()
+	require.NoError(t, err)
+
+	bot1, err := manager.NewBot("one", validToken, WithDiscardLogger())
+	require.NoError(t, err)
+
+	bot2, err := NewBot(validToken2, WithDiscardLogger())
+	require.NoError(t, err)
+	require.NoError(t, manager.Add("two", bot2))
+
+	bot, ok := manager.Bot("one")
+	assert.True(t, ok)
+	assert.Equal(t, bot1, bot)
+	assert.Equal(t, []string{"one", "two"}, manager.Keys())
+
+	require.Error(t, manager.Add("", bot2))
+	require.Error(t, manager.Add("a/b", bot2))
+	require.Error(t, manager.Add("three", nil))
+	require.Error(t, manager.Add("two", bot1))
+	require.Error(t, manager.Add("three", bot1))
+
+	_, err = manager.NewBot("four", invalidToken)
+	require.Error(t, err)
+
+	require.NoError(t, manager.Remove("one"))
+	require.ErrorIs(t, manager.Remove("one"), ErrBotNotManaged)
+	_, ok = manager.Bot("one")
+	assert.False(t, ok)
+	assert.Equal(t, []string{"two"}, manager.Keys())
+}
+
+func TestBotManager_ServeHTTP(t *testing.T) {
+	manager, err := NewBotManager()
+	require.NoError(t, err)
+
+	bot1, err := manager.NewBot("one", validToken, WithDiscardLogger())
+	require.NoError(t, err)
+	bot2, err := manager.NewBot("two", validToken2, WithDiscardLogger())
+	require.NoError(t, err)
+
+	updates1, err := manager.Start(t.Context(), "one")
+	require.NoError(t, err)
+	updates2, err := manager.Start(t.Context(), "two")
+	require.NoError(t, err)
+
+	_, err = manager.Start(t.Context(), "one")
+	require.Error(t, err)
+	_, err = manager.Start(t.Context(), "three")
+	require.ErrorIs(t, err, ErrBotNotManaged)
+
+	serve := func(method, path, secretToken, body string) int {
+		request := httptest.NewRequest(method, path, strings.NewReader(body))
+		if secretToken != "" {
+			request.Header.Set(WebhookSecretTokenHeader, secretToken)
+		}
+		recorder := httptest.NewRecorder()
+		manager.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	t.Run("by_path", func(t *testing.T) {
+		code := serve(http.MethodPost, "/bot/one", bot1.SecretToken(), `{"update_id": 1}`)
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, 1, (<-updates1).UpdateID)
+	})
+
+	t.Run("by_secret_token", func(t *testing.T) {
+		code := serve(http.MethodPost, "/bot/", bot2.SecretToken(), `{"update_id": 2}`)
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, 2, (<-updates2).UpdateID)
+
+		code = serve(http.MethodPost, "/bot", bot2.SecretToken(), `{"update_id": 3}`)
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, 3, (<-updates2).UpdateID)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		assert.Equal(t, http.StatusMethodNotAllowed, serve(http.MethodGet, "/bot/one", bot1.SecretToken(), ""))
+		assert.Equal(t, http.StatusNotFound, serve(http.MethodPost, "/other", bot1.SecretToken(), "{}"))
+		assert.Equal(t, http.StatusNotFound, serve(http.MethodPost, "/bot/three", bot1.SecretToken(), "{}"))
+		assert.Equal(t, http.StatusUnauthorized, serve(http.MethodPost, "/bot/one", bot2.SecretToken(), "{}"))
+		assert.Equal(t, http.StatusUnauthorized, serve(http.MethodPost, "/bot/one", "", "{}"))
+		assert.Equal(t, http.StatusUnauthorized, serve(http.MethodPost, "/bot/", "secret", "{}"))
+		assert.Equal(t, http.StatusInternalServerError, serve(http.MethodPost, "/bot/one", bot1.SecretToken(), "{"))
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		require.NoError(t, manager.Stop("one"))
+		require.ErrorIs(t, manager.Stop("three"), ErrBotNotManaged)
+
+		_, ok := <-updates1
+		assert.False(t, ok)
+		assert.Equal(t, http.StatusNotFound, serve(http.MethodPost, "/bot/one", bot1.SecretToken(), "{}"))
+
+		require.Eventually(t, func() bool { return bot1.running.Load() == runningNone }, time.Second, time.Millisecond)
+
+		updates1, err = manager.Start(t.Context(), "one")
+		require.NoError(t, err)
+		code := serve(http.MethodPost, "/bot/one", bot1.SecretToken(), `{"update_id": 4}`)
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, 4, (<-updates1).UpdateID)
+	})
+
+	t.Run("stop_all", func(t *testing.T) {
+		manager.StopAll()
+
+		_, ok := <-updates1
+		assert.False(t, ok)
+		_, ok = <-updates2
+		assert.False(t, ok)
+	})
+}
+
+func TestBotManager_StopWhileServing(t *testing.T) {
+	manager, err := NewBotManager()
+	require.NoError(t, err)
+
+	bot, err := manager.NewBot("one", validToken, WithDiscardLogger())
+	require.NoError(t, err)
+
+	serve := func() {
+		request := httptest.NewRequest(http.MethodPost, "/bot/one", strings.NewReader(`{"update_id": 1}`))
+		request.Header.Set(WebhookSecretTokenHeader, bot.SecretToken())
+		manager.ServeHTTP(httptest.NewRecorder(), request)
+	}
+
+	run := func(t *testing.T, stop func(cancel context.CancelFunc)) {
+		t.Helper()
+
+		ctx, cancel := context.WithCancel(t.Context())
+		defer cancel()
+
+		updates, err := manager.Start(ctx, "one")
+		require.NoError(t, err)
+
+		drained := make(chan struct{})
+		go func() {
+			defer close(drained)
+			for range updates {
+				// Drain updates until chan is closed
+			}
+		}()
+
+		var wg sync.WaitGroup
+		for range 8 {
+			wg.Go(func() {
+				for range 50 {
+					serve()
+				}
+			})
+		}
+
+		stop(cancel)
+		wg.Wait()
+		<-drained
+
+		require.Eventually(t, func() bool { return bot.running.Load() == runningNone }, time.Second, time.Millisecond)
+	}
+
+	t.Run("stop", func(t *testing.T) {
+		run(t, func(context.CancelFunc) {
+			require.NoError(t, manager.Stop("one"))
+		})
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		run(t, func(cancel context.CancelFunc) {
+			cancel()
+		})
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		run(t, func(context.CancelFunc) {
+			require.NoError(t, manager.Remove("one"))
+		})
+	})
+}
+
+func TestBotManager_StopBlockedRequest(t *testing.T) {
+	manager, err := NewBotManager()
+	require.NoError(t, err)
+
+	bot, err := manager.NewBot("one", validToken, WithDiscardLogger())
+	require.NoError(t, err)
+
+	// Nobody reads updates, so request is blocked until bot is stopped
+	_, err = manager.Start(t.Context(), "one", WithWebhookBuffer(0))
+	require.NoError(t, err)
+
+	served := make(chan int)
+	go func() {
+		request := httptest.NewRequest(http.MethodPost, "/bot/one", strings.NewReader(`{"update_id": 1}`))
+		request.Header.Set(WebhookSecretTokenHeader, bot.SecretToken())
+		recorder := httptest.NewRecorder()
+		manager.ServeHTTP(recorder, request)
+		served <- recorder.Code
+	}()
+	time.Sleep(time.Millisecond * 10)
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		assert.NoError(t, manager.Stop("one"))
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		require.FailNow(t, "stop is blocked by in-flight request")
+	}
+	assert.NotEqual(t, http.StatusOK, <-served)
+}
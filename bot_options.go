@@ -3,13 +3,16 @@ package telego
 import (
 	"context"
 	"errors"
+	"log/slog"
 	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/valyala/fasthttp"
 
 	ta "github.com/mymmrac/telego/telegoapi"
+	tm "github.com/mymmrac/telego/telegometrics"
 )
 
 // WithAPICaller sets a custom API caller to use
@@ -56,6 +59,7 @@ func WithDefaultLogger(debugMode, printErrors bool) BotOption {
 			Replacer:    defaultReplacer(bot.Token()),
 		}
 		bot.log = log
+		bot.structuredLog = newPrintfLogger(log)
 		bot.debugMode = debugMode
 		return nil
 	}
@@ -73,6 +77,7 @@ func WithExtendedDefaultLogger(debugMode, printErrors bool, replacer *strings.Re
 			Replacer:    replacer,
 		}
 		bot.log = log
+		bot.structuredLog = newPrintfLogger(log)
 		bot.debugMode = debugMode
 		return nil
 	}
@@ -95,6 +100,26 @@ func WithDiscardLogger() BotOption {
 func WithLogger(log Logger) BotOption {
 	return func(bot *Bot) error {
 		bot.log = log
+		bot.structuredLog = newPrintfLogger(log)
+		return nil
+	}
+}
+
+// WithSlogLogger sets structured logger to use, bot token is redacted from all messages and string attributes.
+// Redefines existing loggers. [Bot.Logger] will return printf-style logger that writes to the structured logger.
+// Note: Debug level logs will include API responses and (in debug mode) request parameters, which may contain
+// sensitive information.
+func WithSlogLogger(logger *slog.Logger) BotOption {
+	return func(bot *Bot) error {
+		if logger == nil {
+			return errors.New("nil slog logger")
+		}
+
+		bot.structuredLog = slog.New(redactHandler{
+			handler:  logger.Handler(),
+			replacer: defaultReplacer(bot.Token()),
+		})
+		bot.log = slogLogger{logger: bot.structuredLog}
 		return nil
 	}
 }
@@ -130,17 +155,48 @@ func WithTestServerPath() BotOption {
 	}
 }
 
-// WithHealthCheck enables health check using the [Bot.GetMe] method on the start
+// WithHealthCheck enables health check using the [Bot.GetMe] method on the start, bot user returned by [Bot.Me] is
+// preloaded, so bot creation fails fast if it can't be retrieved
+// Note: Should be specified after options that change how API is called (like [WithAPICaller] or [WithAPIServer])
 func WithHealthCheck(ctx context.Context) BotOption {
 	return func(bot *Bot) error {
-		me, err := bot.GetMe(ctx)
-		if err != nil {
-			return err
+		_, err := bot.RefreshMe(ctx)
+		return err
+	}
+}
+
+// WithMeTTL sets for how long bot user returned by [Bot.Me] is cached, zero or negative TTL means forever (default)
+func WithMeTTL(ttl time.Duration) BotOption {
+	return func(bot *Bot) error {
+		bot.meTTL = ttl
+		return nil
+	}
+}
+
+// WithInterceptors adds interceptors that will be called on every API method call in order they are specified
+// (the first one is the outermost), can be used multiple times
+func WithInterceptors(interceptors ...Interceptor) BotOption {
+	return func(bot *Bot) error {
+		for _, interceptor := range interceptors {
+			if interceptor == nil {
+				return errors.New("nil interceptor")
+			}
 		}
 
-		bot.myOnce.Do(func() {})
-		bot.myID = me.ID
-		bot.myUsername = me.Username
+		bot.interceptors = append(bot.interceptors, interceptors...)
+		return nil
+	}
+}
+
+// WithInstrumentation sets instrumentation used to report metrics and traces of API calls, received updates and
+// bot handlers
+func WithInstrumentation(instrumentation tm.Instrumentation) BotOption {
+	return func(bot *Bot) error {
+		if instrumentation == nil {
+			return errors.New("nil instrumentation")
+		}
+
+		bot.instrumentation = instrumentation
 		return nil
 	}
 }
@@ -153,3 +209,19 @@ func WithWarnings() BotOption {
 		return nil
 	}
 }
+
+// WithMethodCallOptions sets default options of API calls to the method, options attached to the context using
+// [WithCallOptions] override them, can be used multiple times
+func WithMethodCallOptions(methodName string, options ...CallOption) BotOption {
+	return func(bot *Bot) error {
+		if methodName == "" {
+			return errors.New("empty method name")
+		}
+
+		if bot.methodCallOptions == nil {
+			bot.methodCallOptions = make(map[string][]CallOption)
+		}
+		bot.methodCallOptions[methodName] = append(bot.methodCallOptions[methodName], options...)
+		return nil
+	}
+}
@@ -1,10 +1,13 @@
 package telego
 
 import (
+	"bytes"
 	"context"
+	"log/slog"
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -14,6 +17,7 @@ import (
 	"github.com/mymmrac/telego/internal/json"
 	ta "github.com/mymmrac/telego/telegoapi"
 	mockapi "github.com/mymmrac/telego/telegoapi/mock"
+	tm "github.com/mymmrac/telego/telegometrics"
 )
 
 type testCallerType struct{}
@@ -143,6 +147,33 @@ func TestWithLogger(t *testing.T) {
 	assert.EqualValues(t, log, bot.log)
 }
 
+func TestWithSlogLogger(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		bot := &Bot{token: validToken}
+		buffer := &bytes.Buffer{}
+
+		err := WithSlogLogger(slog.New(slog.NewTextHandler(buffer, nil)))(bot)
+		require.NoError(t, err)
+		require.NotNil(t, bot.structuredLog)
+		assert.Equal(t, bot.structuredLog, bot.logger())
+
+		bot.Logger().Errorf("Token %s", validToken)
+		assert.Contains(t, buffer.String(), "Token "+DefaultLoggerTokenReplacement)
+		assert.NotContains(t, buffer.String(), validToken)
+
+		log := &testLoggerType{}
+		err = WithLogger(log)(bot)
+		require.NoError(t, err)
+		assert.Equal(t, printfHandler{log: log}, bot.structuredLog.Handler())
+		assert.Same(t, bot.structuredLog, bot.logger())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		err := WithSlogLogger(nil)(&Bot{})
+		require.Error(t, err)
+	})
+}
+
 func TestWithAPIServer(t *testing.T) {
 	bot := &Bot{}
 
@@ -223,6 +254,15 @@ func TestWithHealthCheck(t *testing.T) {
 	)
 	require.NoError(t, err)
 	require.NotNil(t, bot)
+	assert.NotNil(t, bot.me)
+}
+
+func TestWithMeTTL(t *testing.T) {
+	bot := &Bot{}
+
+	err := WithMeTTL(time.Minute)(bot)
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, bot.meTTL)
 }
 
 func TestWithWarnings(t *testing.T) {
@@ -233,3 +273,47 @@ func TestWithWarnings(t *testing.T) {
 
 	assert.True(t, bot.reportWarningAsErrors)
 }
+
+func TestWithInterceptors(t *testing.T) {
+	bot := &Bot{}
+	interceptor := func(ctx context.Context, methodName string, parameters any, next APICall) (*ta.Response, error) {
+		return next(ctx, methodName, parameters)
+	}
+
+	err := WithInterceptors(interceptor, interceptor)(bot)
+	require.NoError(t, err)
+	assert.Len(t, bot.interceptors, 2)
+
+	err = WithInterceptors(interceptor)(bot)
+	require.NoError(t, err)
+	assert.Len(t, bot.interceptors, 3)
+
+	err = WithInterceptors(nil)(bot)
+	require.Error(t, err)
+}
+
+func TestWithInstrumentation(t *testing.T) {
+	bot := &Bot{}
+	assert.Equal(t, tm.Nop{}, bot.Instrumentation())
+
+	exporter := tm.NewPrometheusExporter()
+	err := WithInstrumentation(exporter)(bot)
+	require.NoError(t, err)
+	assert.Equal(t, exporter, bot.Instrumentation())
+
+	err = WithInstrumentation(nil)(bot)
+	require.Error(t, err)
+}
+
+func TestWithMethodCallOptions(t *testing.T) {
+	bot := &Bot{}
+
+	err := WithMethodCallOptions("sendVideo", CallTimeout(time.Minute))(bot)
+	require.NoError(t, err)
+	err = WithMethodCallOptions("sendVideo", NoRetry())(bot)
+	require.NoError(t, err)
+	assert.Len(t, bot.methodCallOptions["sendVideo"], 2)
+
+	err = WithMethodCallOptions("")(bot)
+	require.Error(t, err)
+}
@@ -2,11 +2,14 @@ package telego
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"errors"
+	"math"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -15,6 +18,7 @@ import (
 	"github.com/mymmrac/telego/internal/json"
 	ta "github.com/mymmrac/telego/telegoapi"
 	mockapi "github.com/mymmrac/telego/telegoapi/mock"
+	tm "github.com/mymmrac/telego/telegometrics"
 )
 
 const (
@@ -195,6 +199,17 @@ func TestBot_FileDownloadURL(t *testing.T) {
 		url := bot.FileDownloadURL(filepath)
 		assert.Equal(t, bot.apiURL+"/file"+botPathPrefix+bot.token+"/test/"+filepath, url)
 	})
+	t.Run("local", func(t *testing.T) {
+		bot, err := NewBot(validToken, WithLocalAPIServer("http://localhost:8081",
+			WithLocalFileDirectory("/var/lib/bot-api", "/mnt/data")))
+		require.NoError(t, err)
+
+		url := bot.FileDownloadURL("/var/lib/bot-api/file.txt")
+		assert.Equal(t, "file:///mnt/data/file.txt", url)
+
+		url = bot.FileDownloadURL("file.txt")
+		assert.Equal(t, "http://localhost:8081/file"+botPathPrefix+bot.token+"/file.txt", url)
+	})
 }
 
 type testErrorMarshal struct {
@@ -597,11 +612,17 @@ func TestBot_performRequest(t *testing.T) {
 			Return(&ta.Response{
 				Ok:     false,
 				Result: nil,
-				Error:  &ta.Error{},
+				Error:  &ta.Error{ErrorCode: 403, Description: "Forbidden: bot was blocked by the user"},
 			}, nil)
 
 		err := m.Bot.performRequest(t.Context(), methodName, params, &result)
 		require.Error(t, err)
+		require.ErrorIs(t, err, ta.ErrBotBlocked)
+
+		var methodErr *MethodError
+		require.ErrorAs(t, err, &methodErr)
+		assert.Equal(t, methodName, methodErr.Method)
+		assert.Equal(t, "api: 403 \"Forbidden: bot was blocked by the user\"", methodErr.Error())
 	})
 
 	t.Run("error_construct_and_call", func(t *testing.T) {
@@ -653,7 +674,13 @@ func TestBot_performRequest(t *testing.T) {
 			}, nil)
 
 		err := m.Bot.performRequest(t.Context(), methodName, params, &result)
-		assert.Equal(t, &ta.Error{ErrorCode: 1}, err)
+		var methodErr *MethodError
+		require.ErrorAs(t, err, &methodErr)
+		assert.Equal(t, methodName, methodErr.Method)
+
+		var apiErr *ta.Error
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, &ta.Error{ErrorCode: 1}, apiErr)
 		assert.Equal(t, 1, result)
 	})
 }
@@ -723,6 +750,160 @@ func TestBot_ID_and_Username(t *testing.T) {
 	assert.Equal(t, int64(123), id)
 }
 
+func TestBot_ID_and_Username_error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	m := newMockedBot(ctrl)
+
+	m.MockRequestConstructor.EXPECT().
+		JSONRequest(nil).
+		Return(&ta.RequestData{}, nil).
+		Times(3)
+	m.MockAPICaller.EXPECT().
+		Call(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, errTest).
+		Times(2)
+	m.MockAPICaller.EXPECT().
+		Call(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(telegoResponse(t, &User{
+			ID:       123,
+			Username: "test",
+		}), nil)
+
+	assert.Zero(t, m.Bot.ID())
+	assert.Empty(t, m.Bot.Username())
+	assert.Equal(t, int64(123), m.Bot.ID())
+}
+
+func TestBot_Me(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	m := newMockedBot(ctrl)
+
+	expectMe := func(user *User) {
+		m.MockRequestConstructor.EXPECT().
+			JSONRequest(nil).
+			Return(&ta.RequestData{}, nil)
+		m.MockAPICaller.EXPECT().
+			Call(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(telegoResponse(t, user), nil)
+	}
+
+	expectMe(&User{ID: 123, IsBot: true, CanJoinGroups: true})
+
+	me, err := m.Bot.Me(t.Context())
+	require.NoError(t, err)
+	assert.True(t, me.CanJoinGroups)
+
+	me.ID = 0
+	me, err = m.Bot.Me(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, int64(123), me.ID)
+
+	expectMe(&User{ID: 123, IsBot: true, SupportsInlineQueries: true})
+
+	me, err = m.Bot.RefreshMe(t.Context())
+	require.NoError(t, err)
+	assert.True(t, me.SupportsInlineQueries)
+
+	me, err = m.Bot.Me(t.Context())
+	require.NoError(t, err)
+	assert.False(t, me.CanJoinGroups)
+
+	t.Run("ttl", func(t *testing.T) {
+		m.Bot.meTTL = time.Millisecond
+		time.Sleep(time.Millisecond * 2)
+
+		expectMe(&User{ID: 321})
+
+		me, err = m.Bot.Me(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, int64(321), me.ID)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		m.MockRequestConstructor.EXPECT().
+			JSONRequest(nil).
+			Return(nil, errTest)
+
+		me, err = m.Bot.RefreshMe(t.Context())
+		require.ErrorIs(t, err, errTest)
+		assert.Nil(t, me)
+		assert.Equal(t, int64(321), m.Bot.me.ID)
+	})
+
+	t.Run("refresh_in_progress", func(t *testing.T) {
+		m.Bot.meTTL = 0
+		started := make(chan struct{})
+		release := make(chan struct{})
+
+		m.MockRequestConstructor.EXPECT().
+			JSONRequest(nil).
+			Return(&ta.RequestData{}, nil)
+		m.MockAPICaller.EXPECT().
+			Call(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(context.Context, string, *ta.RequestData) (*ta.Response, error) {
+				close(started)
+				<-release
+				return telegoResponse(t, &User{ID: 456}), nil
+			})
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_, refreshErr := m.Bot.RefreshMe(t.Context())
+			assert.NoError(t, refreshErr)
+		}()
+		<-started
+
+		me, err = m.Bot.Me(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, int64(321), me.ID)
+
+		close(release)
+		<-done
+
+		me, err = m.Bot.Me(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, int64(456), me.ID)
+	})
+
+	t.Run("refresh_error", func(t *testing.T) {
+		m.Bot.meTTL = time.Millisecond
+		time.Sleep(time.Millisecond * 2)
+
+		m.MockRequestConstructor.EXPECT().
+			JSONRequest(nil).
+			Return(nil, errTest)
+
+		me, err = m.Bot.Me(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, int64(456), me.ID)
+		assert.False(t, m.Bot.meRetryAt.IsZero())
+
+		// Not requested again until retry
+		m.Bot.meRetryAt = time.Now().Add(time.Hour)
+		time.Sleep(time.Millisecond * 2)
+		assert.Equal(t, int64(456), m.Bot.ID())
+
+		m.Bot.meRetryAt = time.Time{}
+		expectMe(&User{ID: 789})
+
+		me, err = m.Bot.Me(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, int64(789), me.ID)
+	})
+
+	t.Run("never_cached", func(t *testing.T) {
+		bot := newMockedBot(ctrl)
+		bot.MockRequestConstructor.EXPECT().
+			JSONRequest(nil).
+			Return(nil, errTest)
+
+		me, err = bot.Bot.Me(t.Context())
+		require.ErrorIs(t, err, errTest)
+		assert.Nil(t, me)
+	})
+}
+
 func Test_logRequestWithFiles(t *testing.T) {
 	debug := &strings.Builder{}
 	parameters := map[string]string{
@@ -747,3 +928,101 @@ func Test_logRequest(t *testing.T) {
 	logRequest(debug, parameters)
 	assert.Equal(t, `parameters: {"foo":"bar"}`, debug.String())
 }
+
+func TestBot_interceptedCall(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	m := newMockedBot(ctrl)
+
+	var order []string
+	m.Bot.interceptors = []Interceptor{
+		func(ctx context.Context, methodName string, parameters any, next APICall) (*ta.Response, error) {
+			order = append(order, "first:"+methodName)
+			resp, err := next(ctx, methodName, parameters)
+			order = append(order, "first:done")
+			return resp, err
+		},
+		func(ctx context.Context, methodName string, _ any, next APICall) (*ta.Response, error) {
+			order = append(order, "second:"+methodName)
+			if methodName == "short" {
+				return emptyResp, nil
+			}
+			return next(ctx, methodName, &SendMessageParams{Text: "rewritten"})
+		},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		order = nil
+
+		m.MockRequestConstructor.EXPECT().
+			JSONRequest(&SendMessageParams{Text: "rewritten"}).
+			Return(data, nil)
+
+		m.MockAPICaller.EXPECT().
+			Call(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(emptyResp, nil)
+
+		resp, err := m.Bot.interceptedCall(t.Context(), methodName, &SendMessageParams{})
+		require.NoError(t, err)
+		assert.Equal(t, emptyResp, resp)
+		assert.Equal(t, []string{"first:" + methodName, "second:" + methodName, "first:done"}, order)
+	})
+
+	t.Run("short_circuit", func(t *testing.T) {
+		order = nil
+
+		resp, err := m.Bot.interceptedCall(t.Context(), "short", &SendMessageParams{})
+		require.NoError(t, err)
+		assert.Equal(t, emptyResp, resp)
+		assert.Equal(t, []string{"first:short", "second:short", "first:done"}, order)
+	})
+}
+
+func TestBot_executeRequest_nilResponse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	m := newMockedBot(ctrl)
+
+	m.Bot.interceptors = []Interceptor{
+		func(context.Context, string, any, APICall) (*ta.Response, error) {
+			return nil, nil //nolint:nilnil
+		},
+	}
+
+	err := m.Bot.executeRequest(t.Context(), methodName, nil)
+	var methodErr *MethodError
+	require.ErrorAs(t, err, &methodErr)
+	assert.Equal(t, methodName, methodErr.Method)
+	assert.ErrorContains(t, err, "interceptor returned nil response")
+}
+
+func TestBot_performRequest_instrumentation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	m := newMockedBot(ctrl)
+
+	exporter := tm.NewPrometheusExporter(math.Inf(1))
+	m.Bot.instrumentation = exporter
+
+	m.MockRequestConstructor.EXPECT().
+		JSONRequest(gomock.Any()).
+		Return(data, nil).
+		Times(2)
+
+	m.MockAPICaller.EXPECT().
+		Call(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(emptyResp, nil)
+
+	m.MockAPICaller.EXPECT().
+		Call(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&ta.Response{Ok: false, Error: &ta.Error{ErrorCode: 400}}, nil)
+
+	require.NoError(t, m.Bot.performRequest(t.Context(), methodName, nil))
+	require.Error(t, m.Bot.performRequest(t.Context(), methodName, nil))
+
+	text := &strings.Builder{}
+	_, err := exporter.WriteTo(text)
+	require.NoError(t, err)
+
+	assert.Contains(t, text.String(), `telego_api_requests_total{method="testMethod",status="ok"} 1`)
+	assert.Contains(t, text.String(), `telego_api_requests_total{method="testMethod",status="error"} 1`)
+	assert.Contains(t, text.String(), `telego_api_errors_total{error_code="400",method="testMethod"} 1`)
+	assert.Contains(t, text.String(), `telego_api_request_duration_seconds_count{method="testMethod"} 2`)
+}
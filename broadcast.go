@@ -0,0 +1,451 @@
+package telego
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"log/slog"
+	"sync"
+	"time"
+
+	ta "github.com/mymmrac/telego/telegoapi"
+)
+
+const (
+	defaultBroadcastConcurrency       = 4
+	defaultBroadcastFloodWaitAttempts = 3
+	defaultBroadcastCheckpointEvery   = 100
+)
+
+// DefaultBroadcastRate default rate of broadcast messages, slightly lower than Telegram's limit for bulk notifications
+var DefaultBroadcastRate = ta.RateLimit{Count: 25, Interval: time.Second}
+
+// BroadcastStatus represents outcome of sending broadcast message to one recipient
+type BroadcastStatus string
+
+// Broadcast statuses
+const (
+	// BroadcastSent message was sent
+	BroadcastSent BroadcastStatus = "sent"
+	// BroadcastBlocked recipient blocked the bot, deleted account, or bot can't write to the chat
+	BroadcastBlocked BroadcastStatus = "blocked"
+	// BroadcastNotFound recipient chat not found
+	BroadcastNotFound BroadcastStatus = "not_found"
+	// BroadcastFailed message was not sent because of other error
+	BroadcastFailed BroadcastStatus = "failed"
+)
+
+// BroadcastResult represents outcome of sending broadcast message to one recipient
+type BroadcastResult struct {
+	// Index of the recipient in recipients sequence
+	Index int
+	// ChatID of the recipient
+	ChatID ChatID
+	// Status of sending
+	Status BroadcastStatus
+	// Err error of sending, nil if message was sent
+	Err error
+}
+
+// BroadcastStats represents number of recipients by outcome
+type BroadcastStats struct {
+	Sent     int
+	Blocked  int
+	NotFound int
+	Failed   int
+}
+
+// add counts result status
+func (s *BroadcastStats) add(status BroadcastStatus) {
+	switch status {
+	case BroadcastSent:
+		s.Sent++
+	case BroadcastBlocked:
+		s.Blocked++
+	case BroadcastNotFound:
+		s.NotFound++
+	case BroadcastFailed:
+		s.Failed++
+	}
+}
+
+// BroadcastMessage sends broadcast message to the recipient
+type BroadcastMessage func(ctx context.Context, bot *Bot, chatID ChatID) error
+
+// BroadcastText returns broadcast message that sends text message using provided parameters as a template
+func BroadcastText(params SendMessageParams) BroadcastMessage {
+	return func(ctx context.Context, bot *Bot, chatID ChatID) error {
+		p := params
+		p.ChatID = chatID
+		_, err := bot.SendMessage(ctx, &p)
+		return err
+	}
+}
+
+// BroadcastCopy returns broadcast message that copies message from chat
+func BroadcastCopy(fromChatID ChatID, messageID int) BroadcastMessage {
+	return func(ctx context.Context, bot *Bot, chatID ChatID) error {
+		_, err := bot.CopyMessage(ctx, &CopyMessageParams{
+			ChatID:     chatID,
+			FromChatID: fromChatID,
+			MessageID:  messageID,
+		})
+		return err
+	}
+}
+
+// BroadcastStore represents storage of broadcast progress, implementations must be safe for concurrent use
+type BroadcastStore interface {
+	// LoadCheckpoint returns number of recipients (from the start of recipients sequence) that were already
+	// processed in campaign, zero if campaign is new
+	LoadCheckpoint(ctx context.Context, campaignID string) (int, error)
+	// SaveCheckpoint saves number of processed recipients of campaign
+	SaveCheckpoint(ctx context.Context, campaignID string, processed int) error
+	// SaveResult saves outcome of sending message to one recipient of campaign
+	SaveResult(ctx context.Context, campaignID string, result BroadcastResult) error
+}
+
+// MemoryBroadcastStore is an in-memory implementation of [BroadcastStore]
+type MemoryBroadcastStore struct {
+	mutex       sync.Mutex
+	checkpoints map[string]int
+	results     map[string][]BroadcastResult
+}
+
+// NewMemoryBroadcastStore creates new in-memory broadcast store
+func NewMemoryBroadcastStore() *MemoryBroadcastStore {
+	return &MemoryBroadcastStore{
+		checkpoints: make(map[string]int),
+		results:     make(map[string][]BroadcastResult),
+	}
+}
+
+// LoadCheckpoint returns number of processed recipients of campaign
+func (s *MemoryBroadcastStore) LoadCheckpoint(_ context.Context, campaignID string) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.checkpoints[campaignID], nil
+}
+
+// SaveCheckpoint saves number of processed recipients of campaign
+func (s *MemoryBroadcastStore) SaveCheckpoint(_ context.Context, campaignID string, processed int) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.checkpoints[campaignID] = processed
+	return nil
+}
+
+// SaveResult saves outcome of sending message to one recipient of campaign
+func (s *MemoryBroadcastStore) SaveResult(_ context.Context, campaignID string, result BroadcastResult) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.results[campaignID] = append(s.results[campaignID], result)
+	return nil
+}
+
+// Results returns saved results of campaign in order they were saved
+func (s *MemoryBroadcastStore) Results(campaignID string) []BroadcastResult {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]BroadcastResult(nil), s.results[campaignID]...)
+}
+
+// Broadcast represents campaign of sending the same message to many recipients with rate limiting, handling of
+// flood control, and resumable progress
+//
+// Progress is saved as a checkpoint: number of recipients from the start of the sequence that are all processed.
+// After restart, campaign with the same ID skips already processed recipients, so recipients sequence must be
+// stable between runs. Recipients processed after the last checkpoint (at most concurrency number plus checkpoint
+// interval) may receive message again after restart.
+type Broadcast struct {
+	bot        *Bot
+	campaignID string
+	recipients iter.Seq[ChatID]
+	message    BroadcastMessage
+
+	rate              ta.RateLimit
+	concurrency       int
+	floodWaitAttempts int
+	checkpointEvery   int
+	store             BroadcastStore
+	onResult          func(result BroadcastResult)
+
+	mutex      sync.Mutex
+	pauseUntil time.Time
+}
+
+// BroadcastOption represents an option that can be applied to broadcast
+type BroadcastOption func(b *Broadcast) error
+
+// WithBroadcastRate sets max rate of sending messages. Default is [DefaultBroadcastRate].
+func WithBroadcastRate(rate ta.RateLimit) BroadcastOption {
+	return func(b *Broadcast) error {
+		if rate.Count <= 0 || rate.Interval <= 0 {
+			return fmt.Errorf("invalid rate: %d per %s", rate.Count, rate.Interval)
+		}
+		b.rate = rate
+		return nil
+	}
+}
+
+// WithBroadcastConcurrency sets the max number of messages sent in parallel. Default is 4.
+func WithBroadcastConcurrency(concurrency int) BroadcastOption {
+	return func(b *Broadcast) error {
+		if concurrency <= 0 {
+			return fmt.Errorf("concurrency is not positive: %d", concurrency)
+		}
+		b.concurrency = concurrency
+		return nil
+	}
+}
+
+// WithBroadcastFloodWaitAttempts sets the max number of times message will be retried after hitting flood control,
+// zero disables retries. Default is 3.
+func WithBroadcastFloodWaitAttempts(attempts int) BroadcastOption {
+	return func(b *Broadcast) error {
+		if attempts < 0 {
+			return fmt.Errorf("flood wait attempts is negative: %d", attempts)
+		}
+		b.floodWaitAttempts = attempts
+		return nil
+	}
+}
+
+// WithBroadcastStore sets store used to save progress. Default is in-memory store.
+func WithBroadcastStore(store BroadcastStore) BroadcastOption {
+	return func(b *Broadcast) error {
+		if store == nil {
+			return errors.New("nil store")
+		}
+		b.store = store
+		return nil
+	}
+}
+
+// WithBroadcastCheckpointInterval sets number of processed recipients after which checkpoint is saved, checkpoint
+// is always saved when broadcast stops. Default is 100.
+func WithBroadcastCheckpointInterval(every int) BroadcastOption {
+	return func(b *Broadcast) error {
+		if every <= 0 {
+			return fmt.Errorf("checkpoint interval is not positive: %d", every)
+		}
+		b.checkpointEvery = every
+		return nil
+	}
+}
+
+// WithBroadcastResultHandler sets handler called for each result, handler is called sequentially
+func WithBroadcastResultHandler(handler func(result BroadcastResult)) BroadcastOption {
+	return func(b *Broadcast) error {
+		b.onResult = handler
+		return nil
+	}
+}
+
+// NewBroadcast creates new broadcast campaign, campaign ID is used to save and resume progress
+func NewBroadcast(
+	bot *Bot, campaignID string, recipients iter.Seq[ChatID], message BroadcastMessage, options ...BroadcastOption,
+) (*Broadcast, error) {
+	if campaignID == "" {
+		return nil, errors.New("telego: broadcast: empty campaign ID")
+	}
+	if recipients == nil || message == nil {
+		return nil, errors.New("telego: broadcast: nil recipients or message")
+	}
+
+	b := &Broadcast{
+		bot:               bot,
+		campaignID:        campaignID,
+		recipients:        recipients,
+		message:           message,
+		rate:              DefaultBroadcastRate,
+		concurrency:       defaultBroadcastConcurrency,
+		floodWaitAttempts: defaultBroadcastFloodWaitAttempts,
+		checkpointEvery:   defaultBroadcastCheckpointEvery,
+		store:             NewMemoryBroadcastStore(),
+	}
+
+	for _, option := range options {
+		if err := option(b); err != nil {
+			return nil, fmt.Errorf("telego: broadcast options: %w", err)
+		}
+	}
+
+	return b, nil
+}
+
+// broadcastJob represents message to be sent to one recipient
+type broadcastJob struct {
+	index  int
+	chatID ChatID
+}
+
+// Run sends message to all recipients that were not processed before and returns stats of this run, if context is
+// done before all recipients are processed, progress is saved and context error is returned
+func (b *Broadcast) Run(ctx context.Context) (BroadcastStats, error) {
+	var stats BroadcastStats
+
+	processed, err := b.store.LoadCheckpoint(ctx, b.campaignID)
+	if err != nil {
+		return stats, fmt.Errorf("telego: broadcast: load checkpoint: %w", err)
+	}
+
+	jobs := make(chan broadcastJob)
+	results := make(chan BroadcastResult)
+
+	go b.produce(ctx, processed, jobs)
+
+	wg := sync.WaitGroup{}
+	for range b.concurrency {
+		wg.Go(func() {
+			for job := range jobs {
+				result, ok := b.send(ctx, job)
+				if ok {
+					results <- result
+				}
+			}
+		})
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	storeCtx := context.WithoutCancel(ctx)
+	done := make(map[int]bool)
+	lastSaved := processed
+	for result := range results {
+		stats.add(result.Status)
+		if b.onResult != nil {
+			b.onResult(result)
+		}
+		if err = b.store.SaveResult(storeCtx, b.campaignID, result); err != nil {
+			b.bot.logger().ErrorContext(ctx, "Saving broadcast result",
+				slog.String(logKeyCampaign, b.campaignID), slog.Any(logKeyError, err))
+		}
+
+		done[result.Index] = true
+		for done[processed] {
+			delete(done, processed)
+			processed++
+		}
+
+		if processed-lastSaved >= b.checkpointEvery {
+			if err = b.store.SaveCheckpoint(storeCtx, b.campaignID, processed); err != nil {
+				b.bot.logger().ErrorContext(ctx, "Saving broadcast checkpoint",
+					slog.String(logKeyCampaign, b.campaignID), slog.Any(logKeyError, err))
+			}
+			lastSaved = processed
+		}
+	}
+
+	if err = b.store.SaveCheckpoint(storeCtx, b.campaignID, processed); err != nil {
+		return stats, fmt.Errorf("telego: broadcast: save checkpoint: %w", err)
+	}
+
+	if ctx.Err() != nil {
+		return stats, fmt.Errorf("telego: broadcast: %w", ctx.Err())
+	}
+	return stats, nil
+}
+
+// produce sends recipients starting from offset to jobs chan respecting rate and flood control pauses
+func (b *Broadcast) produce(ctx context.Context, offset int, jobs chan<- broadcastJob) {
+	defer close(jobs)
+
+	interval := b.rate.Interval / time.Duration(b.rate.Count)
+	next := time.Now()
+
+	index := 0
+	for chatID := range b.recipients {
+		if index < offset {
+			index++
+			continue
+		}
+
+		b.mutex.Lock()
+		if b.pauseUntil.After(next) {
+			next = b.pauseUntil
+		}
+		b.mutex.Unlock()
+
+		if !sleepContext(ctx, time.Until(next)) {
+			return
+		}
+		next = time.Now().Add(interval)
+
+		select {
+		case <-ctx.Done():
+			return
+		case jobs <- broadcastJob{index: index, chatID: chatID}:
+		}
+		index++
+	}
+}
+
+// send sends message to recipient retrying after flood control, false is returned if context was done before
+// message was processed
+func (b *Broadcast) send(ctx context.Context, job broadcastJob) (BroadcastResult, bool) {
+	result := BroadcastResult{
+		Index:  job.index,
+		ChatID: job.chatID,
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := b.message(ctx, b.bot, job.chatID)
+		if ctx.Err() != nil {
+			return result, false
+		}
+
+		retryAfter, ok := ta.RetryAfter(err)
+		if ok && attempt < b.floodWaitAttempts {
+			b.mutex.Lock()
+			if pauseUntil := time.Now().Add(retryAfter); pauseUntil.After(b.pauseUntil) {
+				b.pauseUntil = pauseUntil
+			}
+			b.mutex.Unlock()
+
+			if !sleepContext(ctx, retryAfter) {
+				return result, false
+			}
+			continue
+		}
+
+		result.Status = broadcastStatus(err)
+		result.Err = err
+		return result, true
+	}
+}
+
+// broadcastStatus returns status of sending by error
+func broadcastStatus(err error) BroadcastStatus {
+	switch {
+	case err == nil:
+		return BroadcastSent
+	case errors.Is(err, ta.ErrBotBlocked), errors.Is(err, ta.ErrBotKicked), errors.Is(err, ta.ErrBotNotMember),
+		errors.Is(err, ta.ErrUserDeactivated), errors.Is(err, ta.ErrCantInitiateConversation):
+		return BroadcastBlocked
+	case errors.Is(err, ta.ErrChatNotFound), errors.Is(err, ta.ErrUserNotFound):
+		return BroadcastNotFound
+	default:
+		return BroadcastFailed
+	}
+}
+
+// sleepContext sleeps for duration, false is returned if context was done before
+func sleepContext(ctx context.Context, duration time.Duration) bool {
+	if duration <= 0 {
+		return ctx.Err() == nil
+	}
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,239 @@
+package telego
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	ta "github.com/mymmrac/telego/telegoapi"
+)
+
+var testBroadcastRate = ta.RateLimit{Count: 1000, Interval: time.Second}
+
+func testRecipients(count int) func(yield func(ChatID) bool) {
+	return func(yield func(ChatID) bool) {
+		for i := range count {
+			if !yield(ChatID{ID: int64(i)}) {
+				return
+			}
+		}
+	}
+}
+
+func TestNewBroadcast(t *testing.T) {
+	message := func(context.Context, *Bot, ChatID) error { return nil }
+
+	t.Run("success", func(t *testing.T) {
+		store := NewMemoryBroadcastStore()
+		b, err := NewBroadcast(&Bot{}, "test", testRecipients(1), message,
+			WithBroadcastRate(testBroadcastRate),
+			WithBroadcastConcurrency(2),
+			WithBroadcastFloodWaitAttempts(0),
+			WithBroadcastStore(store),
+			WithBroadcastCheckpointInterval(10),
+			WithBroadcastResultHandler(func(BroadcastResult) {}),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, testBroadcastRate, b.rate)
+		assert.Equal(t, 2, b.concurrency)
+		assert.Equal(t, 0, b.floodWaitAttempts)
+		assert.Equal(t, store, b.store)
+		assert.Equal(t, 10, b.checkpointEvery)
+		assert.NotNil(t, b.onResult)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := NewBroadcast(&Bot{}, "", testRecipients(1), message)
+		require.Error(t, err)
+
+		_, err = NewBroadcast(&Bot{}, "test", nil, message)
+		require.Error(t, err)
+
+		options := []BroadcastOption{
+			WithBroadcastRate(ta.RateLimit{}),
+			WithBroadcastConcurrency(0),
+			WithBroadcastFloodWaitAttempts(-1),
+			WithBroadcastStore(nil),
+			WithBroadcastCheckpointInterval(0),
+		}
+		for _, option := range options {
+			_, err = NewBroadcast(&Bot{}, "test", testRecipients(1), message, option)
+			require.Error(t, err)
+		}
+	})
+}
+
+func TestBroadcast_Run(t *testing.T) {
+	blocked := &ta.Error{ErrorCode: 403, Description: "Forbidden: bot was blocked by the user"}
+	notFound := &ta.Error{ErrorCode: 400, Description: "Bad Request: chat not found"}
+	flood := &ta.Error{ErrorCode: 429, Description: "Too Many Requests", Parameters: &ta.ResponseParameters{}}
+
+	t.Run("success", func(t *testing.T) {
+		var mutex sync.Mutex
+		attempts := make(map[int64]int)
+
+		message := func(_ context.Context, _ *Bot, chatID ChatID) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			attempts[chatID.ID]++
+			switch chatID.ID {
+			case 1:
+				return blocked
+			case 2:
+				return notFound
+			case 3:
+				return errTest
+			case 4:
+				if attempts[chatID.ID] == 1 {
+					return flood
+				}
+			}
+			return nil
+		}
+
+		store := NewMemoryBroadcastStore()
+		b, err := NewBroadcast(&Bot{}, "test", testRecipients(6), message,
+			WithBroadcastRate(testBroadcastRate), WithBroadcastStore(store))
+		require.NoError(t, err)
+
+		stats, err := b.Run(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, BroadcastStats{Sent: 3, Blocked: 1, NotFound: 1, Failed: 1}, stats)
+		assert.Equal(t, 2, attempts[4])
+
+		checkpoint, err := store.LoadCheckpoint(t.Context(), "test")
+		require.NoError(t, err)
+		assert.Equal(t, 6, checkpoint)
+
+		results := store.Results("test")
+		slices.SortFunc(results, func(a, b BroadcastResult) int { return a.Index - b.Index })
+		require.Len(t, results, 6)
+		assert.Equal(t, BroadcastBlocked, results[1].Status)
+		assert.Equal(t, BroadcastNotFound, results[2].Status)
+		assert.Equal(t, BroadcastFailed, results[3].Status)
+		assert.ErrorIs(t, results[3].Err, errTest)
+		assert.Equal(t, BroadcastSent, results[4].Status)
+		assert.NoError(t, results[4].Err)
+
+		stats, err = b.Run(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, BroadcastStats{}, stats)
+	})
+
+	t.Run("resume", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+
+		var sent []int64
+		message := func(ctx context.Context, _ *Bot, chatID ChatID) error {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			sent = append(sent, chatID.ID)
+			if chatID.ID == 3 {
+				cancel()
+			}
+			return nil
+		}
+
+		store := NewMemoryBroadcastStore()
+		b, err := NewBroadcast(&Bot{}, "test", testRecipients(10), message,
+			WithBroadcastRate(testBroadcastRate), WithBroadcastStore(store), WithBroadcastConcurrency(1))
+		require.NoError(t, err)
+
+		stats, err := b.Run(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 3, stats.Sent)
+
+		checkpoint, err := store.LoadCheckpoint(t.Context(), "test")
+		require.NoError(t, err)
+		assert.Equal(t, 3, checkpoint)
+
+		stats, err = b.Run(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, 7, stats.Sent)
+		assert.Equal(t, []int64{0, 1, 2, 3, 3, 4, 5, 6, 7, 8, 9}, sent)
+	})
+
+	t.Run("rate", func(t *testing.T) {
+		b, err := NewBroadcast(&Bot{}, "test", testRecipients(5),
+			func(context.Context, *Bot, ChatID) error { return nil },
+			WithBroadcastRate(ta.RateLimit{Count: 1, Interval: 10 * time.Millisecond}))
+		require.NoError(t, err)
+
+		start := time.Now()
+		stats, err := b.Run(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, 5, stats.Sent)
+		assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+	})
+
+	t.Run("error_store", func(t *testing.T) {
+		b, err := NewBroadcast(&Bot{}, "test", testRecipients(1),
+			func(context.Context, *Bot, ChatID) error { return nil },
+			WithBroadcastStore(errorBroadcastStore{}))
+		require.NoError(t, err)
+
+		_, err = b.Run(t.Context())
+		require.Error(t, err)
+	})
+}
+
+type errorBroadcastStore struct{}
+
+func (errorBroadcastStore) LoadCheckpoint(context.Context, string) (int, error) {
+	return 0, errTest
+}
+
+func (errorBroadcastStore) SaveCheckpoint(context.Context, string, int) error {
+	return errTest
+}
+
+func (errorBroadcastStore) SaveResult(context.Context, string, BroadcastResult) error {
+	return errTest
+}
+
+func TestBroadcastText(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	m := newMockedBot(ctrl)
+
+	m.MockRequestConstructor.EXPECT().
+		JSONRequest(&SendMessageParams{ChatID: ChatID{ID: 1}, Text: "text"}).
+		Return(data, nil)
+	m.MockAPICaller.EXPECT().
+		Call(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(telegoResponse(t, expectedMessage), nil)
+
+	err := BroadcastText(SendMessageParams{Text: "text"})(t.Context(), m.Bot, ChatID{ID: 1})
+	require.NoError(t, err)
+}
+
+func TestBroadcastCopy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	m := newMockedBot(ctrl)
+
+	m.MockRequestConstructor.EXPECT().
+		JSONRequest(&CopyMessageParams{ChatID: ChatID{ID: 1}, FromChatID: ChatID{ID: 2}, MessageID: 3}).
+		Return(nil, errTest)
+
+	err := BroadcastCopy(ChatID{ID: 2}, 3)(t.Context(), m.Bot, ChatID{ID: 1})
+	require.ErrorIs(t, err, errTest)
+}
+
+func Test_broadcastStatus(t *testing.T) {
+	assert.Equal(t, BroadcastSent, broadcastStatus(nil))
+	assert.Equal(t, BroadcastBlocked, broadcastStatus(&ta.Error{
+		ErrorCode: 403, Description: "Forbidden: user is deactivated",
+	}))
+	assert.Equal(t, BroadcastNotFound, broadcastStatus(&ta.Error{
+		ErrorCode: 400, Description: "Bad Request: user not found",
+	}))
+	assert.Equal(t, BroadcastFailed, broadcastStatus(errTest))
+}
@@ -0,0 +1,38 @@
+package telego
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mymmrac/telego/internal/json"
+	ta "github.com/mymmrac/telego/telegoapi"
+)
+
+// ParametersWithFiles represents custom method parameters that contain files, such parameters are sent as multipart
+// request, so they must be a pointer to struct with `json` tags on all fields (file fields can use [InputFile])
+type ParametersWithFiles interface {
+	// FileParameters returns files to upload with field names as keys
+	FileParameters() map[string]ta.NamedReader
+}
+
+// Call calls any Telegram method (including ones not yet generated or provided by custom Bot API server) and
+// unmarshals its result into T, parameters are sent the same way as for generated methods (JSON or multipart if
+// parameters have files, see [ParametersWithFiles]), nil parameters are allowed for methods without parameters
+func Call[T any](ctx context.Context, bot *Bot, methodName string, params any) (T, error) {
+	var result T
+	if methodName == "" {
+		return result, errors.New("telego: call: empty method name")
+	}
+
+	if err := bot.performRequest(ctx, methodName, params, &result); err != nil {
+		return result, fmt.Errorf("telego: %s: %w", methodName, err)
+	}
+
+	return result, nil
+}
+
+// CallRaw calls any Telegram method the same way as [Call] and returns raw JSON result
+func CallRaw(ctx context.Context, bot *Bot, methodName string, params any) (json.RawMessage, error) {
+	return Call[json.RawMessage](ctx, bot, methodName, params)
+}
@@ -0,0 +1,53 @@
+package telego
+
+import (
+	"context"
+	"time"
+
+	ta "github.com/mymmrac/telego/telegoapi"
+)
+
+// CallOption represents an option of a single API call, see [WithCallOptions]
+type CallOption = ta.CallOption
+
+// CallPriority represents priority of API call, see [ta.CallPriority]
+type CallPriority = ta.CallPriority
+
+// Call priorities, see [ta.CallPriority]
+const (
+	CallPriorityNormal = ta.CallPriorityNormal
+	CallPriorityLow    = ta.CallPriorityLow
+)
+
+// WithCallOptions returns context with options applied to API calls made with it, they override per method defaults
+// set by [WithMethodCallOptions]
+// Note: Timeout, retry and priority options are honored by [ta.FastHTTPCaller], [ta.HTTPCaller], [ta.RetryCaller]
+// and [ta.RateLimitCaller], custom callers can read them using [ta.CallOptionsFromContext]
+func WithCallOptions(ctx context.Context, options ...CallOption) context.Context {
+	return ta.WithCallOptions(ctx, options...)
+}
+
+// CallTimeout sets timeout of each HTTP request made by the call
+func CallTimeout(timeout time.Duration) CallOption {
+	return ta.CallTimeout(timeout)
+}
+
+// CallMaxAttempts sets max number of attempts made by [ta.RetryCaller]
+func CallMaxAttempts(maxAttempts int) CallOption {
+	return ta.CallMaxAttempts(maxAttempts)
+}
+
+// NoRetry disables retries made by [ta.RetryCaller]
+func NoRetry() CallOption {
+	return ta.NoRetry()
+}
+
+// Priority sets priority of the call used by [ta.RateLimitCaller]
+func Priority(priority CallPriority) CallOption {
+	return ta.Priority(priority)
+}
+
+// CallAPIServer sets bot API server URL used for the call instead of one set by [WithAPIServer]
+func CallAPIServer(apiURL string) CallOption {
+	return ta.CallAPIServer(apiURL)
+}
@@ -0,0 +1,57 @@
+package telego
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	ta "github.com/mymmrac/telego/telegoapi"
+)
+
+func TestWithCallOptions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	m := newMockedBot(ctrl)
+	require.NoError(t, WithMethodCallOptions("sendVideo", CallTimeout(time.Minute), NoRetry())(m.Bot))
+
+	m.MockRequestConstructor.EXPECT().
+		JSONRequest(gomock.Any()).
+		Return(data, nil)
+
+	m.MockAPICaller.EXPECT().
+		Call(gomock.Any(), "https://example.com"+botPathPrefix+validToken+"/sendVideo", data).
+		DoAndReturn(func(ctx context.Context, _ string, _ *ta.RequestData) (*ta.Response, error) {
+			assert.Equal(t, ta.CallOptions{
+				Timeout:     time.Second,
+				MaxAttempts: 1,
+				Priority:    CallPriorityLow,
+				APIServer:   "https://example.com",
+			}, ta.CallOptionsFromContext(ctx))
+			return telegoResponse(t, expectedMessage), nil
+		})
+
+	ctx := WithCallOptions(t.Context(),
+		CallTimeout(time.Second),
+		Priority(CallPriorityLow),
+		CallAPIServer("https://example.com"),
+	)
+	_, err := m.Bot.SendVideo(ctx, &SendVideoParams{})
+	require.NoError(t, err)
+
+	m.MockRequestConstructor.EXPECT().
+		JSONRequest(gomock.Any()).
+		Return(data, nil)
+
+	m.MockAPICaller.EXPECT().
+		Call(gomock.Any(), gomock.Any(), data).
+		DoAndReturn(func(ctx context.Context, _ string, _ *ta.RequestData) (*ta.Response, error) {
+			assert.Equal(t, ta.CallOptions{MaxAttempts: 5}, ta.CallOptionsFromContext(ctx))
+			return telegoResponse(t, true), nil
+		})
+
+	_, err = Call[bool](WithCallOptions(t.Context(), CallMaxAttempts(5)), m.Bot, "getMe", nil)
+	require.NoError(t, err)
+}
@@ -0,0 +1,106 @@
+package telego
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/mymmrac/telego/internal/json"
+	ta "github.com/mymmrac/telego/telegoapi"
+)
+
+type customParamsWithFile struct {
+	N    int       `json:"n"`
+	File InputFile `json:"file"`
+}
+
+func (p *customParamsWithFile) FileParameters() map[string]ta.NamedReader {
+	return map[string]ta.NamedReader{
+		"file": p.File.File,
+	}
+}
+
+func TestCall(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	m := newMockedBot(ctrl)
+
+	t.Run("success", func(t *testing.T) {
+		m.MockRequestConstructor.EXPECT().
+			JSONRequest(gomock.Any()).
+			Return(data, nil)
+
+		m.MockAPICaller.EXPECT().
+			Call(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(telegoResponse(t, expectedMessage), nil)
+
+		message, err := Call[*Message](t.Context(), m.Bot, "sendCustomMessage", map[string]any{"a": 1})
+		require.NoError(t, err)
+		assert.Equal(t, expectedMessage, message)
+	})
+
+	t.Run("success_multipart", func(t *testing.T) {
+		params := &customParamsWithFile{N: 1, File: InputFile{File: &testNamedReader{}}}
+
+		m.MockRequestConstructor.EXPECT().
+			MultipartRequest(map[string]string{"n": "1"}, params.FileParameters()).
+			Return(data, nil)
+
+		m.MockAPICaller.EXPECT().
+			Call(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(telegoResponse(t, true), nil)
+
+		ok, err := Call[bool](t.Context(), m.Bot, "uploadCustom", params)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		m.MockRequestConstructor.EXPECT().
+			JSONRequest(gomock.Any()).
+			Return(nil, errTest)
+
+		_, err := Call[bool](t.Context(), m.Bot, "customMethod", nil)
+		require.ErrorIs(t, err, errTest)
+		assert.Contains(t, err.Error(), "telego: customMethod:")
+	})
+
+	t.Run("error_unmarshal", func(t *testing.T) {
+		m.MockRequestConstructor.EXPECT().
+			JSONRequest(gomock.Any()).
+			Return(data, nil)
+
+		m.MockAPICaller.EXPECT().
+			Call(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(telegoResponse(t, expectedMessage), nil)
+
+		_, err := Call[int](t.Context(), m.Bot, "customMethod", nil)
+		require.Error(t, err)
+	})
+
+	t.Run("error_method_name", func(t *testing.T) {
+		_, err := Call[bool](t.Context(), m.Bot, "", nil)
+		require.Error(t, err)
+	})
+}
+
+func TestCallRaw(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	m := newMockedBot(ctrl)
+
+	m.MockRequestConstructor.EXPECT().
+		JSONRequest(gomock.Any()).
+		Return(data, nil)
+
+	m.MockAPICaller.EXPECT().
+		Call(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(telegoResponse(t, expectedMessage), nil)
+
+	result, err := CallRaw(t.Context(), m.Bot, "customMethod", nil)
+	require.NoError(t, err)
+
+	expected, err := json.Marshal(expectedMessage)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(expected), string(result))
+}
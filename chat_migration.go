@@ -0,0 +1,262 @@
+package telego
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"reflect"
+	"strings"
+	"sync"
+
+	ta "github.com/mymmrac/telego/telegoapi"
+)
+
+// Parameter names that can contain migrated chat ID
+const (
+	chatIDParameter     = "chat_id"
+	fromChatIDParameter = "from_chat_id"
+)
+
+// ChatMigrationHandler handles migration of the group to the supergroup
+type ChatMigrationHandler func(ctx context.Context, oldChatID, newChatID int64)
+
+// chatMigration represents known migrations of groups to supergroups
+type chatMigration struct {
+	handler ChatMigrationHandler
+
+	mutex sync.RWMutex
+	chats map[int64]int64
+}
+
+// WithChatMigration enables automatic handling of group to supergroup migration. If a request fails because the group
+// was migrated, handler (can be nil) is called with old and new chat IDs, the mapping is remembered, and request is
+// retried with the new chat ID. All later requests with old chat ID (in chat_id or from_chat_id parameters) will be
+// sent with the new chat ID.
+// Note: Requests with files are retried only if all files implement [io.Seeker] (like [os.File])
+func WithChatMigration(handler ChatMigrationHandler) BotOption {
+	return func(bot *Bot) error {
+		bot.ensureChatMigration().handler = handler
+		return nil
+	}
+}
+
+// WithKnownChatMigrations enables automatic handling of group to supergroup migration (see [WithChatMigration]) and
+// adds already known migrations from old to new chat IDs (for example, persisted by migration handler)
+func WithKnownChatMigrations(migrations map[int64]int64) BotOption {
+	return func(bot *Bot) error {
+		m := bot.ensureChatMigration()
+		for oldChatID, newChatID := range migrations {
+			m.chats[oldChatID] = newChatID
+		}
+		return nil
+	}
+}
+
+// ensureChatMigration enables chat migration if it's not enabled yet
+func (b *Bot) ensureChatMigration() *chatMigration {
+	if b.migration == nil {
+		b.migration = &chatMigration{
+			chats: make(map[int64]int64),
+		}
+	}
+	return b.migration
+}
+
+// MigratedChatID returns the supergroup chat ID that group was migrated to, only known if chat migration is enabled
+func (b *Bot) MigratedChatID(chatID int64) (int64, bool) {
+	if b.migration == nil {
+		return 0, false
+	}
+
+	b.migration.mutex.RLock()
+	defer b.migration.mutex.RUnlock()
+
+	newChatID, ok := b.migration.chats[chatID]
+	return newChatID, ok
+}
+
+// constructAndCallRequestWithMigration creates and executes request, if chat migration is enabled, old chat IDs are
+// replaced and request is retried if group was migrated
+func (b *Bot) constructAndCallRequestWithMigration(
+	ctx context.Context, methodName string, parameters any,
+) (*ta.Response, error) {
+	if b.migration == nil {
+		return b.constructAndCallRequest(ctx, methodName, parameters)
+	}
+
+	parameters = b.migration.rewrite(parameters)
+	response, err := b.constructAndCallRequest(ctx, methodName, parameters)
+	if err != nil || response.Ok {
+		return response, err
+	}
+
+	newChatID, ok := ta.MigrateToChatID(response.Error)
+	if !ok {
+		return response, nil
+	}
+
+	oldChatID, ok := parametersChatID(parameters)
+	if !ok || oldChatID == newChatID {
+		return response, nil
+	}
+
+	b.migration.mutex.Lock()
+	b.migration.chats[oldChatID] = newChatID
+	b.migration.mutex.Unlock()
+
+	b.logger().InfoContext(ctx, "Chat migrated",
+		slog.Int64(logKeyOldChatID, oldChatID), slog.Int64(logKeyNewChatID, newChatID))
+	if b.migration.handler != nil {
+		b.migration.handler(ctx, oldChatID, newChatID)
+	}
+
+	if !resetFiles(parameters) {
+		return response, nil
+	}
+
+	return b.constructAndCallRequest(ctx, methodName, b.migration.rewrite(parameters))
+}
+
+// rewrite returns a shallow copy of parameters with migrated chat IDs replaced, or the original parameters if nothing
+// to replace
+func (m *chatMigration) rewrite(parameters any) any {
+	paramsStruct, ok := parametersStruct(parameters)
+	if !ok {
+		return parameters
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	if len(m.chats) == 0 {
+		return parameters
+	}
+
+	var copied reflect.Value
+	for i := 0; i < paramsStruct.NumField(); i++ {
+		if !isChatIDField(paramsStruct.Type().Field(i)) {
+			continue
+		}
+
+		newChatID, found := m.chats[chatIDFieldValue(paramsStruct.Field(i))]
+		if !found {
+			continue
+		}
+
+		if !copied.IsValid() {
+			copied = reflect.New(paramsStruct.Type())
+			copied.Elem().Set(paramsStruct)
+		}
+		setChatIDField(copied.Elem().Field(i), newChatID)
+	}
+
+	if !copied.IsValid() {
+		return parameters
+	}
+	return copied.Interface()
+}
+
+// parametersStruct returns struct value of parameters, if parameters is a pointer to struct
+func parametersStruct(parameters any) (reflect.Value, bool) {
+	value := reflect.ValueOf(parameters)
+	if value.Kind() != reflect.Pointer || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	return value.Elem(), true
+}
+
+// parametersChatID returns chat ID from parameters that could cause migration error, chat_id is preferred over
+// from_chat_id
+func parametersChatID(parameters any) (int64, bool) {
+	paramsStruct, ok := parametersStruct(parameters)
+	if !ok {
+		return 0, false
+	}
+
+	var fromChatID int64
+	for i := 0; i < paramsStruct.NumField(); i++ {
+		field := paramsStruct.Type().Field(i)
+		if !isChatIDField(field) {
+			continue
+		}
+
+		chatID := chatIDFieldValue(paramsStruct.Field(i))
+		if chatID == 0 {
+			continue
+		}
+
+		if jsonFieldName(field) == chatIDParameter {
+			return chatID, true
+		}
+		fromChatID = chatID
+	}
+
+	return fromChatID, fromChatID != 0
+}
+
+// jsonFieldName returns JSON name of struct field
+func jsonFieldName(field reflect.StructField) string {
+	key, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	return key
+}
+
+// isChatIDField reports if struct field can contain chat ID that can be migrated
+func isChatIDField(field reflect.StructField) bool {
+	name := jsonFieldName(field)
+	if name != chatIDParameter && name != fromChatIDParameter {
+		return false
+	}
+
+	switch field.Type.Kind() { //nolint:exhaustive
+	case reflect.Int, reflect.Int64:
+		return true
+	case reflect.Struct:
+		return field.Type == reflect.TypeFor[ChatID]()
+	default:
+		return false
+	}
+}
+
+// chatIDFieldValue returns chat ID from chat ID field
+func chatIDFieldValue(field reflect.Value) int64 {
+	if field.Kind() == reflect.Struct {
+		chatID, _ := field.Interface().(ChatID) //nolint:errcheck
+		return chatID.ID
+	}
+	return field.Int()
+}
+
+// setChatIDField sets chat ID to chat ID field
+func setChatIDField(field reflect.Value, chatID int64) {
+	if field.Kind() == reflect.Struct {
+		field.Set(reflect.ValueOf(ChatID{ID: chatID}))
+		return
+	}
+	field.SetInt(chatID)
+}
+
+// resetFiles seeks all files to the start to make it possible to send them again, returns false if not all files
+// can be reset
+func resetFiles(parameters any) bool {
+	files, hasFiles := filesParameters(parameters)
+	if !hasFiles {
+		return true
+	}
+
+	for _, file := range files {
+		if isNil(file) {
+			continue
+		}
+
+		seeker, ok := file.(io.Seeker)
+		if !ok {
+			return false
+		}
+
+		if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+			return false
+		}
+	}
+
+	return true
+}
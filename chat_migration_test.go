@@ -0,0 +1,134 @@
+package telego
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	ta "github.com/mymmrac/telego/telegoapi"
+)
+
+func TestWithChatMigration(t *testing.T) {
+	bot := &Bot{}
+
+	err := WithChatMigration(nil)(bot)
+	require.NoError(t, err)
+	require.NotNil(t, bot.migration)
+
+	err = WithKnownChatMigrations(map[int64]int64{-1: -100})(bot)
+	require.NoError(t, err)
+
+	chatID, ok := bot.MigratedChatID(-1)
+	assert.True(t, ok)
+	assert.Equal(t, int64(-100), chatID)
+
+	_, ok = (&Bot{}).MigratedChatID(-1)
+	assert.False(t, ok)
+}
+
+func TestBot_constructAndCallRequestWithMigration(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	m := newMockedBot(ctrl)
+
+	var migrated [][2]int64
+	require.NoError(t, WithChatMigration(func(_ context.Context, oldChatID, newChatID int64) {
+		migrated = append(migrated, [2]int64{oldChatID, newChatID})
+	})(m.Bot))
+
+	migrateResp := &ta.Response{
+		Ok: false,
+		Error: &ta.Error{
+			ErrorCode:   400,
+			Description: "Bad Request: group chat was upgraded to a supergroup chat",
+			Parameters:  &ta.ResponseParameters{MigrateToChatID: -100},
+		},
+	}
+
+	params := &SendMessageParams{ChatID: ChatID{ID: -1}, Text: "test"}
+
+	gomock.InOrder(
+		m.MockRequestConstructor.EXPECT().
+			JSONRequest(params).
+			Return(data, nil),
+		m.MockAPICaller.EXPECT().
+			Call(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(migrateResp, nil),
+		m.MockRequestConstructor.EXPECT().
+			JSONRequest(&SendMessageParams{ChatID: ChatID{ID: -100}, Text: "test"}).
+			Return(data, nil),
+		m.MockAPICaller.EXPECT().
+			Call(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(telegoResponse(t, expectedMessage), nil),
+		m.MockRequestConstructor.EXPECT().
+			JSONRequest(&ForwardMessageParams{ChatID: ChatID{ID: 1}, FromChatID: ChatID{ID: -100}}).
+			Return(data, nil),
+		m.MockAPICaller.EXPECT().
+			Call(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(telegoResponse(t, expectedMessage), nil),
+	)
+
+	message, err := m.Bot.SendMessage(t.Context(), params)
+	require.NoError(t, err)
+	assert.Equal(t, expectedMessage, message)
+	assert.Equal(t, int64(-1), params.ChatID.ID)
+	assert.Equal(t, [][2]int64{{-1, -100}}, migrated)
+
+	_, err = m.Bot.ForwardMessage(t.Context(), &ForwardMessageParams{ChatID: ChatID{ID: 1}, FromChatID: ChatID{ID: -1}})
+	require.NoError(t, err)
+
+	t.Run("error_files_not_resettable", func(t *testing.T) {
+		gomock.InOrder(
+			m.MockRequestConstructor.EXPECT().
+				MultipartRequest(gomock.Any(), gomock.Any()).
+				Return(data, nil),
+			m.MockAPICaller.EXPECT().
+				Call(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(&ta.Response{
+					Ok: false,
+					Error: &ta.Error{
+						ErrorCode:  400,
+						Parameters: &ta.ResponseParameters{MigrateToChatID: -200},
+					},
+				}, nil),
+		)
+
+		_, err = m.Bot.SendDocument(t.Context(), &SendDocumentParams{ChatID: ChatID{ID: -2}, Document: testInputFile})
+		require.ErrorIs(t, err, ta.ErrMigrated)
+		assert.Equal(t, [][2]int64{{-1, -100}, {-2, -200}}, migrated)
+	})
+}
+
+func Test_parametersChatID(t *testing.T) {
+	chatID, ok := parametersChatID(&ForwardMessageParams{ChatID: ChatID{ID: 1}, FromChatID: ChatID{ID: 2}})
+	assert.True(t, ok)
+	assert.Equal(t, int64(1), chatID)
+
+	chatID, ok = parametersChatID(&ForwardMessageParams{ChatID: ChatID{Username: "@test"}, FromChatID: ChatID{ID: 2}})
+	assert.True(t, ok)
+	assert.Equal(t, int64(2), chatID)
+
+	chatID, ok = parametersChatID(&SetGameScoreParams{ChatID: 3})
+	assert.True(t, ok)
+	assert.Equal(t, int64(3), chatID)
+
+	_, ok = parametersChatID(&GetUpdatesParams{})
+	assert.False(t, ok)
+
+	_, ok = parametersChatID(nil)
+	assert.False(t, ok)
+}
+
+func Test_resetFiles(t *testing.T) {
+	assert.True(t, resetFiles(&SendMessageParams{}))
+	assert.False(t, resetFiles(&SendDocumentParams{Document: testInputFile}))
+
+	file, err := os.CreateTemp(t.TempDir(), "test")
+	require.NoError(t, err)
+	defer func() { _ = file.Close() }()
+
+	assert.True(t, resetFiles(&SendDocumentParams{Document: InputFile{File: file}}))
+}
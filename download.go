@@ -0,0 +1,199 @@
+package telego
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+
+	ta "github.com/mymmrac/telego/telegoapi"
+)
+
+var (
+	// ErrFileTooLarge returned when downloaded file exceeds max size
+	ErrFileTooLarge = errors.New("telego: file too large")
+	// ErrFileSizeMismatch returned when downloaded file size doesn't match [File.FileSize]
+	ErrFileSizeMismatch = errors.New("telego: file size mismatch")
+)
+
+// DownloadProgress reports progress of file download, downloaded includes offset, total is zero if file size is
+// unknown
+type DownloadProgress func(downloaded, total int64)
+
+// downloadFile represents configuration of file download
+type downloadFile struct {
+	maxSize    int64
+	offset     int64
+	progress   DownloadProgress
+	verifySize bool
+}
+
+// DownloadFileOption represents an option that can be applied to file download
+type DownloadFileOption func(bot *Bot, download *downloadFile) error
+
+// WithDownloadMaxSize sets max size of downloaded file in bytes, zero means unlimited. Default is
+// [Bot.DownloadLimit].
+func WithDownloadMaxSize(maxSize int64) DownloadFileOption {
+	return func(_ *Bot, download *downloadFile) error {
+		if maxSize < 0 {
+			return errors.New("max size should be non-negative")
+		}
+		download.maxSize = maxSize
+		return nil
+	}
+}
+
+// WithDownloadOffset sets offset from which file is downloaded, used to resume interrupted downloads, only remaining
+// data is written
+func WithDownloadOffset(offset int64) DownloadFileOption {
+	return func(_ *Bot, download *downloadFile) error {
+		if offset < 0 {
+			return errors.New("offset should be non-negative")
+		}
+		download.offset = offset
+		return nil
+	}
+}
+
+// WithDownloadProgress sets callback that reports download progress after each write
+func WithDownloadProgress(progress DownloadProgress) DownloadFileOption {
+	return func(_ *Bot, download *downloadFile) error {
+		download.progress = progress
+		return nil
+	}
+}
+
+// WithDownloadVerifySize enables verification of downloaded file size against [File.FileSize]
+func WithDownloadVerifySize() DownloadFileOption {
+	return func(_ *Bot, download *downloadFile) error {
+		download.verifySize = true
+		return nil
+	}
+}
+
+// DownloadFile gets file info using [Bot.GetFile] method and streams file data to the writer, using bot's API caller
+// (it must implement [ta.Downloader]) or reading file from disk if local Bot API server shares its files
+func (b *Bot) DownloadFile(
+	ctx context.Context, fileID string, writer io.Writer, options ...DownloadFileOption,
+) (*File, error) {
+	download := &downloadFile{
+		maxSize: b.DownloadLimit(),
+	}
+	for _, option := range options {
+		if err := option(b, download); err != nil {
+			return nil, fmt.Errorf("telego: download file options: %w", err)
+		}
+	}
+
+	file, err := b.GetFile(ctx, &GetFileParams{FileID: fileID})
+	if err != nil {
+		return nil, err
+	}
+
+	if download.maxSize > 0 && file.FileSize > download.maxSize {
+		return file, fmt.Errorf("%w: %d bytes", ErrFileTooLarge, file.FileSize)
+	}
+
+	body, err := b.openFile(ctx, file.FilePath, download.offset)
+	if err != nil {
+		return file, fmt.Errorf("telego: download file: %w", err)
+	}
+	defer func() { _ = body.Close() }() //nolint:errcheck
+
+	downloaded, err := download.copy(writer, body, file.FileSize)
+	if err != nil {
+		return file, err
+	}
+
+	if download.verifySize && file.FileSize > 0 && downloaded != file.FileSize {
+		return file, fmt.Errorf("%w: expected %d bytes, got %d", ErrFileSizeMismatch, file.FileSize, downloaded)
+	}
+
+	return file, nil
+}
+
+// openFile opens file data stream starting from offset
+func (b *Bot) openFile(ctx context.Context, filePath string, offset int64) (io.ReadCloser, error) {
+	if filePath == "" {
+		return nil, errors.New("empty file path")
+	}
+
+	if _, ok := b.LocalFilePath(filePath); ok {
+		file, err := b.OpenLocalFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err = file.Seek(offset, io.SeekStart); err != nil {
+			_ = file.Close()
+			return nil, fmt.Errorf("seek: %w", err)
+		}
+
+		return file, nil
+	}
+	if b.local != nil && filepath.IsAbs(filePath) {
+		return nil, fmt.Errorf("file %q is not accessible locally, local server doesn't serve files", filePath)
+	}
+
+	downloader, ok := b.api.(ta.Downloader)
+	if !ok {
+		return nil, ta.ErrDownloadNotSupported
+	}
+
+	response, err := downloader.Download(ctx, b.FileDownloadURL(filePath), offset)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case response.StatusCode == http.StatusPartialContent && offset > 0:
+		return response.Body, nil
+	case response.StatusCode == http.StatusOK:
+		// Server ignored range request, skip already downloaded data
+		if _, err = io.CopyN(io.Discard, response.Body, offset); err != nil {
+			_ = response.Body.Close()
+			return nil, fmt.Errorf("skip offset: %w", err)
+		}
+		return response.Body, nil
+	default:
+		_ = response.Body.Close()
+		return nil, fmt.Errorf("http status: %d", response.StatusCode)
+	}
+}
+
+// copy copies data to the writer enforcing max size and reporting progress, returns total downloaded size
+// including offset
+func (d *downloadFile) copy(writer io.Writer, body io.Reader, total int64) (int64, error) {
+	downloaded := d.offset
+	if d.maxSize > 0 {
+		body = io.LimitReader(body, d.maxSize-d.offset+1)
+	}
+
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := body.Read(buf)
+		if n > 0 {
+			downloaded += int64(n)
+			if d.maxSize > 0 && downloaded > d.maxSize {
+				return downloaded, fmt.Errorf("%w: more than %d bytes", ErrFileTooLarge, d.maxSize)
+			}
+
+			if _, writeErr := writer.Write(buf[:n]); writeErr != nil {
+				return downloaded, fmt.Errorf("telego: download file: write: %w", writeErr)
+			}
+
+			if d.progress != nil {
+				d.progress(downloaded, total)
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			return downloaded, nil
+		}
+		if err != nil {
+			return downloaded, fmt.Errorf("telego: download file: read: %w", err)
+		}
+	}
+}
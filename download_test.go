@@ -0,0 +1,163 @@
+package telego
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	ta "github.com/mymmrac/telego/telegoapi"
+)
+
+const testFileData = "0123456789"
+
+func newDownloadBot(t *testing.T, fileSize int64, ignoreRange bool) *Bot {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		switch request.URL.Path {
+		case botPathPrefix + validToken + "/getFile":
+			_, _ = writer.Write([]byte(`{"ok":true,"result":{"file_id":"1","file_unique_id":"1",` +
+				`"file_path":"doc/file","file_size":` + strconv.FormatInt(fileSize, 10) + `}}`))
+		case "/file" + botPathPrefix + validToken + "/doc/file":
+			if ignoreRange {
+				request.Header.Del(ta.RangeHeader)
+			}
+			http.ServeContent(writer, request, "file", time.Time{}, strings.NewReader(testFileData))
+		default:
+			writer.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	bot, err := NewBot(validToken, WithAPIServer(srv.URL), WithHTTPClient(srv.Client()), WithDiscardLogger())
+	require.NoError(t, err)
+
+	return bot
+}
+
+func TestBot_DownloadFile(t *testing.T) {
+	fileSize := int64(len(testFileData))
+
+	t.Run("success", func(t *testing.T) {
+		bot := newDownloadBot(t, fileSize, false)
+		buffer := &bytes.Buffer{}
+		var progress []int64
+
+		file, err := bot.DownloadFile(t.Context(), "1", buffer, WithDownloadVerifySize(),
+			WithDownloadProgress(func(downloaded, total int64) {
+				assert.Equal(t, fileSize, total)
+				progress = append(progress, downloaded)
+			}))
+		require.NoError(t, err)
+		assert.Equal(t, "doc/file", file.FilePath)
+		assert.Equal(t, testFileData, buffer.String())
+		assert.Equal(t, []int64{fileSize}, progress)
+	})
+
+	t.Run("resume", func(t *testing.T) {
+		bot := newDownloadBot(t, fileSize, false)
+		buffer := &bytes.Buffer{}
+
+		_, err := bot.DownloadFile(t.Context(), "1", buffer, WithDownloadOffset(4), WithDownloadVerifySize())
+		require.NoError(t, err)
+		assert.Equal(t, testFileData[4:], buffer.String())
+	})
+
+	t.Run("resume_range_ignored", func(t *testing.T) {
+		bot := newDownloadBot(t, fileSize, true)
+		buffer := &bytes.Buffer{}
+
+		_, err := bot.DownloadFile(t.Context(), "1", buffer, WithDownloadOffset(4))
+		require.NoError(t, err)
+		assert.Equal(t, testFileData[4:], buffer.String())
+	})
+
+	t.Run("local", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "doc"), 0o700))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "doc", "file"), []byte(testFileData), 0o600))
+
+		ctrl := gomock.NewController(t)
+		m := newMockedBot(ctrl)
+		m.Bot.local = &localAPIServer{serverDir: "/var/lib/bot-api", localDir: dir}
+
+		m.MockRequestConstructor.EXPECT().JSONRequest(gomock.Any()).Return(data, nil)
+		m.MockAPICaller.EXPECT().Call(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(telegoResponse(t, &File{FilePath: "/var/lib/bot-api/doc/file", FileSize: fileSize}), nil)
+
+		buffer := &bytes.Buffer{}
+		_, err := m.Bot.DownloadFile(t.Context(), "1", buffer, WithDownloadOffset(2), WithDownloadVerifySize())
+		require.NoError(t, err)
+		assert.Equal(t, testFileData[2:], buffer.String())
+	})
+
+	t.Run("error_local_not_accessible", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := newMockedBot(ctrl)
+		m.Bot.local = &localAPIServer{}
+
+		m.MockRequestConstructor.EXPECT().JSONRequest(gomock.Any()).Return(data, nil)
+		m.MockAPICaller.EXPECT().Call(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(telegoResponse(t, &File{FilePath: "/var/lib/bot-api/doc/file", FileSize: fileSize}), nil)
+
+		_, err := m.Bot.DownloadFile(t.Context(), "1", &bytes.Buffer{})
+		require.ErrorContains(t, err, "not accessible locally")
+	})
+
+	t.Run("error_max_size", func(t *testing.T) {
+		bot := newDownloadBot(t, fileSize, false)
+		buffer := &bytes.Buffer{}
+
+		_, err := bot.DownloadFile(t.Context(), "1", buffer, WithDownloadMaxSize(5))
+		require.ErrorIs(t, err, ErrFileTooLarge)
+		assert.Empty(t, buffer.String())
+	})
+
+	t.Run("error_max_size_unknown_file_size", func(t *testing.T) {
+		bot := newDownloadBot(t, 0, false)
+		buffer := &bytes.Buffer{}
+
+		_, err := bot.DownloadFile(t.Context(), "1", buffer, WithDownloadMaxSize(5))
+		require.ErrorIs(t, err, ErrFileTooLarge)
+		assert.Empty(t, buffer.String())
+	})
+
+	t.Run("error_size_mismatch", func(t *testing.T) {
+		bot := newDownloadBot(t, fileSize+1, false)
+
+		_, err := bot.DownloadFile(t.Context(), "1", &bytes.Buffer{}, WithDownloadVerifySize())
+		require.ErrorIs(t, err, ErrFileSizeMismatch)
+	})
+
+	t.Run("error_not_supported", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := newMockedBot(ctrl)
+
+		m.MockRequestConstructor.EXPECT().JSONRequest(gomock.Any()).Return(data, nil)
+		m.MockAPICaller.EXPECT().Call(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(telegoResponse(t, &File{FilePath: "doc/file"}), nil)
+
+		_, err := m.Bot.DownloadFile(t.Context(), "1", &bytes.Buffer{})
+		require.ErrorIs(t, err, ta.ErrDownloadNotSupported)
+	})
+
+	t.Run("error_options", func(t *testing.T) {
+		bot := newDownloadBot(t, fileSize, false)
+
+		_, err := bot.DownloadFile(t.Context(), "1", &bytes.Buffer{}, WithDownloadMaxSize(-1))
+		require.Error(t, err)
+
+		_, err = bot.DownloadFile(t.Context(), "1", &bytes.Buffer{}, WithDownloadOffset(-1))
+		require.Error(t, err)
+	})
+}
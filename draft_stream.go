@@ -0,0 +1,382 @@
+package telego
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mymmrac/telego/internal/textsplit"
+	ta "github.com/mymmrac/telego/telegoapi"
+)
+
+const (
+	defaultDraftStreamInterval  = time.Second
+	defaultDraftStreamKeepAlive = 20 * time.Second
+	draftStreamReadBufferSize   = 4096
+)
+
+// ErrDraftStreamClosed returned for writes to already closed draft stream
+var ErrDraftStreamClosed = errors.New("telego: draft stream closed")
+
+// DraftStream represents message that is streamed to private chat as draft while its text is being generated (for
+// example, by LLM) and sent as persistent message once done. Text can be written in chunks of any size, while
+// drafts are updated in background no more often than the configured interval and are re-sent periodically, so the
+// preview doesn't expire, even if no new text arrives for a while. Draft shows the last part of text that fits into
+// message limit, on close the whole text is sent using [Bot.SendLongMessage].
+//
+// Draft stream is safe for concurrent use, [DraftStream.Close] must be called once text is complete.
+type DraftStream struct {
+	bot       *Bot
+	ctx       context.Context
+	params    SendMessageParams
+	draftID   int
+	interval  time.Duration
+	keepAlive time.Duration
+	format    func(text string) (string, []MessageEntity)
+
+	mutex  sync.Mutex
+	text   []byte
+	dirty  bool
+	closed bool
+	err    error
+	notify chan struct{}
+	done   chan struct{}
+}
+
+// DraftStreamOption represents an option that can be applied to draft stream
+type DraftStreamOption func(s *DraftStream) error
+
+// WithDraftStreamInterval sets the min interval between draft updates. Default is 1s.
+func WithDraftStreamInterval(interval time.Duration) DraftStreamOption {
+	return func(s *DraftStream) error {
+		if interval <= 0 {
+			return fmt.Errorf("interval is not positive: %s", interval)
+		}
+		s.interval = interval
+		return nil
+	}
+}
+
+// WithDraftStreamKeepAlive sets the interval after which draft is re-sent if there were no updates, it should be less
+// than 30s draft lifetime. Default is 20s.
+func WithDraftStreamKeepAlive(keepAlive time.Duration) DraftStreamOption {
+	return func(s *DraftStream) error {
+		if keepAlive <= 0 {
+			return fmt.Errorf("keep alive interval is not positive: %s", keepAlive)
+		}
+		s.keepAlive = keepAlive
+		return nil
+	}
+}
+
+// WithDraftStreamDraftID sets the draft identifier. Default is random positive number.
+func WithDraftStreamDraftID(draftID int) DraftStreamOption {
+	return func(s *DraftStream) error {
+		if draftID == 0 {
+			return errors.New("draft ID is zero")
+		}
+		s.draftID = draftID
+		return nil
+	}
+}
+
+// WithDraftStreamFormat sets the function that converts written text into text with entities, it's applied to the
+// whole text on each draft update and before final send, so it should handle incomplete formatting gracefully.
+// Default is no formatting.
+func WithDraftStreamFormat(format func(text string) (string, []MessageEntity)) DraftStreamOption {
+	return func(s *DraftStream) error {
+		if format == nil {
+			return errors.New("format function is nil")
+		}
+		s.format = format
+		return nil
+	}
+}
+
+// NewDraftStream creates and starts new draft stream for bot, params are used as a template for final messages (text
+// and entities are replaced with streamed ones), chat ID must be numeric ID of private chat. Context is used for draft
+// updates, once it's done, updates stop and writes fail with context error.
+func NewDraftStream(
+	ctx context.Context, bot *Bot, params *SendMessageParams, options ...DraftStreamOption,
+) (*DraftStream, error) {
+	if params == nil {
+		return nil, errors.New("telego: draft stream: nil parameters")
+	}
+	if params.ChatID.ID == 0 {
+		return nil, errors.New("telego: draft stream: chat ID must be numeric ID of private chat")
+	}
+	if params.ParseMode != "" {
+		return nil, errors.New("telego: draft stream: parse mode is not supported, use format option instead")
+	}
+
+	s := &DraftStream{
+		bot:       bot,
+		ctx:       ctx,
+		params:    *params,
+		draftID:   rand.IntN(math.MaxInt32) + 1, //nolint:gosec
+		interval:  defaultDraftStreamInterval,
+		keepAlive: defaultDraftStreamKeepAlive,
+		format: func(text string) (string, []MessageEntity) {
+			return text, nil
+		},
+		notify: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+
+	for _, option := range options {
+		if err := option(s); err != nil {
+			return nil, fmt.Errorf("telego: draft stream options: %w", err)
+		}
+	}
+
+	go s.run()
+
+	return s, nil
+}
+
+// DraftID returns identifier of the draft
+func (s *DraftStream) DraftID() int {
+	return s.draftID
+}
+
+// Write appends text to the draft, text may end with incomplete UTF-8 character that will be completed by the next
+// write. Error is returned if stream is closed, its context is done or draft update failed.
+func (s *DraftStream) Write(p []byte) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return 0, ErrDraftStreamClosed
+	}
+	if s.err != nil {
+		return 0, s.err
+	}
+	if err := s.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	s.text = append(s.text, p...)
+	if len(p) != 0 {
+		s.dirty = true
+		s.wake()
+	}
+
+	return len(p), nil
+}
+
+// WriteString appends text to the draft, same as [DraftStream.Write]
+func (s *DraftStream) WriteString(text string) (int, error) {
+	return s.Write([]byte(text))
+}
+
+// ReadFrom appends text from reader to the draft until EOF or error
+func (s *DraftStream) ReadFrom(r io.Reader) (int64, error) {
+	buf := make([]byte, draftStreamReadBufferSize)
+	var total int64
+
+	for {
+		n, readErr := r.Read(buf)
+		if n > 0 {
+			written, err := s.Write(buf[:n])
+			total += int64(written)
+			if err != nil {
+				return total, err
+			}
+		}
+
+		if errors.Is(readErr, io.EOF) {
+			return total, nil
+		}
+		if readErr != nil {
+			return total, readErr
+		}
+	}
+}
+
+// Consume appends text chunks from channel to the draft until channel is closed or stream context is done
+func (s *DraftStream) Consume(chunks <-chan string) error {
+	for {
+		select {
+		case <-s.ctx.Done():
+			return s.ctx.Err()
+		case chunk, ok := <-chunks:
+			if !ok {
+				return nil
+			}
+			if _, err := s.WriteString(chunk); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Close stops draft updates and sends the whole text as one or more persistent messages using provided context,
+// nothing is sent if text is blank. Close can be called even if stream context is done or draft update failed.
+func (s *DraftStream) Close(ctx context.Context) ([]Message, error) {
+	s.mutex.Lock()
+	if s.closed {
+		s.mutex.Unlock()
+		return nil, ErrDraftStreamClosed
+	}
+	s.closed = true
+	s.wake()
+	s.mutex.Unlock()
+
+	<-s.done
+
+	text, entities := s.format(validUTF8Prefix(s.text))
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+
+	params := s.params
+	params.Text = text
+	params.Entities = entities
+
+	messages, err := s.bot.SendLongMessage(ctx, &params)
+	if err != nil {
+		return messages, fmt.Errorf("telego: draft stream: %w", err)
+	}
+	return messages, nil
+}
+
+// wake notifies update loop that state has changed
+func (s *DraftStream) wake() {
+	select {
+	case s.notify <- struct{}{}:
+	default:
+		// Update loop already notified
+	}
+}
+
+// run sends draft updates until stream is closed, its context is done or update failed
+func (s *DraftStream) run() {
+	defer close(s.done)
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	var nextUpdate, nextKeepAlive time.Time
+	blank := false
+	for {
+		s.mutex.Lock()
+		if s.closed {
+			s.mutex.Unlock()
+			return
+		}
+		dirty := s.dirty && !blank
+		s.mutex.Unlock()
+
+		var wakeAt time.Time
+		switch {
+		case dirty:
+			wakeAt = nextUpdate
+		case !nextKeepAlive.IsZero():
+			wakeAt = nextKeepAlive
+		}
+
+		if (dirty || !nextKeepAlive.IsZero()) && !wakeAt.After(time.Now()) {
+			blankText, retryAfter, err := s.update()
+			if err != nil {
+				s.mutex.Lock()
+				s.err = err
+				s.mutex.Unlock()
+				return
+			}
+
+			if blankText {
+				// Wait for more text
+				blank = true
+				nextKeepAlive = time.Time{}
+				continue
+			}
+
+			now := time.Now()
+			nextUpdate = now.Add(max(s.interval, retryAfter))
+			nextKeepAlive = now.Add(max(s.keepAlive, retryAfter))
+			continue
+		}
+
+		var timerC <-chan time.Time
+		if !wakeAt.IsZero() {
+			timer.Reset(time.Until(wakeAt))
+			timerC = timer.C
+		}
+
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-s.notify:
+			timer.Stop()
+			blank = false
+		case <-timerC:
+			// Update or keep alive is due
+		}
+	}
+}
+
+// update sends the last part of current text as draft, true is returned if text is blank and wasn't sent, as Telegram
+// rejects it. If flood control is hit, time to wait before the next update is returned and text is marked as not sent.
+func (s *DraftStream) update() (bool, time.Duration, error) {
+	s.mutex.Lock()
+	size := len(s.text)
+	text := validUTF8Prefix(s.text)
+	s.mutex.Unlock()
+
+	text, entities := s.format(text)
+	if strings.TrimSpace(text) == "" {
+		return true, 0, nil
+	}
+
+	s.mutex.Lock()
+	if len(s.text) == size {
+		s.dirty = false
+	}
+	s.mutex.Unlock()
+
+	params := &SendMessageDraftParams{
+		ChatID:          s.params.ChatID.ID,
+		MessageThreadID: s.params.MessageThreadID,
+		DraftID:         s.draftID,
+	}
+
+	chunks := splitMessageText(text, entities, textsplit.MaxMessageTextLength)
+	if len(chunks) != 0 {
+		chunk := chunks[len(chunks)-1]
+		params.Text = chunk.Text
+		params.Entities = chunk.Entities
+	}
+
+	err := s.bot.SendMessageDraft(s.ctx, params)
+	if retryAfter, ok := ta.RetryAfter(err); ok {
+		s.mutex.Lock()
+		s.dirty = true
+		s.mutex.Unlock()
+		return false, retryAfter, nil
+	}
+	if err != nil && s.ctx.Err() == nil {
+		return false, 0, fmt.Errorf("telego: draft stream: %w", err)
+	}
+	return false, 0, nil
+}
+
+// validUTF8Prefix returns text without trailing incomplete UTF-8 character
+func validUTF8Prefix(text []byte) string {
+	for i := len(text) - 1; i >= 0 && i >= len(text)-utf8.UTFMax; i-- {
+		if !utf8.RuneStart(text[i]) {
+			continue
+		}
+		if !utf8.FullRune(text[i:]) {
+			return string(text[:i])
+		}
+		break
+	}
+	return string(text)
+}
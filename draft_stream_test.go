@@ -0,0 +1,400 @@
+package telego
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	ta "github.com/mymmrac/telego/telegoapi"
+)
+
+type draftStreamRecorder struct {
+	mutex    sync.Mutex
+	drafts   []SendMessageDraftParams
+	messages []SendMessageParams
+}
+
+func (r *draftStreamRecorder) lastDraft() (SendMessageDraftParams, int) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if len(r.drafts) == 0 {
+		return SendMessageDraftParams{}, 0
+	}
+	return r.drafts[len(r.drafts)-1], len(r.drafts)
+}
+
+func newDraftStreamBot(t *testing.T, draftErrs ...error) (*Bot, *draftStreamRecorder) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	m := newMockedBot(ctrl)
+	recorder := &draftStreamRecorder{}
+
+	m.MockRequestConstructor.EXPECT().
+		JSONRequest(gomock.Any()).
+		DoAndReturn(func(parameters any) (*ta.RequestData, error) {
+			recorder.mutex.Lock()
+			defer recorder.mutex.Unlock()
+
+			switch p := parameters.(type) {
+			case *SendMessageDraftParams:
+				recorder.drafts = append(recorder.drafts, *p)
+				return &ta.RequestData{}, nil
+			case *SendMessageParams:
+				recorder.messages = append(recorder.messages, *p)
+				return data, nil
+			default:
+				return nil, errors.New("unexpected parameters")
+			}
+		}).
+		AnyTimes()
+
+	m.MockAPICaller.EXPECT().
+		Call(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, requestData *ta.RequestData) (*ta.Response, error) {
+			if requestData == data {
+				recorder.mutex.Lock()
+				defer recorder.mutex.Unlock()
+				return telegoResponse(t, &Message{MessageID: len(recorder.messages)}), nil
+			}
+
+			recorder.mutex.Lock()
+			defer recorder.mutex.Unlock()
+			if len(draftErrs) != 0 {
+				err := draftErrs[0]
+				draftErrs = draftErrs[1:]
+				if err != nil {
+					return nil, err
+				}
+			}
+			return emptyResp, nil
+		}).
+		AnyTimes()
+
+	return m.Bot, recorder
+}
+
+func TestNewDraftStream(t *testing.T) {
+	ctx := t.Context()
+	bot := &Bot{}
+	params := &SendMessageParams{ChatID: ChatID{ID: 1}}
+
+	t.Run("success", func(t *testing.T) {
+		stream, err := NewDraftStream(ctx, bot, params,
+			WithDraftStreamInterval(time.Millisecond),
+			WithDraftStreamKeepAlive(time.Second),
+			WithDraftStreamDraftID(42),
+			WithDraftStreamFormat(func(text string) (string, []MessageEntity) { return text, nil }),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, 42, stream.DraftID())
+		assert.Equal(t, time.Millisecond, stream.interval)
+		assert.Equal(t, time.Second, stream.keepAlive)
+
+		messages, err := stream.Close(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, messages)
+	})
+
+	t.Run("default_draft_id", func(t *testing.T) {
+		stream, err := NewDraftStream(ctx, bot, params)
+		require.NoError(t, err)
+		assert.Positive(t, stream.DraftID())
+
+		_, err = stream.Close(ctx)
+		require.NoError(t, err)
+	})
+
+	tests := []struct {
+		name    string
+		params  *SendMessageParams
+		options []DraftStreamOption
+	}{
+		{name: "nil_params"},
+		{name: "username", params: &SendMessageParams{ChatID: ChatID{Username: "@test"}}},
+		{name: "parse_mode", params: &SendMessageParams{ChatID: ChatID{ID: 1}, ParseMode: ModeHTML}},
+		{name: "interval", params: params, options: []DraftStreamOption{WithDraftStreamInterval(0)}},
+		{name: "keep_alive", params: params, options: []DraftStreamOption{WithDraftStreamKeepAlive(-1)}},
+		{name: "draft_id", params: params, options: []DraftStreamOption{WithDraftStreamDraftID(0)}},
+		{name: "format", params: params, options: []DraftStreamOption{WithDraftStreamFormat(nil)}},
+	}
+
+	for _, tt := range tests {
+		t.Run("error_"+tt.name, func(t *testing.T) {
+			stream, err := NewDraftStream(ctx, bot, tt.params, tt.options...)
+			require.Error(t, err)
+			assert.Nil(t, stream)
+		})
+	}
+}
+
+func TestDraftStream(t *testing.T) {
+	ctx := t.Context()
+	params := &SendMessageParams{
+		ChatID:          ChatID{ID: 1},
+		MessageThreadID: 2,
+		ReplyMarkup:     &ReplyKeyboardRemove{RemoveKeyboard: true},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		bot, recorder := newDraftStreamBot(t)
+		stream, err := NewDraftStream(ctx, bot, params,
+			WithDraftStreamInterval(time.Millisecond), WithDraftStreamDraftID(3))
+		require.NoError(t, err)
+
+		smile := []byte("🙂")
+		_, err = stream.WriteString("Hello, ")
+		require.NoError(t, err)
+		_, err = stream.Write(append([]byte("world "), smile[:2]...))
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			draft, _ := recorder.lastDraft()
+			return draft.Text == "Hello, world "
+		}, time.Second, time.Millisecond)
+
+		_, err = stream.Write(smile[2:])
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			draft, _ := recorder.lastDraft()
+			return draft.Text == "Hello, world 🙂"
+		}, time.Second, time.Millisecond)
+
+		draft, _ := recorder.lastDraft()
+		assert.Equal(t, SendMessageDraftParams{
+			ChatID:          1,
+			MessageThreadID: 2,
+			DraftID:         3,
+			Text:            "Hello, world 🙂",
+		}, draft)
+
+		messages, err := stream.Close(ctx)
+		require.NoError(t, err)
+		require.Len(t, messages, 1)
+
+		require.Len(t, recorder.messages, 1)
+		assert.Equal(t, "Hello, world 🙂", recorder.messages[0].Text)
+		assert.Equal(t, 2, recorder.messages[0].MessageThreadID)
+		assert.Equal(t, params.ReplyMarkup, recorder.messages[0].ReplyMarkup)
+
+		_, err = stream.WriteString("more")
+		require.ErrorIs(t, err, ErrDraftStreamClosed)
+		_, err = stream.Close(ctx)
+		require.ErrorIs(t, err, ErrDraftStreamClosed)
+	})
+
+	t.Run("keep_alive", func(t *testing.T) {
+		bot, recorder := newDraftStreamBot(t)
+		stream, err := NewDraftStream(ctx, bot, params,
+			WithDraftStreamInterval(time.Millisecond), WithDraftStreamKeepAlive(time.Millisecond))
+		require.NoError(t, err)
+
+		_, err = stream.WriteString("text")
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			draft, count := recorder.lastDraft()
+			return draft.Text == "text" && count >= 3
+		}, time.Second, time.Millisecond)
+
+		_, err = stream.Close(ctx)
+		require.NoError(t, err)
+	})
+
+	t.Run("blank_prefix", func(t *testing.T) {
+		bot, recorder := newDraftStreamBot(t)
+		stream, err := NewDraftStream(ctx, bot, params, WithDraftStreamInterval(time.Millisecond))
+		require.NoError(t, err)
+
+		_, err = stream.WriteString("\n ")
+		require.NoError(t, err)
+		time.Sleep(10 * time.Millisecond)
+
+		_, count := recorder.lastDraft()
+		assert.Zero(t, count)
+
+		_, err = stream.WriteString("text")
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			draft, _ := recorder.lastDraft()
+			return draft.Text == "\n text"
+		}, time.Second, time.Millisecond)
+
+		_, err = stream.Close(ctx)
+		require.NoError(t, err)
+	})
+
+	t.Run("throttle", func(t *testing.T) {
+		bot, recorder := newDraftStreamBot(t)
+		stream, err := NewDraftStream(ctx, bot, params, WithDraftStreamInterval(time.Hour))
+		require.NoError(t, err)
+
+		_, err = stream.WriteString("a")
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			_, count := recorder.lastDraft()
+			return count == 1
+		}, time.Second, time.Millisecond)
+
+		_, err = stream.WriteString("b")
+		require.NoError(t, err)
+		time.Sleep(10 * time.Millisecond)
+
+		draft, count := recorder.lastDraft()
+		assert.Equal(t, 1, count)
+		assert.Equal(t, "a", draft.Text)
+
+		_, err = stream.Close(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "ab", recorder.messages[0].Text)
+	})
+
+	t.Run("long_text", func(t *testing.T) {
+		bot, recorder := newDraftStreamBot(t)
+		stream, err := NewDraftStream(ctx, bot, params,
+			WithDraftStreamInterval(time.Millisecond),
+			WithDraftStreamFormat(func(text string) (string, []MessageEntity) {
+				return text, []MessageEntity{{Type: EntityTypeBold, Offset: 0, Length: len(text)}}
+			}),
+		)
+		require.NoError(t, err)
+
+		first := strings.Repeat("a", 3000)
+		second := strings.Repeat("b", 3000)
+
+		n, err := stream.ReadFrom(strings.NewReader(first + "\n\n" + second))
+		require.NoError(t, err)
+		assert.Equal(t, int64(6002), n)
+
+		require.Eventually(t, func() bool {
+			draft, _ := recorder.lastDraft()
+			return draft.Text == second
+		}, time.Second, time.Millisecond)
+
+		draft, _ := recorder.lastDraft()
+		assert.Equal(t, []MessageEntity{{Type: EntityTypeBold, Offset: 0, Length: 3000}}, draft.Entities)
+
+		messages, err := stream.Close(ctx)
+		require.NoError(t, err)
+		require.Len(t, messages, 2)
+
+		require.Len(t, recorder.messages, 2)
+		assert.Equal(t, first, recorder.messages[0].Text)
+		assert.Equal(t, second, recorder.messages[1].Text)
+		assert.Equal(t, []MessageEntity{{Type: EntityTypeBold, Offset: 0, Length: 3000}}, recorder.messages[1].Entities)
+	})
+
+	t.Run("consume", func(t *testing.T) {
+		bot, recorder := newDraftStreamBot(t)
+		stream, err := NewDraftStream(ctx, bot, params)
+		require.NoError(t, err)
+
+		chunks := make(chan string)
+		go func() {
+			for _, chunk := range []string{"one ", "two ", "three"} {
+				chunks <- chunk
+			}
+			close(chunks)
+		}()
+
+		require.NoError(t, stream.Consume(chunks))
+
+		_, err = stream.Close(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "one two three", recorder.messages[0].Text)
+	})
+
+	t.Run("flood_wait", func(t *testing.T) {
+		floodErr := &ta.Error{ErrorCode: 429, Parameters: &ta.ResponseParameters{RetryAfter: 0}}
+		bot, recorder := newDraftStreamBot(t, floodErr)
+		stream, err := NewDraftStream(ctx, bot, params, WithDraftStreamInterval(time.Millisecond))
+		require.NoError(t, err)
+
+		_, err = stream.WriteString("text")
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			_, count := recorder.lastDraft()
+			return count >= 2
+		}, time.Second, time.Millisecond)
+
+		_, err = stream.WriteString(" more")
+		require.NoError(t, err)
+
+		_, err = stream.Close(ctx)
+		require.NoError(t, err)
+	})
+
+	t.Run("draft_error", func(t *testing.T) {
+		bot, recorder := newDraftStreamBot(t, errors.New("test"))
+		stream, err := NewDraftStream(ctx, bot, params, WithDraftStreamInterval(time.Millisecond))
+		require.NoError(t, err)
+
+		_, err = stream.WriteString("text")
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			_, err = stream.WriteString(" more")
+			return err != nil
+		}, time.Second, time.Millisecond)
+
+		messages, err := stream.Close(ctx)
+		require.NoError(t, err)
+		require.Len(t, messages, 1)
+		assert.True(t, strings.HasPrefix(recorder.messages[0].Text, "text"))
+	})
+
+	t.Run("context_canceled", func(t *testing.T) {
+		bot, recorder := newDraftStreamBot(t)
+		streamCtx, cancel := context.WithCancel(ctx)
+		stream, err := NewDraftStream(streamCtx, bot, params)
+		require.NoError(t, err)
+
+		_, err = stream.WriteString("text")
+		require.NoError(t, err)
+		cancel()
+
+		_, err = stream.WriteString(" more")
+		require.ErrorIs(t, err, context.Canceled)
+		require.ErrorIs(t, stream.Consume(make(chan string)), context.Canceled)
+
+		messages, err := stream.Close(ctx)
+		require.NoError(t, err)
+		require.Len(t, messages, 1)
+		assert.Equal(t, "text", recorder.messages[0].Text)
+	})
+
+	t.Run("blank", func(t *testing.T) {
+		bot, recorder := newDraftStreamBot(t)
+		stream, err := NewDraftStream(ctx, bot, params)
+		require.NoError(t, err)
+
+		_, err = stream.WriteString(" \n ")
+		require.NoError(t, err)
+
+		messages, err := stream.Close(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, messages)
+		assert.Empty(t, recorder.messages)
+	})
+}
+
+func TestValidUTF8Prefix(t *testing.T) {
+	smile := []byte("🙂")
+
+	assert.Empty(t, validUTF8Prefix(nil))
+	assert.Equal(t, "abc", validUTF8Prefix([]byte("abc")))
+	assert.Equal(t, "a🙂", validUTF8Prefix([]byte("a🙂")))
+	assert.Equal(t, "a", validUTF8Prefix(append([]byte("a"), smile[:1]...)))
+	assert.Equal(t, "a", validUTF8Prefix(append([]byte("a"), smile[:3]...)))
+}
@@ -0,0 +1,258 @@
+package telego
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/mymmrac/telego/internal/json"
+	ta "github.com/mymmrac/telego/telegoapi"
+)
+
+// FileIDCache represents storage of file IDs of uploaded files by their content hash, implementations must be safe
+// for concurrent use
+type FileIDCache interface {
+	// Get returns file ID by key, false is returned if there is no file ID for the key
+	Get(ctx context.Context, key string) (string, bool)
+	// Set stores file ID by key
+	Set(ctx context.Context, key string, fileID string)
+	// Delete removes file ID by key
+	Delete(ctx context.Context, key string)
+}
+
+// MemoryFileIDCache is an in-memory implementation of [FileIDCache]
+type MemoryFileIDCache struct {
+	mutex   sync.RWMutex
+	fileIDs map[string]string
+}
+
+// NewMemoryFileIDCache creates new in-memory file ID cache
+func NewMemoryFileIDCache() *MemoryFileIDCache {
+	return &MemoryFileIDCache{
+		fileIDs: make(map[string]string),
+	}
+}
+
+// Get returns file ID by key
+func (c *MemoryFileIDCache) Get(_ context.Context, key string) (string, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	fileID, ok := c.fileIDs[key]
+	return fileID, ok
+}
+
+// Set stores file ID by key
+func (c *MemoryFileIDCache) Set(_ context.Context, key string, fileID string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.fileIDs[key] = fileID
+}
+
+// Delete removes file ID by key
+func (c *MemoryFileIDCache) Delete(_ context.Context, key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.fileIDs, key)
+}
+
+// WithFileIDCache enables caching of file IDs of uploaded files, if cache is nil, in-memory cache is used.
+// Files sent using [Bot.SendPhoto], [Bot.SendVideo], [Bot.SendDocument], [Bot.SendAudio], [Bot.SendAnimation],
+// [Bot.SendVoice], [Bot.SendVideoNote] and [Bot.SendSticker] methods are hashed (SHA-256), and if the same file was
+// already uploaded by this bot, its file ID is sent instead of uploading file again. Documents are also keyed by file
+// name, so the same content sent under a different name is uploaded again. If Telegram rejects cached file ID, it's
+// removed from the cache and file is uploaded.
+// Note: Readers that don't implement [io.Seeker] are read into memory to calculate hash
+func WithFileIDCache(cache FileIDCache) BotOption {
+	return func(bot *Bot) error {
+		if cache == nil {
+			cache = NewMemoryFileIDCache()
+		}
+
+		botID, _, _ := strings.Cut(bot.token, ":")
+		bot.interceptors = append(bot.interceptors, fileIDCacheInterceptor(cache, botID))
+		return nil
+	}
+}
+
+// fileIDCacheInterceptor returns interceptor that replaces uploaded files with cached file IDs, keys are prefixed
+// with bot ID as file IDs are unique for each bot
+func fileIDCacheInterceptor(cache FileIDCache, botID string) Interceptor {
+	return func(ctx context.Context, methodName string, parameters any, next APICall) (*ta.Response, error) {
+		parameters, file, kind := cacheableFile(parameters)
+		if file == nil || file.File == nil || isNil(file.File) {
+			return next(ctx, methodName, parameters)
+		}
+
+		hash, err := hashFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("file id cache: %w", err)
+		}
+		key := botID + ":" + kind + ":" + hash
+		if kind == "document" {
+			key += ":" + file.File.Name()
+		}
+
+		if fileID, ok := cache.Get(ctx, key); ok {
+			upload := *file
+			*file = InputFile{FileID: fileID}
+
+			response, err := next(ctx, methodName, parameters)
+			if !isWrongFileID(response, err) {
+				return response, err
+			}
+
+			cache.Delete(ctx, key)
+			*file = upload
+		}
+
+		response, err := next(ctx, methodName, parameters)
+		if err != nil || !response.Ok {
+			return response, err
+		}
+
+		var message Message
+		if err = json.Unmarshal(response.Result, &message); err != nil {
+			return response, nil //nolint:nilerr
+		}
+
+		if fileID := messageFileID(&message, kind); fileID != "" {
+			cache.Set(ctx, key, fileID)
+		}
+
+		return response, nil
+	}
+}
+
+// isWrongFileID reports if call failed because file ID was rejected
+func isWrongFileID(response *ta.Response, err error) bool {
+	if err != nil {
+		return errors.Is(err, ta.ErrWrongFileID)
+	}
+	return response != nil && !response.Ok && response.Error != nil && errors.Is(response.Error, ta.ErrWrongFileID)
+}
+
+// cacheableFile returns copy of parameters and pointer to its file that can be cached with file kind, nil file is
+// returned if method is not supported
+func cacheableFile(parameters any) (any, *InputFile, string) {
+	switch p := parameters.(type) {
+	case *SendPhotoParams:
+		c := *p
+		return &c, &c.Photo, "photo"
+	case *SendVideoParams:
+		c := *p
+		return &c, &c.Video, "video"
+	case *SendDocumentParams:
+		c := *p
+		return &c, &c.Document, "document"
+	case *SendAudioParams:
+		c := *p
+		return &c, &c.Audio, "audio"
+	case *SendAnimationParams:
+		c := *p
+		return &c, &c.Animation, "animation"
+	case *SendVoiceParams:
+		c := *p
+		return &c, &c.Voice, "voice"
+	case *SendVideoNoteParams:
+		c := *p
+		return &c, &c.VideoNote, "video_note"
+	case *SendStickerParams:
+		c := *p
+		return &c, &c.Sticker, "sticker"
+	default:
+		return parameters, nil, ""
+	}
+}
+
+// hashFile calculates SHA-256 hash of file data, seekable files are returned to their original position, other
+// files are replaced with in-memory copy
+func hashFile(file *InputFile) (string, error) {
+	hash := sha256.New()
+
+	if seeker, ok := file.File.(io.Seeker); ok {
+		offset, err := seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return "", fmt.Errorf("seek: %w", err)
+		}
+
+		if _, err = io.Copy(hash, file.File); err != nil {
+			return "", fmt.Errorf("read file: %w", err)
+		}
+
+		if _, err = seeker.Seek(offset, io.SeekStart); err != nil {
+			return "", fmt.Errorf("seek: %w", err)
+		}
+	} else {
+		data, err := io.ReadAll(file.File)
+		if err != nil {
+			return "", fmt.Errorf("read file: %w", err)
+		}
+		_, _ = hash.Write(data)
+
+		file.File = namedBytes{
+			Reader: bytes.NewReader(data),
+			name:   file.File.Name(),
+		}
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// namedBytes represents in-memory copy of [ta.NamedReader]
+type namedBytes struct {
+	*bytes.Reader
+	name string
+}
+
+// Name returns file name
+func (n namedBytes) Name() string {
+	return n.name
+}
+
+// messageFileID returns file ID of sent file by its kind
+func messageFileID(message *Message, kind string) string {
+	switch kind {
+	case "photo":
+		if len(message.Photo) > 0 {
+			return message.Photo[len(message.Photo)-1].FileID
+		}
+	case "video":
+		if message.Video != nil {
+			return message.Video.FileID
+		}
+	case "document":
+		if message.Document != nil {
+			return message.Document.FileID
+		}
+	case "audio":
+		if message.Audio != nil {
+			return message.Audio.FileID
+		}
+	case "animation":
+		if message.Animation != nil {
+			return message.Animation.FileID
+		}
+	case "voice":
+		if message.Voice != nil {
+			return message.Voice.FileID
+		}
+	case "video_note":
+		if message.VideoNote != nil {
+			return message.VideoNote.FileID
+		}
+	case "sticker":
+		if message.Sticker != nil {
+			return message.Sticker.FileID
+		}
+	}
+	return ""
+}
@@ -0,0 +1,241 @@
+package telego
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	ta "github.com/mymmrac/telego/telegoapi"
+)
+
+type testNamedStringReader struct {
+	io.Reader
+}
+
+func (testNamedStringReader) Name() string {
+	return "file"
+}
+
+func TestWithFileIDCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	m := newMockedBot(ctrl)
+
+	cache := NewMemoryFileIDCache()
+	require.NoError(t, WithFileIDCache(cache)(m.Bot))
+
+	photo := &Message{Photo: []PhotoSize{{FileID: "small"}, {FileID: "large"}}}
+
+	t.Run("upload", func(t *testing.T) {
+		m.MockRequestConstructor.EXPECT().
+			MultipartRequest(gomock.Any(), gomock.Any()).
+			Return(data, nil)
+		m.MockAPICaller.EXPECT().
+			Call(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(telegoResponse(t, photo), nil)
+
+		params := &SendPhotoParams{Photo: InputFile{File: testNamedStringReader{Reader: strings.NewReader("image")}}}
+		_, err := m.Bot.SendPhoto(t.Context(), params)
+		require.NoError(t, err)
+		assert.NotNil(t, params.Photo.File)
+
+		fileID, ok := cache.Get(t.Context(), "1234567890:photo:"+sha256Hex("image"))
+		require.True(t, ok)
+		assert.Equal(t, "large", fileID)
+	})
+
+	t.Run("cached", func(t *testing.T) {
+		m.MockRequestConstructor.EXPECT().
+			JSONRequest(gomock.Any()).
+			DoAndReturn(func(parameters any) (*ta.RequestData, error) {
+				p, ok := parameters.(*SendPhotoParams)
+				require.True(t, ok)
+				assert.Equal(t, InputFile{FileID: "large"}, p.Photo)
+				return data, nil
+			})
+		m.MockAPICaller.EXPECT().
+			Call(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(telegoResponse(t, photo), nil)
+
+		params := &SendPhotoParams{Photo: InputFile{File: testNamedStringReader{Reader: strings.NewReader("image")}}}
+		_, err := m.Bot.SendPhoto(t.Context(), params)
+		require.NoError(t, err)
+	})
+
+	t.Run("not_cacheable", func(t *testing.T) {
+		m.MockRequestConstructor.EXPECT().
+			JSONRequest(gomock.Any()).
+			Return(data, nil)
+		m.MockAPICaller.EXPECT().
+			Call(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(telegoResponse(t, expectedMessage), nil)
+
+		_, err := m.Bot.SendMessage(t.Context(), &SendMessageParams{})
+		require.NoError(t, err)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		m.MockRequestConstructor.EXPECT().
+			MultipartRequest(gomock.Any(), gomock.Any()).
+			Return(data, nil)
+		m.MockAPICaller.EXPECT().
+			Call(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil, errTest)
+
+		params := &SendVideoParams{Video: InputFile{File: testNamedStringReader{Reader: strings.NewReader("video")}}}
+		_, err := m.Bot.SendVideo(t.Context(), params)
+		require.Error(t, err)
+
+		_, ok := cache.Get(t.Context(), "1234567890:video:"+sha256Hex("video"))
+		assert.False(t, ok)
+	})
+
+	t.Run("stale", func(t *testing.T) {
+		key := "1234567890:photo:" + sha256Hex("stale")
+		cache.Set(t.Context(), key, "stale")
+
+		m.MockRequestConstructor.EXPECT().
+			JSONRequest(gomock.Any()).
+			Return(data, nil)
+		m.MockAPICaller.EXPECT().
+			Call(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&ta.Response{Ok: false, Error: &ta.Error{
+				ErrorCode:   400,
+				Description: "Bad Request: wrong file identifier/HTTP URL specified",
+			}}, nil)
+		m.MockRequestConstructor.EXPECT().
+			MultipartRequest(gomock.Any(), gomock.Any()).
+			Return(data, nil)
+		m.MockAPICaller.EXPECT().
+			Call(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(telegoResponse(t, &Message{Photo: []PhotoSize{{FileID: "fresh"}}}), nil)
+
+		params := &SendPhotoParams{Photo: InputFile{File: testNamedStringReader{Reader: strings.NewReader("stale")}}}
+		_, err := m.Bot.SendPhoto(t.Context(), params)
+		require.NoError(t, err)
+
+		fileID, ok := cache.Get(t.Context(), key)
+		require.True(t, ok)
+		assert.Equal(t, "fresh", fileID)
+	})
+
+	t.Run("document_name", func(t *testing.T) {
+		m.MockRequestConstructor.EXPECT().
+			MultipartRequest(gomock.Any(), gomock.Any()).
+			Return(data, nil)
+		m.MockAPICaller.EXPECT().
+			Call(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(telegoResponse(t, &Message{Document: &Document{FileID: "document"}}), nil)
+
+		params := &SendDocumentParams{
+			Document: InputFile{File: testNamedStringReader{Reader: strings.NewReader("doc")}},
+		}
+		_, err := m.Bot.SendDocument(t.Context(), params)
+		require.NoError(t, err)
+
+		fileID, ok := cache.Get(t.Context(), "1234567890:document:"+sha256Hex("doc")+":file")
+		require.True(t, ok)
+		assert.Equal(t, "document", fileID)
+	})
+}
+
+func TestMemoryFileIDCache(t *testing.T) {
+	cache := NewMemoryFileIDCache()
+
+	cache.Set(t.Context(), "key", "file")
+	fileID, ok := cache.Get(t.Context(), "key")
+	assert.True(t, ok)
+	assert.Equal(t, "file", fileID)
+
+	cache.Delete(t.Context(), "key")
+	_, ok = cache.Get(t.Context(), "key")
+	assert.False(t, ok)
+}
+
+func sha256Hex(s string) string {
+	file := &InputFile{File: testNamedStringReader{Reader: strings.NewReader(s)}}
+	hash, _ := hashFile(file) //nolint:errcheck
+	return hash
+}
+
+func Test_hashFile(t *testing.T) {
+	t.Run("seeker", func(t *testing.T) {
+		reader := bytes.NewReader([]byte("skip-data"))
+		_, err := reader.Seek(5, io.SeekStart)
+		require.NoError(t, err)
+
+		file := &InputFile{File: testSizedReader{Reader: reader}}
+		hash, err := hashFile(file)
+		require.NoError(t, err)
+		assert.Equal(t, "3a6eb0790f39ac87c94f3856b2dd2c5d110e6811602261a9a923d3bb23adc8b7", hash)
+
+		rest, err := io.ReadAll(file.File)
+		require.NoError(t, err)
+		assert.Equal(t, "data", string(rest))
+	})
+
+	t.Run("reader", func(t *testing.T) {
+		file := &InputFile{File: testNamedStringReader{Reader: strings.NewReader("data")}}
+		hash, err := hashFile(file)
+		require.NoError(t, err)
+		assert.Equal(t, "3a6eb0790f39ac87c94f3856b2dd2c5d110e6811602261a9a923d3bb23adc8b7", hash)
+		assert.Equal(t, "file", file.File.Name())
+
+		rest, err := io.ReadAll(file.File)
+		require.NoError(t, err)
+		assert.Equal(t, "data", string(rest))
+	})
+}
+
+func Test_messageFileID(t *testing.T) {
+	message := &Message{
+		Video:     &Video{FileID: "video"},
+		Document:  &Document{FileID: "document"},
+		Audio:     &Audio{FileID: "audio"},
+		Animation: &Animation{FileID: "animation"},
+		Voice:     &Voice{FileID: "voice"},
+		VideoNote: &VideoNote{FileID: "video_note"},
+		Sticker:   &Sticker{FileID: "sticker"},
+	}
+
+	for _, kind := range []string{"video", "document", "audio", "animation", "voice", "video_note", "sticker"} {
+		assert.Equal(t, kind, messageFileID(message, kind))
+		assert.Empty(t, messageFileID(&Message{}, kind))
+	}
+
+	assert.Empty(t, messageFileID(&Message{}, "photo"))
+	assert.Empty(t, messageFileID(message, "unknown"))
+}
+
+func Test_cacheableFile(t *testing.T) {
+	tests := []struct {
+		parameters any
+		kind       string
+	}{
+		{parameters: &SendPhotoParams{}, kind: "photo"},
+		{parameters: &SendVideoParams{}, kind: "video"},
+		{parameters: &SendDocumentParams{}, kind: "document"},
+		{parameters: &SendAudioParams{}, kind: "audio"},
+		{parameters: &SendAnimationParams{}, kind: "animation"},
+		{parameters: &SendVoiceParams{}, kind: "voice"},
+		{parameters: &SendVideoNoteParams{}, kind: "video_note"},
+		{parameters: &SendStickerParams{}, kind: "sticker"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			parameters, file, kind := cacheableFile(tt.parameters)
+			assert.Equal(t, tt.kind, kind)
+			assert.NotNil(t, file)
+			assert.NotSame(t, tt.parameters, parameters)
+		})
+	}
+
+	parameters, file, _ := cacheableFile(&SendMessageParams{})
+	assert.Nil(t, file)
+	assert.IsType(t, &SendMessageParams{}, parameters)
+}
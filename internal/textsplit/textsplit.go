@@ -0,0 +1,242 @@
+// Package textsplit splits long text into chunks with length limited in UTF-16 code units, it's used to split
+// messages and their entities
+package textsplit
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Telegram limits of text length in UTF-16 code units
+const (
+	// MaxMessageTextLength is max length of message text
+	MaxMessageTextLength = 4096
+	// MaxCaptionLength is max length of media caption
+	MaxCaptionLength = 1024
+)
+
+// splittableEntityTypes are types of entities that stay valid if split into parts, other entities (like custom emoji,
+// code or mentions) are not split unless they don't fit into one chunk
+var splittableEntityTypes = map[string]bool{
+	"bold":                  true,
+	"italic":                true,
+	"underline":             true,
+	"strikethrough":         true,
+	"spoiler":               true,
+	"text_link":             true,
+	"pre":                   true,
+	"blockquote":            true,
+	"expandable_blockquote": true,
+}
+
+// span represents range of the text in UTF-16 code units that should not be split
+type span struct {
+	start int
+	end   int
+}
+
+// Chunk represents part of the text
+type Chunk struct {
+	// Text of the chunk
+	Text string
+	// Offset of the chunk in the original text in UTF-16 code units
+	Offset int
+	// Length of the chunk in UTF-16 code units
+	Length int
+}
+
+// Clip returns offset and length of text range (like message entity) relative to the chunk, false if range doesn't
+// intersect with the chunk
+func (c Chunk) Clip(offset, length int) (int, int, bool) {
+	start := max(offset, c.Offset)
+	end := min(offset+length, c.Offset+c.Length)
+	if start >= end {
+		return 0, 0, false
+	}
+	return start - c.Offset, end - start, true
+}
+
+// boundary represents position between runes of the text
+type boundary struct {
+	// index of byte in the text
+	index int
+	// offset in UTF-16 code units
+	offset int
+}
+
+// breakFunc reports if text can be split between r1 and r2 runes (r1 is the one before r2)
+type breakFunc func(r1, r2 rune) bool
+
+// breaks ordered by preference: paragraphs, lines, sentences and words
+var breaks = []breakFunc{
+	func(r1, r2 rune) bool { return r1 == '\n' && r2 == '\n' },
+	func(_, r2 rune) bool { return r2 == '\n' },
+	func(r1, r2 rune) bool { return strings.ContainsRune(".!?…", r1) && unicode.IsSpace(r2) },
+	func(_, r2 rune) bool { return unicode.IsSpace(r2) },
+}
+
+// Split splits text into chunks no longer than limit UTF-16 code units, text is split at paragraph, line, sentence or
+// word boundary in the second half of the chunk if possible, otherwise at the limit, surrogate pairs are never split.
+// Whitespace around split points is trimmed. Text that fits into limit (or any text if limit is not positive) is
+// returned as a single chunk as is.
+func Split(text string, limit int) []Chunk {
+	return split(text, limit, nil)
+}
+
+// EntitiesChunk represents part of the text with its entities
+type EntitiesChunk[E any] struct {
+	// Text of the chunk
+	Text string
+	// Entities of the chunk with offsets relative to the chunk
+	Entities []E
+}
+
+// SplitEntities splits text like [Split] and re-bases entities to the chunk they belong to, entities that span
+// multiple chunks are split between them. Text is never split inside entities that can't be split (like custom emoji,
+// code or mentions), split point is moved before such entity, unless it doesn't fit into one chunk. Fields function
+// returns pointers to offset and length of the entity and its type.
+func SplitEntities[E any](
+	text string, entities []E, limit int, fields func(entity *E) (offset, length *int, entityType string),
+) []EntitiesChunk[E] {
+	var atomic []span
+	for i := range entities {
+		offset, length, entityType := fields(&entities[i])
+		if !splittableEntityTypes[entityType] && *length > 0 {
+			atomic = append(atomic, span{start: *offset, end: *offset + *length})
+		}
+	}
+
+	chunks := split(text, limit, atomic)
+	result := make([]EntitiesChunk[E], 0, len(chunks))
+	for _, chunk := range chunks {
+		entitiesChunk := EntitiesChunk[E]{
+			Text: chunk.Text,
+		}
+
+		for _, entity := range entities {
+			offset, length, _ := fields(&entity)
+			clippedOffset, clippedLength, ok := chunk.Clip(*offset, *length)
+			if !ok {
+				continue
+			}
+
+			*offset = clippedOffset
+			*length = clippedLength
+			entitiesChunk.Entities = append(entitiesChunk.Entities, entity)
+		}
+
+		result = append(result, entitiesChunk)
+	}
+
+	return result
+}
+
+// split splits text into chunks, split points are moved before atomic spans that would be split otherwise
+func split(text string, limit int, atomic []span) []Chunk {
+	boundaries := make([]boundary, 0, len(text)+1)
+	offset := 0
+	for index, r := range text {
+		boundaries = append(boundaries, boundary{index: index, offset: offset})
+		offset += utf16Len(r)
+	}
+	boundaries = append(boundaries, boundary{index: len(text), offset: offset})
+
+	if limit <= 0 || offset <= limit {
+		return []Chunk{{Text: text, Offset: 0, Length: offset}}
+	}
+
+	var chunks []Chunk
+	last := len(boundaries) - 1
+	for start := 0; start < last; {
+		end := last
+		if boundaries[last].offset-boundaries[start].offset > limit {
+			end = start + sort.Search(last-start, func(i int) bool {
+				return boundaries[start+i+1].offset-boundaries[start].offset > limit
+			})
+			end = max(breakAt(text, boundaries, start, end), start+1)
+
+			for {
+				spanStart, ok := splitSpan(atomic, boundaries[start].offset, boundaries[end].offset)
+				if !ok {
+					break
+				}
+
+				spanEnd := sort.Search(len(boundaries), func(i int) bool {
+					return boundaries[i].offset > spanStart
+				}) - 1
+				if spanEnd <= start {
+					break
+				}
+				end = breakAt(text, boundaries, start, spanEnd)
+			}
+		}
+
+		next := end
+		for end > start && end < last && isSpaceBefore(text, boundaries[end]) {
+			end--
+		}
+		for next < last && isSpaceAfter(text, boundaries[next]) {
+			next++
+		}
+
+		if end > start {
+			chunks = append(chunks, Chunk{
+				Text:   text[boundaries[start].index:boundaries[end].index],
+				Offset: boundaries[start].offset,
+				Length: boundaries[end].offset - boundaries[start].offset,
+			})
+		}
+		start = next
+	}
+
+	return chunks
+}
+
+// splitSpan returns the start of the first atomic span that would be split at offset, spans starting at the chunk
+// start are ignored as they don't fit into one chunk anyway
+func splitSpan(atomic []span, chunkStart, offset int) (int, bool) {
+	start, found := 0, false
+	for _, s := range atomic {
+		if s.start > chunkStart && s.start < offset && s.end > offset && (!found || s.start < start) {
+			start, found = s.start, true
+		}
+	}
+	return start, found
+}
+
+// breakAt returns the best boundary to split text between start and end boundaries
+func breakAt(text string, boundaries []boundary, start, end int) int {
+	minOffset := boundaries[start].offset + (boundaries[end].offset-boundaries[start].offset)/2
+	for _, canBreak := range breaks {
+		for i := end; i > start && boundaries[i].offset > minOffset; i-- {
+			r1, _ := utf8.DecodeLastRuneInString(text[:boundaries[i].index])
+			r2, _ := utf8.DecodeRuneInString(text[boundaries[i].index:])
+			if canBreak(r1, r2) {
+				return i
+			}
+		}
+	}
+	return end
+}
+
+// isSpaceBefore reports if rune before boundary is whitespace
+func isSpaceBefore(text string, b boundary) bool {
+	r, _ := utf8.DecodeLastRuneInString(text[:b.index])
+	return unicode.IsSpace(r)
+}
+
+// isSpaceAfter reports if rune after boundary is whitespace
+func isSpaceAfter(text string, b boundary) bool {
+	r, _ := utf8.DecodeRuneInString(text[b.index:])
+	return unicode.IsSpace(r)
+}
+
+// utf16Len returns number of UTF-16 code units used to encode rune
+func utf16Len(r rune) int {
+	if r >= 0x10000 {
+		return 2
+	}
+	return 1
+}
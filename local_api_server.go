@@ -0,0 +1,177 @@
+package telego
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// File size limits of cloud and local Bot API servers in bytes, zero means unlimited
+const (
+	CloudUploadLimit   = 50 * 1024 * 1024
+	CloudDownloadLimit = 20 * 1024 * 1024
+	LocalUploadLimit   = 2000 * 1024 * 1024
+	LocalDownloadLimit = 0
+)
+
+// FileURIScheme represents URI scheme used for files stored on the local Bot API server filesystem
+const FileURIScheme = "file://"
+
+// localAPIServer represents configuration of local Bot API server (started with --local flag)
+type localAPIServer struct {
+	serverDir string
+	localDir  string
+}
+
+// LocalAPIServerOption represents an option that can be applied to local Bot API server configuration
+type LocalAPIServerOption func(local *localAPIServer) error
+
+// WithLocalFileDirectory sets working directory of local Bot API server (--dir flag) and the same directory as it's
+// accessible from this process (for example, shared volume mounted to a different path), so files can be read from
+// disk directly, empty local directory means that server and bot share the filesystem. Local server doesn't serve
+// files over HTTP, so this option is required to download files.
+func WithLocalFileDirectory(serverDir, localDir string) LocalAPIServerOption {
+	return func(local *localAPIServer) error {
+		if !filepath.IsAbs(serverDir) {
+			return fmt.Errorf("server directory should be absolute: %q", serverDir)
+		}
+		if localDir == "" {
+			localDir = serverDir
+		}
+
+		local.serverDir = filepath.Clean(serverDir)
+		local.localDir = filepath.Clean(localDir)
+		return nil
+	}
+}
+
+// WithLocalAPIServer sets local Bot API server URL to use, server should be started with --local flag, in this mode
+// [Bot.GetFile] returns absolute paths, files can be uploaded using file:// URIs, upload limit is 2000 MB and
+// download size is unlimited, to download files [WithLocalFileDirectory] option should be set
+// Note: Before using bot with local server for the first time, it should be logged out from the cloud server, see
+// [Bot.MoveToAPIServer]
+func WithLocalAPIServer(apiURL string, options ...LocalAPIServerOption) BotOption {
+	return func(bot *Bot) error {
+		if apiURL == "" {
+			return errors.New("empty local bot api server url")
+		}
+
+		local := &localAPIServer{}
+		for _, option := range options {
+			if err := option(local); err != nil {
+				return fmt.Errorf("local api server: %w", err)
+			}
+		}
+
+		bot.apiURL = apiURL
+		bot.local = local
+		return nil
+	}
+}
+
+// IsLocalAPIServer reports if bot uses local Bot API server
+func (b *Bot) IsLocalAPIServer() bool {
+	return b.local != nil
+}
+
+// UploadLimit returns max size of uploaded files in bytes
+func (b *Bot) UploadLimit() int64 {
+	if b.local != nil {
+		return LocalUploadLimit
+	}
+	return CloudUploadLimit
+}
+
+// DownloadLimit returns max size of downloaded files in bytes, zero means unlimited
+func (b *Bot) DownloadLimit() int64 {
+	if b.local != nil {
+		return LocalDownloadLimit
+	}
+	return CloudDownloadLimit
+}
+
+// LocalFilePath returns path of the file (retrieved from [Bot.GetFile] method) as it's accessible from this process,
+// false is returned if bot doesn't use local Bot API server, file directory is not set by [WithLocalFileDirectory],
+// or file path is not absolute or is outside of server directory
+func (b *Bot) LocalFilePath(filePath string) (string, bool) {
+	if b.local == nil || b.local.localDir == "" || !filepath.IsAbs(filePath) {
+		return "", false
+	}
+
+	relPath, err := filepath.Rel(b.local.serverDir, filepath.Clean(filePath))
+	if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+
+	return filepath.Join(b.local.localDir, relPath), true
+}
+
+// LocalFileURI returns file:// URI of the file stored on disk that can be used as [InputFile.URL] with local Bot API
+// server, path is converted from local directory to server directory if it's set
+func (b *Bot) LocalFileURI(localPath string) (string, error) {
+	absPath, err := filepath.Abs(localPath)
+	if err != nil {
+		return "", fmt.Errorf("telego: local file uri: %w", err)
+	}
+
+	if b.local != nil && b.local.localDir != "" {
+		relPath, relErr := filepath.Rel(b.local.localDir, absPath)
+		if relErr == nil && relPath != ".." && !strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+			absPath = filepath.Join(b.local.serverDir, relPath)
+		}
+	}
+
+	return FileURIScheme + filepath.ToSlash(absPath), nil
+}
+
+// OpenLocalFile opens the file (retrieved from [Bot.GetFile] method) from disk, works only with local Bot API server
+// that shares its files with this process, only files inside directory set by [WithLocalFileDirectory] can be opened
+func (b *Bot) OpenLocalFile(filePath string) (*os.File, error) {
+	localPath, ok := b.LocalFilePath(filePath)
+	if !ok {
+		return nil, fmt.Errorf("telego: file %q is not accessible locally", filePath)
+	}
+
+	file, err := os.Open(localPath) //nolint:gosec
+	if err != nil {
+		return nil, fmt.Errorf("telego: open local file: %w", err)
+	}
+
+	return file, nil
+}
+
+// MoveToAPIServer prepares bot to be launched on another Bot API server, target should be a bot with the same token
+// configured to use another server. If bot uses cloud server, it's logged out, if bot uses local server, webhook is
+// deleted and bot instance is closed. After that target server is checked by calling [Bot.GetMe] method.
+// Note: After logging out from the cloud server, bot can't log in back for 10 minutes, and close method returns error
+// 429 in the first 10 minutes after the bot is launched
+func (b *Bot) MoveToAPIServer(ctx context.Context, target *Bot) error {
+	if target == nil || target.token != b.token {
+		return errors.New("telego: move to api server: target bot should have the same token")
+	}
+	if target.apiURL == b.apiURL {
+		return errors.New("telego: move to api server: target bot should use another server")
+	}
+
+	if b.local == nil {
+		if err := b.LogOut(ctx); err != nil {
+			return fmt.Errorf("telego: move to api server: %w", err)
+		}
+	} else {
+		if err := b.DeleteWebhook(ctx, nil); err != nil {
+			return fmt.Errorf("telego: move to api server: %w", err)
+		}
+		if err := b.Close(ctx); err != nil {
+			return fmt.Errorf("telego: move to api server: %w", err)
+		}
+	}
+
+	if _, err := target.GetMe(ctx); err != nil {
+		return fmt.Errorf("telego: move to api server: check target: %w", err)
+	}
+
+	return nil
+}
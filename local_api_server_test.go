@@ -0,0 +1,195 @@
+package telego
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestWithLocalAPIServer(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		bot := &Bot{}
+
+		err := WithLocalAPIServer("http://localhost:8081", WithLocalFileDirectory("/var/lib/bot-api/", "data"))(bot)
+		require.NoError(t, err)
+		assert.Equal(t, "http://localhost:8081", bot.apiURL)
+		assert.True(t, bot.IsLocalAPIServer())
+		assert.Equal(t, &localAPIServer{serverDir: "/var/lib/bot-api", localDir: "data"}, bot.local)
+	})
+
+	t.Run("error_url", func(t *testing.T) {
+		err := WithLocalAPIServer("")(&Bot{})
+		require.Error(t, err)
+	})
+
+	t.Run("error_server_dir", func(t *testing.T) {
+		err := WithLocalAPIServer("http://localhost:8081", WithLocalFileDirectory("bot-api", "data"))(&Bot{})
+		require.Error(t, err)
+	})
+
+	t.Run("shared_dir", func(t *testing.T) {
+		bot := &Bot{}
+
+		err := WithLocalAPIServer("http://localhost:8081", WithLocalFileDirectory("/var/lib/bot-api", ""))(bot)
+		require.NoError(t, err)
+		assert.Equal(t, &localAPIServer{serverDir: "/var/lib/bot-api", localDir: "/var/lib/bot-api"}, bot.local)
+
+		path, ok := bot.LocalFilePath("/var/lib/bot-api/photos/file.jpg")
+		assert.True(t, ok)
+		assert.Equal(t, "/var/lib/bot-api/photos/file.jpg", path)
+		assert.Equal(t, "file:///var/lib/bot-api/photos/file.jpg", bot.FileDownloadURL(path))
+	})
+}
+
+func TestBot_limits(t *testing.T) {
+	bot := &Bot{}
+	assert.False(t, bot.IsLocalAPIServer())
+	assert.EqualValues(t, CloudUploadLimit, bot.UploadLimit())
+	assert.EqualValues(t, CloudDownloadLimit, bot.DownloadLimit())
+
+	bot.local = &localAPIServer{}
+	assert.EqualValues(t, LocalUploadLimit, bot.UploadLimit())
+	assert.EqualValues(t, LocalDownloadLimit, bot.DownloadLimit())
+}
+
+func TestBot_LocalFilePath(t *testing.T) {
+	bot := &Bot{}
+
+	_, ok := bot.LocalFilePath("/var/lib/bot-api/photos/file.jpg")
+	assert.False(t, ok)
+
+	bot.local = &localAPIServer{}
+
+	_, ok = bot.LocalFilePath("/var/lib/bot-api/photos/file.jpg")
+	assert.False(t, ok)
+
+	bot.local = &localAPIServer{serverDir: "/var/lib/bot-api", localDir: "/mnt/data"}
+
+	path, ok := bot.LocalFilePath("/var/lib/bot-api/photos/file.jpg")
+	assert.True(t, ok)
+	assert.Equal(t, "/mnt/data/photos/file.jpg", path)
+
+	_, ok = bot.LocalFilePath("photos/file.jpg")
+	assert.False(t, ok)
+
+	_, ok = bot.LocalFilePath("/var/lib/other/file.jpg")
+	assert.False(t, ok)
+
+	_, ok = bot.LocalFilePath("/var/lib/bot-api/../../../etc/passwd")
+	assert.False(t, ok)
+}
+
+func TestBot_LocalFileURI(t *testing.T) {
+	bot := &Bot{}
+
+	uri, err := bot.LocalFileURI("/mnt/data/file.jpg")
+	require.NoError(t, err)
+	assert.Equal(t, "file:///mnt/data/file.jpg", uri)
+
+	bot.local = &localAPIServer{serverDir: "/var/lib/bot-api", localDir: "/mnt/data"}
+
+	uri, err = bot.LocalFileURI("/mnt/data/file.jpg")
+	require.NoError(t, err)
+	assert.Equal(t, "file:///var/lib/bot-api/file.jpg", uri)
+
+	uri, err = bot.LocalFileURI("/mnt/other/file.jpg")
+	require.NoError(t, err)
+	assert.Equal(t, "file:///mnt/other/file.jpg", uri)
+}
+
+func TestBot_OpenLocalFile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file"), []byte("data"), 0o600))
+
+	bot := &Bot{local: &localAPIServer{serverDir: "/var/lib/bot-api", localDir: dir}}
+
+	file, err := bot.OpenLocalFile("/var/lib/bot-api/file")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = file.Close() })
+
+	fileData, err := io.ReadAll(file)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("data"), fileData)
+
+	_, err = bot.OpenLocalFile("/var/lib/bot-api/not-found")
+	require.Error(t, err)
+
+	_, err = bot.OpenLocalFile("file")
+	require.Error(t, err)
+
+	_, err = bot.OpenLocalFile("/var/lib/bot-api/../file")
+	require.Error(t, err)
+}
+
+func TestBot_MoveToAPIServer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	t.Run("cloud_to_local", func(t *testing.T) {
+		cloud := newMockedBot(ctrl)
+		local := newMockedBot(ctrl)
+		local.Bot.local = &localAPIServer{}
+		local.Bot.apiURL = "http://localhost:8081"
+
+		cloud.MockRequestConstructor.EXPECT().JSONRequest(gomock.Any()).Return(data, nil)
+		cloud.MockAPICaller.EXPECT().
+			Call(gomock.Any(), cloud.Bot.apiURL+botPathPrefix+validToken+"/logOut", gomock.Any()).
+			Return(emptyResp, nil)
+
+		local.MockRequestConstructor.EXPECT().JSONRequest(gomock.Any()).Return(data, nil)
+		local.MockAPICaller.EXPECT().
+			Call(gomock.Any(), "http://localhost:8081"+botPathPrefix+validToken+"/getMe", gomock.Any()).
+			Return(telegoResponse(t, &User{ID: 1}), nil)
+
+		err := cloud.Bot.MoveToAPIServer(t.Context(), local.Bot)
+		require.NoError(t, err)
+	})
+
+	t.Run("local_to_local", func(t *testing.T) {
+		local1 := newMockedBot(ctrl)
+		local1.Bot.local = &localAPIServer{}
+		local2 := newMockedBot(ctrl)
+		local2.Bot.local = &localAPIServer{}
+		local2.Bot.apiURL = "http://localhost:8081"
+
+		local1.MockRequestConstructor.EXPECT().JSONRequest(gomock.Any()).Return(data, nil).Times(2)
+		gomock.InOrder(
+			local1.MockAPICaller.EXPECT().
+				Call(gomock.Any(), local1.Bot.apiURL+botPathPrefix+validToken+"/deleteWebhook", gomock.Any()).
+				Return(emptyResp, nil),
+			local1.MockAPICaller.EXPECT().
+				Call(gomock.Any(), local1.Bot.apiURL+botPathPrefix+validToken+"/close", gomock.Any()).
+				Return(emptyResp, nil),
+		)
+
+		local2.MockRequestConstructor.EXPECT().JSONRequest(gomock.Any()).Return(nil, errTest)
+
+		err := local1.Bot.MoveToAPIServer(t.Context(), local2.Bot)
+		require.Error(t, err)
+	})
+
+	t.Run("error_target", func(t *testing.T) {
+		m := newMockedBot(ctrl)
+
+		err := m.Bot.MoveToAPIServer(t.Context(), nil)
+		require.Error(t, err)
+
+		err = m.Bot.MoveToAPIServer(t.Context(), m.Bot)
+		require.Error(t, err)
+	})
+
+	t.Run("error_log_out", func(t *testing.T) {
+		cloud := newMockedBot(ctrl)
+		local := newMockedBot(ctrl)
+		local.Bot.apiURL = "http://localhost:8081"
+
+		cloud.MockRequestConstructor.EXPECT().JSONRequest(gomock.Any()).Return(nil, errTest)
+
+		err := cloud.Bot.MoveToAPIServer(t.Context(), local.Bot)
+		require.Error(t, err)
+	})
+}
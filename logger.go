@@ -1,9 +1,12 @@
 package telego
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -81,6 +84,16 @@ func (l *logger) log(mode logMode, text string) {
 	}
 }
 
+// debugEnabled reports if debug information is logged
+func (l *logger) debugEnabled() bool {
+	return l.DebugMode
+}
+
+// errorEnabled reports if error information is logged
+func (l *logger) errorEnabled() bool {
+	return l.PrintErrors
+}
+
 // Debugf logs debug information
 func (l *logger) Debugf(format string, args ...any) {
 	if l.DebugMode {
@@ -102,3 +115,204 @@ const DefaultLoggerTokenReplacement = "BOT_TOKEN"
 func defaultReplacer(token string) *strings.Replacer {
 	return strings.NewReplacer(token, DefaultLoggerTokenReplacement)
 }
+
+// Structured log attribute keys
+const (
+	logKeyMethod     = "method"
+	logKeyError      = "error"
+	logKeyURL        = "url"
+	logKeyData       = "data"
+	logKeyResponse   = "response"
+	logKeyRetryAfter = "retry_after"
+	logKeyOldChatID  = "old_chat_id"
+	logKeyNewChatID  = "new_chat_id"
+	logKeyCampaign   = "campaign"
+)
+
+// logger returns structured logger used for internal logs, if it's not set, [Logger] is used through compatibility
+// handler
+func (b *Bot) logger() *slog.Logger {
+	if b.structuredLog != nil {
+		return b.structuredLog
+	}
+	return newPrintfLogger(b.log)
+}
+
+// newPrintfLogger returns structured logger that writes to printf-style [Logger]
+func newPrintfLogger(log Logger) *slog.Logger {
+	return slog.New(printfHandler{log: log})
+}
+
+// levelLogger represents [Logger] that reports which records it logs
+type levelLogger interface {
+	debugEnabled() bool
+	errorEnabled() bool
+}
+
+// printfHandler is a [slog.Handler] that writes records to printf-style [Logger], debug and info records are logged
+// as debug, warn and error records are logged as errors, attributes are appended to the message as key=value pairs
+type printfHandler struct {
+	log    Logger
+	attrs  string
+	prefix string
+}
+
+// Enabled reports if [Logger] logs records of the level, loggers that don't report it are assumed to log everything
+func (h printfHandler) Enabled(_ context.Context, level slog.Level) bool {
+	if h.log == nil {
+		return false
+	}
+
+	leveled, ok := h.log.(levelLogger)
+	if !ok {
+		return true
+	}
+	if level >= slog.LevelWarn {
+		return leveled.errorEnabled()
+	}
+	return leveled.debugEnabled()
+}
+
+// Handle writes record to [Logger]
+func (h printfHandler) Handle(_ context.Context, record slog.Record) error {
+	text := &strings.Builder{}
+	_, _ = text.WriteString(record.Message)
+	_, _ = text.WriteString(h.attrs)
+	record.Attrs(func(attr slog.Attr) bool {
+		writeAttr(text, h.prefix, attr)
+		return true
+	})
+
+	if record.Level >= slog.LevelWarn {
+		h.log.Errorf("%s", text.String())
+	} else {
+		h.log.Debugf("%s", text.String())
+	}
+	return nil
+}
+
+// WithAttrs returns handler with attributes added to all records
+func (h printfHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	text := &strings.Builder{}
+	_, _ = text.WriteString(h.attrs)
+	for _, attr := range attrs {
+		writeAttr(text, h.prefix, attr)
+	}
+	h.attrs = text.String()
+	return h
+}
+
+// WithGroup returns handler with group name prepended to all attribute keys
+func (h printfHandler) WithGroup(name string) slog.Handler {
+	if name != "" {
+		h.prefix += name + "."
+	}
+	return h
+}
+
+// writeAttr writes attribute as key=value pair, string values with spaces or quotes are quoted
+func writeAttr(text *strings.Builder, prefix string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		for _, groupAttr := range attr.Value.Group() {
+			writeAttr(text, prefix, groupAttr)
+		}
+		return
+	}
+
+	value := attr.Value.String()
+	if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+		value = strconv.Quote(value)
+	}
+
+	_, _ = text.WriteString(" " + prefix + attr.Key + "=" + value)
+}
+
+// slogLogger is a [Logger] that writes to [slog.Logger]
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+// Debugf logs debug information
+func (l slogLogger) Debugf(format string, args ...any) {
+	if l.logger.Enabled(context.Background(), slog.LevelDebug) {
+		l.logger.Debug(fmt.Sprintf(format, args...))
+	}
+}
+
+// Errorf logs error information
+func (l slogLogger) Errorf(format string, args ...any) {
+	if l.logger.Enabled(context.Background(), slog.LevelError) {
+		l.logger.Error(fmt.Sprintf(format, args...))
+	}
+}
+
+// redactHandler is a [slog.Handler] that replaces sensitive text (like bot token) in messages and string attributes
+type redactHandler struct {
+	handler  slog.Handler
+	replacer *strings.Replacer
+}
+
+// Enabled reports if underlying handler handles records of the level
+func (h redactHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// Handle redacts record and passes it to underlying handler
+func (h redactHandler) Handle(ctx context.Context, record slog.Record) error {
+	redacted := slog.NewRecord(record.Time, record.Level, h.replacer.Replace(record.Message), record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		redacted.AddAttrs(h.redact(attr))
+		return true
+	})
+	return h.handler.Handle(ctx, redacted)
+}
+
+// WithAttrs returns handler with redacted attributes
+func (h redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, 0, len(attrs))
+	for _, attr := range attrs {
+		redacted = append(redacted, h.redact(attr))
+	}
+	h.handler = h.handler.WithAttrs(redacted)
+	return h
+}
+
+// WithGroup returns handler with group
+func (h redactHandler) WithGroup(name string) slog.Handler {
+	h.handler = h.handler.WithGroup(name)
+	return h
+}
+
+// redact replaces sensitive text in string, error and stringer values
+func (h redactHandler) redact(attr slog.Attr) slog.Attr {
+	attr.Value = attr.Value.Resolve()
+
+	switch attr.Value.Kind() { //nolint:exhaustive
+	case slog.KindString:
+		attr.Value = slog.StringValue(h.replacer.Replace(attr.Value.String()))
+	case slog.KindGroup:
+		group := attr.Value.Group()
+		redacted := make([]slog.Attr, 0, len(group))
+		for _, groupAttr := range group {
+			redacted = append(redacted, h.redact(groupAttr))
+		}
+		attr.Value = slog.GroupValue(redacted...)
+	case slog.KindAny:
+		switch value := attr.Value.Any().(type) {
+		case error:
+			attr.Value = slog.StringValue(h.replacer.Replace(value.Error()))
+		case fmt.Stringer:
+			attr.Value = slog.StringValue(h.replacer.Replace(value.String()))
+		}
+	}
+
+	return attr
+}
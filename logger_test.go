@@ -2,6 +2,8 @@ package telego
 
 import (
 	"bytes"
+	"errors"
+	"log/slog"
 	"os"
 	"strings"
 	"sync"
@@ -79,3 +81,58 @@ func Test_newDefaultLogger(t *testing.T) {
 	assert.True(t, l.PrintErrors)
 	assert.NotNil(t, l.Replacer)
 }
+
+func Test_printfHandler(t *testing.T) {
+	l, b := testLogger()
+	l.DebugMode = true
+	l.PrintErrors = true
+
+	log := slog.New(printfHandler{log: l}).With("a", 1).WithGroup("g")
+
+	log.Debug("debug", "b", "two words", slog.Group("c", "d", true))
+	assert.Contains(t, b.String(), "DEBUG")
+	assert.Contains(t, b.String(), `debug a=1 g.b="two words" g.c.d=true`)
+
+	b.Reset()
+	log.Warn("warn", "e", "")
+	assert.Contains(t, b.String(), "ERROR")
+	assert.Contains(t, b.String(), `warn a=1 g.e=""`)
+
+	assert.False(t, printfHandler{}.Enabled(t.Context(), slog.LevelError))
+
+	l.DebugMode = false
+	assert.False(t, log.Enabled(t.Context(), slog.LevelDebug))
+	assert.True(t, log.Enabled(t.Context(), slog.LevelError))
+
+	l.PrintErrors = false
+	assert.False(t, log.Enabled(t.Context(), slog.LevelWarn))
+
+	assert.True(t, printfHandler{log: &testLoggerType{}}.Enabled(t.Context(), slog.LevelDebug))
+}
+
+func Test_slogLogger(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	l := slogLogger{logger: slog.New(slog.NewTextHandler(buffer, &slog.HandlerOptions{Level: slog.LevelError}))}
+
+	l.Debugf(format, data1, data2)
+	assert.Empty(t, buffer.String())
+
+	l.Errorf(format, data1, data2)
+	assert.Contains(t, buffer.String(), "level=ERROR")
+	assert.Contains(t, buffer.String(), `msg="test ok"`)
+}
+
+func Test_redactHandler(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	log := slog.New(redactHandler{
+		handler:  slog.NewTextHandler(buffer, nil),
+		replacer: defaultReplacer(data1),
+	}).With("with", data1).WithGroup("g")
+
+	log.Info("message "+data1, "string", data1, "error", errors.New(data1), slog.Group("group", "value", data1),
+		"number", 1)
+
+	assert.NotContains(t, buffer.String(), data1)
+	assert.Contains(t, buffer.String(), `msg="message BOT_TOKEN" with=BOT_TOKEN g.string=BOT_TOKEN g.error=BOT_TOKEN `+
+		`g.group.value=BOT_TOKEN g.number=1`)
+}
@@ -0,0 +1,57 @@
+package telego
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mymmrac/telego/internal/textsplit"
+)
+
+// SendLongMessage sends message with text of any length, text and entities are split into chunks that fit into message
+// text limit (at paragraph, line, sentence or word boundaries if possible, but never inside entities that can't be
+// split, like custom emoji, code or mentions, unless they don't fit into one message) and sent in order, each chunk is
+// sent as a reply to the previous one. Reply parameters and message effect are used only for the first chunk, reply
+// markup only for the last one, other parameters are used for all chunks. On error, already sent messages are returned.
+//
+// Note: Parse mode is not supported, as formatted text can't be split safely, use entities instead
+func (b *Bot) SendLongMessage(ctx context.Context, params *SendMessageParams) ([]Message, error) {
+	if params == nil {
+		return nil, errors.New("telego: sendLongMessage: nil parameters")
+	}
+	if params.ParseMode != "" {
+		return nil, errors.New("telego: sendLongMessage: parse mode is not supported, use entities instead")
+	}
+
+	chunks := splitMessageText(params.Text, params.Entities, textsplit.MaxMessageTextLength)
+	messages := make([]Message, 0, len(chunks))
+
+	for i, chunk := range chunks {
+		chunkParams := *params
+		chunkParams.Text = chunk.Text
+		chunkParams.Entities = chunk.Entities
+
+		if i > 0 {
+			chunkParams.ReplyParameters = &ReplyParameters{MessageID: messages[i-1].MessageID}
+			chunkParams.MessageEffectID = ""
+		}
+		if i < len(chunks)-1 {
+			chunkParams.ReplyMarkup = nil
+		}
+
+		message, err := b.SendMessage(ctx, &chunkParams)
+		if err != nil {
+			return messages, fmt.Errorf("telego: sendLongMessage: chunk %d: %w", i+1, err)
+		}
+		messages = append(messages, *message)
+	}
+
+	return messages, nil
+}
+
+// splitMessageText splits text and its entities into chunks no longer than limit UTF-16 code units
+func splitMessageText(text string, entities []MessageEntity, limit int) []textsplit.EntitiesChunk[MessageEntity] {
+	return textsplit.SplitEntities(text, entities, limit, func(entity *MessageEntity) (*int, *int, string) {
+		return &entity.Offset, &entity.Length, entity.Type
+	})
+}
@@ -0,0 +1,122 @@
+package telego
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	ta "github.com/mymmrac/telego/telegoapi"
+)
+
+func TestBot_SendLongMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	m := newMockedBot(ctrl)
+
+	first := strings.Repeat("a", 3000)
+	second := strings.Repeat("b", 3000)
+	markup := &ReplyKeyboardRemove{RemoveKeyboard: true}
+	params := &SendMessageParams{
+		ChatID:          ChatID{ID: 1},
+		Text:            first + "\n\n" + second,
+		Entities:        []MessageEntity{{Type: EntityTypeBold, Offset: 2990, Length: 20}},
+		MessageEffectID: "effect",
+		ReplyParameters: &ReplyParameters{MessageID: 42},
+		ReplyMarkup:     markup,
+	}
+
+	t.Run("success", func(t *testing.T) {
+		var sent []*SendMessageParams
+		m.MockRequestConstructor.EXPECT().
+			JSONRequest(gomock.Any()).
+			DoAndReturn(func(parameters any) (*ta.RequestData, error) {
+				sent = append(sent, parameters.(*SendMessageParams))
+				return data, nil
+			}).
+			Times(2)
+
+		m.MockAPICaller.EXPECT().
+			Call(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(telegoResponse(t, &Message{MessageID: 1}), nil)
+		m.MockAPICaller.EXPECT().
+			Call(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(telegoResponse(t, &Message{MessageID: 2}), nil)
+
+		messages, err := m.Bot.SendLongMessage(t.Context(), params)
+		require.NoError(t, err)
+		require.Len(t, messages, 2)
+		require.Len(t, sent, 2)
+
+		assert.Equal(t, first, sent[0].Text)
+		assert.Equal(t, []MessageEntity{{Type: EntityTypeBold, Offset: 2990, Length: 10}}, sent[0].Entities)
+		assert.Equal(t, "effect", sent[0].MessageEffectID)
+		assert.Equal(t, 42, sent[0].ReplyParameters.MessageID)
+		assert.Nil(t, sent[0].ReplyMarkup)
+
+		assert.Equal(t, second, sent[1].Text)
+		assert.Equal(t, []MessageEntity{{Type: EntityTypeBold, Offset: 0, Length: 8}}, sent[1].Entities)
+		assert.Empty(t, sent[1].MessageEffectID)
+		assert.Equal(t, 1, sent[1].ReplyParameters.MessageID)
+		assert.Equal(t, markup, sent[1].ReplyMarkup)
+
+		assert.Equal(t, 42, params.ReplyParameters.MessageID)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		m.MockRequestConstructor.EXPECT().
+			JSONRequest(gomock.Any()).
+			Return(data, nil).
+			Times(2)
+
+		m.MockAPICaller.EXPECT().
+			Call(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(telegoResponse(t, &Message{MessageID: 1}), nil)
+		m.MockAPICaller.EXPECT().
+			Call(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil, errTest)
+
+		messages, err := m.Bot.SendLongMessage(t.Context(), params)
+		require.ErrorIs(t, err, errTest)
+		assert.Len(t, messages, 1)
+	})
+
+	t.Run("unsplittable_entity", func(t *testing.T) {
+		var sent []*SendMessageParams
+		m.MockRequestConstructor.EXPECT().
+			JSONRequest(gomock.Any()).
+			DoAndReturn(func(parameters any) (*ta.RequestData, error) {
+				sent = append(sent, parameters.(*SendMessageParams))
+				return data, nil
+			}).
+			Times(2)
+		m.MockAPICaller.EXPECT().
+			Call(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(telegoResponse(t, &Message{MessageID: 1}), nil).
+			Times(2)
+
+		text := strings.Repeat("a", 4094) + "👍🏻"
+		_, err := m.Bot.SendLongMessage(t.Context(), &SendMessageParams{
+			ChatID:   ChatID{ID: 1},
+			Text:     text,
+			Entities: []MessageEntity{{Type: EntityTypeCustomEmoji, Offset: 4094, Length: 4, CustomEmojiID: "1"}},
+		})
+		require.NoError(t, err)
+		require.Len(t, sent, 2)
+
+		assert.Equal(t, strings.Repeat("a", 4094), sent[0].Text)
+		assert.Empty(t, sent[0].Entities)
+		assert.Equal(t, "👍🏻", sent[1].Text)
+		assert.Equal(t, []MessageEntity{{Type: EntityTypeCustomEmoji, Offset: 0, Length: 4, CustomEmojiID: "1"}},
+			sent[1].Entities)
+	})
+
+	t.Run("error_params", func(t *testing.T) {
+		_, err := m.Bot.SendLongMessage(t.Context(), nil)
+		require.Error(t, err)
+
+		_, err = m.Bot.SendLongMessage(t.Context(), &SendMessageParams{Text: "text", ParseMode: ModeHTML})
+		require.Error(t, err)
+	})
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"slices"
 	"time"
+
+	tm "github.com/mymmrac/telego/telegometrics"
 )
 
 const (
@@ -124,16 +127,21 @@ func (b *Bot) doLongPolling(ctx context.Context, lp *longPolling, params *GetUpd
 		var updates []Update
 		updates, err := b.GetUpdates(ctx, params)
 		if err != nil {
-			b.log.Errorf("Getting updates: %s", err)
+			b.logger().ErrorContext(ctx, "Getting updates", slog.Any(logKeyError, err))
 			if lp.retryTimeout == 0 || errors.Is(err, context.Canceled) {
 				return
 			}
 
-			b.log.Errorf("Retrying getting updates in %s...", lp.retryTimeout.String())
+			b.logger().WarnContext(ctx, "Retrying getting updates", slog.Duration(logKeyRetryAfter, lp.retryTimeout))
 			time.Sleep(lp.retryTimeout)
 			continue
 		}
 
+		if len(updates) > 0 {
+			b.Instrumentation().AddCounter(tm.MetricUpdates, float64(len(updates)),
+				tm.L(tm.LabelSource, tm.SourceLongPolling))
+		}
+
 		for _, update := range updates {
 			if update.UpdateID >= params.Offset {
 				params.Offset = update.UpdateID + 1
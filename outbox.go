@@ -0,0 +1,353 @@
+package telego
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	ta "github.com/mymmrac/telego/telegoapi"
+)
+
+const (
+	defaultOutboxWorkers           = 8
+	defaultOutboxFloodWaitAttempts = 3
+)
+
+// ErrOutboxClosed returned for calls sent to already closed outbox
+var ErrOutboxClosed = errors.New("telego: outbox closed")
+
+// OutboxPriority represents priority class of outbox call, calls with lower value are executed first
+type OutboxPriority int
+
+// Outbox priorities
+const (
+	// OutboxPriorityInteractive used for calls that someone is waiting for, like replies to commands
+	OutboxPriorityInteractive OutboxPriority = iota
+	// OutboxPriorityBulk used for calls that can wait, like notifications or mass messaging
+	OutboxPriorityBulk
+)
+
+// OutboxCall represents API call executed by outbox
+type OutboxCall func(ctx context.Context, bot *Bot) (*Message, error)
+
+// OutboxFuture represents result of outbox call that will be available once call is done
+type OutboxFuture struct {
+	done    chan struct{}
+	message *Message
+	err     error
+}
+
+// newOutboxFuture creates new unresolved future
+func newOutboxFuture() *OutboxFuture {
+	return &OutboxFuture{
+		done: make(chan struct{}),
+	}
+}
+
+// resolve sets result of the future, must be called only once
+func (f *OutboxFuture) resolve(message *Message, err error) {
+	f.message = message
+	f.err = err
+	close(f.done)
+}
+
+// Done returns chan that will be closed once call is done
+func (f *OutboxFuture) Done() <-chan struct{} {
+	return f.done
+}
+
+// Wait waits for call to be done and returns its result, if context is done before that, context error is returned
+// Note: Canceling wait context doesn't cancel the call itself
+func (f *OutboxFuture) Wait(ctx context.Context) (*Message, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-f.done:
+		return f.message, f.err
+	}
+}
+
+// outboxItem represents queued call
+type outboxItem struct {
+	ctx      context.Context
+	call     OutboxCall
+	priority OutboxPriority
+	seq      uint64
+	attempts int
+	future   *OutboxFuture
+}
+
+// before reports if item should be executed before other item
+func (i *outboxItem) before(other *outboxItem) bool {
+	if i.priority != other.priority {
+		return i.priority < other.priority
+	}
+	return i.seq < other.seq
+}
+
+// outboxChat represents queue of calls to one chat
+type outboxChat struct {
+	items          []*outboxItem
+	busy           bool
+	throttledUntil time.Time
+}
+
+// enqueue inserts item keeping queue ordered by priority and then by order of sending
+func (c *outboxChat) enqueue(item *outboxItem) {
+	index := slices.IndexFunc(c.items, item.before)
+	if index == -1 {
+		c.items = append(c.items, item)
+		return
+	}
+	c.items = slices.Insert(c.items, index, item)
+}
+
+// Outbox represents ordered queue of outgoing API calls. Calls to the same chat are executed one by one in order
+// of sending (calls with higher priority are executed first), while calls to different chats are executed in parallel.
+// If a call fails because of flood control (429 Too Many Requests with retry after), only its chat is paused until
+// the limit resets and then call is retried, calls to other chats continue to be executed.
+//
+// Note: To let outbox handle flood waits, [ta.RetryCaller] (if used) should not wait for rate limit reset
+// ([ta.RetryRateLimitSkip] or [ta.RetryRateLimitAbort]), otherwise waiting call will occupy a worker
+type Outbox struct {
+	bot               *Bot
+	workers           int
+	floodWaitAttempts int
+
+	mutex  sync.Mutex
+	chats  map[string]*outboxChat
+	active int
+	seq    uint64
+	closed bool
+	notify chan struct{}
+	done   chan struct{}
+}
+
+// OutboxOption represents an option that can be applied to outbox
+type OutboxOption func(o *Outbox) error
+
+// WithOutboxWorkers sets the max number of calls executed in parallel. Default is 8.
+func WithOutboxWorkers(workers int) OutboxOption {
+	return func(o *Outbox) error {
+		if workers <= 0 {
+			return fmt.Errorf("workers number is not positive: %d", workers)
+		}
+		o.workers = workers
+		return nil
+	}
+}
+
+// WithOutboxFloodWaitAttempts sets the max number of times call will be retried after hitting flood control, zero
+// disables retries. Default is 3.
+func WithOutboxFloodWaitAttempts(attempts int) OutboxOption {
+	return func(o *Outbox) error {
+		if attempts < 0 {
+			return fmt.Errorf("flood wait attempts is negative: %d", attempts)
+		}
+		o.floodWaitAttempts = attempts
+		return nil
+	}
+}
+
+// NewOutbox creates and starts new outbox for bot, [Outbox.Close] should be called once outbox is no longer needed
+func NewOutbox(bot *Bot, options ...OutboxOption) (*Outbox, error) {
+	o := &Outbox{
+		bot:               bot,
+		workers:           defaultOutboxWorkers,
+		floodWaitAttempts: defaultOutboxFloodWaitAttempts,
+		chats:             make(map[string]*outboxChat),
+		notify:            make(chan struct{}, 1),
+		done:              make(chan struct{}),
+	}
+
+	for _, option := range options {
+		if err := option(o); err != nil {
+			return nil, fmt.Errorf("telego: outbox options: %w", err)
+		}
+	}
+
+	go o.dispatch()
+
+	return o, nil
+}
+
+// Send queues call to chat with provided priority, context is used for call execution, if it's done before call
+// started, call will be skipped with context error as a result
+func (o *Outbox) Send(ctx context.Context, chatID ChatID, priority OutboxPriority, call OutboxCall) *OutboxFuture {
+	future := newOutboxFuture()
+
+	o.mutex.Lock()
+	if o.closed {
+		o.mutex.Unlock()
+		future.resolve(nil, ErrOutboxClosed)
+		return future
+	}
+
+	o.seq++
+	item := &outboxItem{
+		ctx:      ctx,
+		call:     call,
+		priority: priority,
+		seq:      o.seq,
+		future:   future,
+	}
+
+	key := chatID.String()
+	chat, ok := o.chats[key]
+	if !ok {
+		chat = &outboxChat{}
+		o.chats[key] = chat
+	}
+	chat.enqueue(item)
+	o.mutex.Unlock()
+
+	o.wake()
+	return future
+}
+
+// SendMessage queues [Bot.SendMessage] call with provided priority
+// Note: Params should not be modified until call is done
+func (o *Outbox) SendMessage(ctx context.Context, priority OutboxPriority, params *SendMessageParams) *OutboxFuture {
+	return o.Send(ctx, params.ChatID, priority, func(ctx context.Context, bot *Bot) (*Message, error) {
+		return bot.SendMessage(ctx, params)
+	})
+}
+
+// Close stops accepting new calls and blocks until all already queued calls are done
+func (o *Outbox) Close() {
+	o.mutex.Lock()
+	o.closed = true
+	o.mutex.Unlock()
+
+	o.wake()
+	<-o.done
+}
+
+// wake notifies dispatcher that state has changed
+func (o *Outbox) wake() {
+	select {
+	case o.notify <- struct{}{}:
+	default:
+		// Dispatcher already notified
+	}
+}
+
+// dispatch starts calls as soon as their chats are ready and there are free workers
+func (o *Outbox) dispatch() {
+	defer close(o.done)
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		o.mutex.Lock()
+
+		var wakeAt time.Time
+		for o.active < o.workers {
+			var chat *outboxChat
+			chat, wakeAt = o.next(time.Now())
+			if chat == nil {
+				break
+			}
+
+			item := chat.items[0]
+			chat.items = chat.items[1:]
+			chat.busy = true
+			o.active++
+
+			go o.run(chat, item)
+		}
+
+		if o.closed && o.active == 0 && !o.pending() {
+			o.mutex.Unlock()
+			return
+		}
+
+		o.mutex.Unlock()
+
+		var timerC <-chan time.Time
+		if !wakeAt.IsZero() {
+			timer.Reset(time.Until(wakeAt))
+			timerC = timer.C
+		}
+
+		select {
+		case <-o.notify:
+			timer.Stop()
+		case <-timerC:
+			// Throttled chat is ready
+		}
+	}
+}
+
+// next returns chat which call should be executed next and the earliest time when throttled chat will be ready,
+// chats with no calls are removed
+func (o *Outbox) next(now time.Time) (*outboxChat, time.Time) {
+	var next *outboxChat
+	var wakeAt time.Time
+
+	for key, chat := range o.chats {
+		throttled := chat.throttledUntil.After(now)
+
+		if len(chat.items) == 0 {
+			if !chat.busy && !throttled {
+				delete(o.chats, key)
+			}
+			continue
+		}
+
+		if chat.busy {
+			continue
+		}
+
+		if throttled {
+			if wakeAt.IsZero() || chat.throttledUntil.Before(wakeAt) {
+				wakeAt = chat.throttledUntil
+			}
+			continue
+		}
+
+		if next == nil || chat.items[0].before(next.items[0]) {
+			next = chat
+		}
+	}
+
+	return next, wakeAt
+}
+
+// pending reports if there are queued calls
+func (o *Outbox) pending() bool {
+	for _, chat := range o.chats {
+		if len(chat.items) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// run executes call and either resolves its future or queues it again if flood wait happened
+func (o *Outbox) run(chat *outboxChat, item *outboxItem) {
+	var message *Message
+	err := item.ctx.Err()
+	if err == nil {
+		message, err = item.call(item.ctx, o.bot)
+	}
+
+	o.mutex.Lock()
+	chat.busy = false
+	o.active--
+
+	if retryAfter, ok := ta.RetryAfter(err); ok && item.attempts < o.floodWaitAttempts {
+		item.attempts++
+		chat.throttledUntil = time.Now().Add(retryAfter)
+		chat.enqueue(item)
+	} else {
+		item.future.resolve(message, err)
+	}
+	o.mutex.Unlock()
+
+	o.wake()
+}
@@ -0,0 +1,199 @@
+package telego
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	ta "github.com/mymmrac/telego/telegoapi"
+)
+
+func TestNewOutbox(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		outbox, err := NewOutbox(&Bot{}, WithOutboxWorkers(1), WithOutboxFloodWaitAttempts(0))
+		require.NoError(t, err)
+		assert.Equal(t, 1, outbox.workers)
+		assert.Equal(t, 0, outbox.floodWaitAttempts)
+		outbox.Close()
+	})
+
+	t.Run("error_workers", func(t *testing.T) {
+		outbox, err := NewOutbox(&Bot{}, WithOutboxWorkers(0))
+		require.Error(t, err)
+		assert.Nil(t, outbox)
+	})
+
+	t.Run("error_flood_wait_attempts", func(t *testing.T) {
+		outbox, err := NewOutbox(&Bot{}, WithOutboxFloodWaitAttempts(-1))
+		require.Error(t, err)
+		assert.Nil(t, outbox)
+	})
+}
+
+func TestOutbox_Send(t *testing.T) {
+	ctx := t.Context()
+
+	t.Run("order_in_chat", func(t *testing.T) {
+		outbox, err := NewOutbox(&Bot{})
+		require.NoError(t, err)
+
+		var order []int
+		futures := make([]*OutboxFuture, 0, 10)
+		for i := range 10 {
+			futures = append(futures, outbox.Send(ctx, ChatID{ID: 1}, OutboxPriorityBulk,
+				func(_ context.Context, _ *Bot) (*Message, error) {
+					order = append(order, i)
+					return &Message{MessageID: i}, nil
+				},
+			))
+		}
+
+		for i, future := range futures {
+			message, err := future.Wait(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, i, message.MessageID)
+		}
+		assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, order)
+
+		outbox.Close()
+	})
+
+	t.Run("priority", func(t *testing.T) {
+		outbox, err := NewOutbox(&Bot{}, WithOutboxWorkers(1))
+		require.NoError(t, err)
+
+		release := make(chan struct{})
+		started := make(chan struct{})
+		outbox.Send(ctx, ChatID{ID: 1}, OutboxPriorityBulk, func(_ context.Context, _ *Bot) (*Message, error) {
+			close(started)
+			<-release
+			return &Message{}, nil
+		})
+		<-started
+
+		var lock sync.Mutex
+		var order []string
+		record := func(name string) OutboxCall {
+			return func(_ context.Context, _ *Bot) (*Message, error) {
+				lock.Lock()
+				order = append(order, name)
+				lock.Unlock()
+				return &Message{}, nil
+			}
+		}
+
+		bulk := outbox.Send(ctx, ChatID{ID: 2}, OutboxPriorityBulk, record("bulk"))
+		interactive := outbox.Send(ctx, ChatID{Username: "@test"}, OutboxPriorityInteractive, record("interactive"))
+		close(release)
+
+		_, err = bulk.Wait(ctx)
+		require.NoError(t, err)
+		_, err = interactive.Wait(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"interactive", "bulk"}, order)
+
+		outbox.Close()
+	})
+
+	t.Run("flood_wait", func(t *testing.T) {
+		outbox, err := NewOutbox(&Bot{}, WithOutboxFloodWaitAttempts(1))
+		require.NoError(t, err)
+
+		floodErr := &ta.Error{ErrorCode: 429, Parameters: &ta.ResponseParameters{RetryAfter: 0}}
+
+		attempts := 0
+		future := outbox.Send(ctx, ChatID{ID: 1}, OutboxPriorityBulk, func(context.Context, *Bot) (*Message, error) {
+			attempts++
+			if attempts == 1 {
+				return nil, floodErr
+			}
+			return &Message{MessageID: 1}, nil
+		})
+
+		message, err := future.Wait(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, message.MessageID)
+		assert.Equal(t, 2, attempts)
+
+		future = outbox.Send(ctx, ChatID{ID: 1}, OutboxPriorityBulk, func(_ context.Context, _ *Bot) (*Message, error) {
+			return nil, floodErr
+		})
+
+		message, err = future.Wait(ctx)
+		require.ErrorIs(t, err, floodErr)
+		assert.Nil(t, message)
+
+		outbox.Close()
+	})
+
+	t.Run("error_canceled", func(t *testing.T) {
+		outbox, err := NewOutbox(&Bot{})
+		require.NoError(t, err)
+
+		canceledCtx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		future := outbox.Send(canceledCtx, ChatID{ID: 1}, OutboxPriorityBulk,
+			func(context.Context, *Bot) (*Message, error) {
+				panic("unreachable")
+			},
+		)
+
+		<-future.Done()
+		message, err := future.Wait(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, message)
+
+		outbox.Close()
+	})
+
+	t.Run("error_closed", func(t *testing.T) {
+		outbox, err := NewOutbox(&Bot{})
+		require.NoError(t, err)
+		outbox.Close()
+
+		future := outbox.Send(ctx, ChatID{ID: 1}, OutboxPriorityBulk, func(context.Context, *Bot) (*Message, error) {
+			panic("unreachable")
+		})
+
+		_, err = future.Wait(ctx)
+		require.ErrorIs(t, err, ErrOutboxClosed)
+	})
+}
+
+func TestOutbox_SendMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	m := newMockedBot(ctrl)
+	ctx := t.Context()
+
+	m.MockRequestConstructor.EXPECT().
+		JSONRequest(gomock.Any()).
+		Return(data, nil)
+
+	m.MockAPICaller.EXPECT().
+		Call(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(telegoResponse(t, expectedMessage), nil)
+
+	outbox, err := NewOutbox(m.Bot)
+	require.NoError(t, err)
+
+	message, err := outbox.SendMessage(ctx, OutboxPriorityInteractive, &SendMessageParams{}).Wait(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, expectedMessage, message)
+
+	outbox.Close()
+}
+
+func TestOutboxFuture_Wait(t *testing.T) {
+	ctx, cancel := context.WithTimeout(t.Context(), time.Millisecond)
+	defer cancel()
+
+	message, err := newOutboxFuture().Wait(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Nil(t, message)
+}
@@ -11,6 +11,8 @@ import (
 const (
 	// ContentTypeHeader http content type header
 	ContentTypeHeader = "Content-Type"
+	// RangeHeader http range header
+	RangeHeader = "Range"
 
 	// ContentTypeJSON http JSON content type
 	ContentTypeJSON = "application/json"
@@ -76,6 +78,24 @@ type Caller interface {
 	Call(ctx context.Context, url string, data *RequestData) (*Response, error)
 }
 
+// DownloadResponse represents response of file download, body must be closed
+type DownloadResponse struct {
+	// StatusCode HTTP status code
+	StatusCode int
+	// ContentLength size of the body in bytes, -1 if unknown
+	ContentLength int64
+	// Body file data stream
+	Body io.ReadCloser
+}
+
+// Downloader represents way to download files, implemented by [FastHTTPCaller], [HTTPCaller] and caller decorators
+// (if underlying caller implements it)
+type Downloader interface {
+	// Download makes GET request to the URL and returns streamed response, if offset is positive, only data starting
+	// from the offset is requested using range header
+	Download(ctx context.Context, url string, offset int64) (*DownloadResponse, error)
+}
+
 // NamedReader represents a way to send files (or other data).
 // Implemented by [os.File].
 // Note: Name method may be called multiple times and should return unique names for all files sent in one request.
@@ -0,0 +1,105 @@
+package telegoapi
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// CallPriority represents priority of API call used by [RateLimitCaller]
+type CallPriority int
+
+const (
+	// CallPriorityNormal default priority, call reserves the earliest time slot allowed by rate limits
+	CallPriorityNormal CallPriority = iota
+	// CallPriorityLow call doesn't reserve future time slots, instead it waits until rate limits allow sending it
+	// right away, so calls with normal priority made later may be sent first
+	CallPriorityLow
+)
+
+// CallOptions represents options of a single API call, they are attached to the context using [WithCallOptions]
+type CallOptions struct {
+	// Timeout of each HTTP request, used by [FastHTTPCaller] and [HTTPCaller], zero means no timeout
+	Timeout time.Duration
+	// MaxAttempts overrides [RetryCaller.MaxAttempts], one disables retries, zero means no override
+	MaxAttempts int
+	// Priority of the call, used by [RateLimitCaller]
+	Priority CallPriority
+	// APIServer overrides bot API server URL, empty means no override
+	APIServer string
+}
+
+// CallOption represents an option that can be applied to [CallOptions]
+type CallOption func(options *CallOptions)
+
+// callOptionsKey context key of call options
+type callOptionsKey struct{}
+
+// WithCallOptions returns context with options applied to API calls made with it, options are applied after ones
+// already attached to the context
+func WithCallOptions(ctx context.Context, options ...CallOption) context.Context {
+	if len(options) == 0 {
+		return ctx
+	}
+
+	existing, _ := ctx.Value(callOptionsKey{}).([]CallOption)
+	return context.WithValue(ctx, callOptionsKey{}, append(slices.Clip(existing), options...))
+}
+
+// WithDefaultCallOptions returns context with options applied to API calls made with it, options are applied before
+// ones already attached to the context, so they can be used as defaults
+func WithDefaultCallOptions(ctx context.Context, options ...CallOption) context.Context {
+	if len(options) == 0 {
+		return ctx
+	}
+
+	existing, _ := ctx.Value(callOptionsKey{}).([]CallOption)
+	return context.WithValue(ctx, callOptionsKey{}, append(slices.Clip(options), existing...))
+}
+
+// CallOptionsFromContext returns options of API call attached to the context
+func CallOptionsFromContext(ctx context.Context) CallOptions {
+	var callOptions CallOptions
+
+	options, _ := ctx.Value(callOptionsKey{}).([]CallOption)
+	for _, option := range options {
+		if option != nil {
+			option(&callOptions)
+		}
+	}
+
+	return callOptions
+}
+
+// CallTimeout sets timeout of each HTTP request
+func CallTimeout(timeout time.Duration) CallOption {
+	return func(options *CallOptions) {
+		options.Timeout = timeout
+	}
+}
+
+// CallMaxAttempts sets max number of attempts made by [RetryCaller]
+func CallMaxAttempts(maxAttempts int) CallOption {
+	return func(options *CallOptions) {
+		options.MaxAttempts = maxAttempts
+	}
+}
+
+// NoRetry disables retries made by [RetryCaller]
+func NoRetry() CallOption {
+	return CallMaxAttempts(1)
+}
+
+// Priority sets priority of the call
+func Priority(priority CallPriority) CallOption {
+	return func(options *CallOptions) {
+		options.Priority = priority
+	}
+}
+
+// CallAPIServer sets bot API server URL used for the call
+func CallAPIServer(apiURL string) CallOption {
+	return func(options *CallOptions) {
+		options.APIServer = apiURL
+	}
+}
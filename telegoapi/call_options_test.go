@@ -0,0 +1,29 @@
+package telegoapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCallOptionsFromContext(t *testing.T) {
+	ctx := t.Context()
+	assert.Equal(t, CallOptions{}, CallOptionsFromContext(ctx))
+	assert.Equal(t, ctx, WithCallOptions(ctx))
+	assert.Equal(t, ctx, WithDefaultCallOptions(ctx))
+
+	ctx = WithCallOptions(ctx, CallTimeout(time.Second), nil, Priority(CallPriorityLow))
+	ctx = WithCallOptions(ctx, CallAPIServer("https://example.com"))
+	ctx = WithDefaultCallOptions(ctx, NoRetry(), CallTimeout(time.Minute), CallAPIServer("https://default.com"))
+
+	assert.Equal(t, CallOptions{
+		Timeout:     time.Second,
+		MaxAttempts: 1,
+		Priority:    CallPriorityLow,
+		APIServer:   "https://example.com",
+	}, CallOptionsFromContext(ctx))
+
+	ctx = WithCallOptions(ctx, CallMaxAttempts(5))
+	assert.Equal(t, 5, CallOptionsFromContext(ctx).MaxAttempts)
+}
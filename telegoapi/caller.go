@@ -10,11 +10,14 @@ import (
 	"io"
 	"math"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/valyala/fasthttp"
 
 	"github.com/mymmrac/telego/internal/json"
+	"github.com/mymmrac/telego/telegometrics"
 )
 
 // FastHTTPCaller fasthttp implementation of [Caller]
@@ -27,7 +30,7 @@ var DefaultFastHTTPCaller = &FastHTTPCaller{
 	Client: &fasthttp.Client{},
 }
 
-// Call is a fasthttp implementation
+// Call is a fasthttp implementation, request timeout is set by context deadline or [CallTimeout] call option
 func (a FastHTTPCaller) Call(ctx context.Context, url string, data *RequestData) (*Response, error) {
 	select {
 	case <-ctx.Done():
@@ -57,6 +60,11 @@ func (a FastHTTPCaller) Call(ctx context.Context, url string, data *RequestData)
 
 	var err error
 	deadline, ok := ctx.Deadline()
+	if timeout := CallOptionsFromContext(ctx).Timeout; timeout > 0 {
+		if timeoutDeadline := time.Now().Add(timeout); !ok || timeoutDeadline.Before(deadline) {
+			deadline, ok = timeoutDeadline, true
+		}
+	}
 	if ok {
 		err = a.Client.DoDeadline(request, response, deadline)
 	} else {
@@ -79,6 +87,73 @@ func (a FastHTTPCaller) Call(ctx context.Context, url string, data *RequestData)
 	return apiResp, nil
 }
 
+// Download is a fasthttp implementation of [Downloader], response body is streamed
+func (a FastHTTPCaller) Download(ctx context.Context, url string, offset int64) (*DownloadResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+		// Continue
+	}
+
+	request := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(request)
+
+	request.SetRequestURI(url)
+	request.Header.SetMethod(fasthttp.MethodGet)
+	if offset > 0 {
+		request.Header.Set(RangeHeader, rangeFrom(offset))
+	}
+
+	response := fasthttp.AcquireResponse()
+	response.StreamBody = true
+
+	var err error
+	deadline, ok := ctx.Deadline()
+	if ok {
+		err = a.Client.DoDeadline(request, response, deadline)
+	} else {
+		err = a.Client.Do(request, response)
+	}
+	if err != nil {
+		fasthttp.ReleaseResponse(response)
+		return nil, fmt.Errorf("fasthttp do request: %w", err)
+	}
+
+	body := response.BodyStream()
+	if body == nil {
+		body = bytes.NewReader(response.Body())
+	}
+
+	return &DownloadResponse{
+		StatusCode:    response.StatusCode(),
+		ContentLength: int64(response.Header.ContentLength()),
+		Body: fastHTTPBody{
+			Reader:   body,
+			response: response,
+		},
+	}, nil
+}
+
+// fastHTTPBody represents streamed fasthttp response body
+type fastHTTPBody struct {
+	io.Reader
+	response *fasthttp.Response
+}
+
+// Close closes body stream and releases response
+func (f fastHTTPBody) Close() error {
+	err := f.response.CloseBodyStream()
+	fasthttp.ReleaseResponse(f.response)
+	if err != nil {
+		return fmt.Errorf("close body stream: %w", err)
+	}
+	return nil
+}
+
+// ErrDownloadNotSupported returned when caller doesn't support downloading files
+var ErrDownloadNotSupported = errors.New("download not supported by caller")
+
 // HTTPCaller http implementation of [Caller]
 type HTTPCaller struct {
 	Client *http.Client
@@ -89,7 +164,7 @@ var DefaultHTTPCaller = &HTTPCaller{
 	Client: http.DefaultClient,
 }
 
-// Call is an http implementation
+// Call is an http implementation, request timeout is set by context deadline or [CallTimeout] call option
 func (h HTTPCaller) Call(ctx context.Context, url string, data *RequestData) (*Response, error) {
 	var requestBody io.Reader
 	switch {
@@ -101,6 +176,12 @@ func (h HTTPCaller) Call(ctx context.Context, url string, data *RequestData) (*R
 		return nil, errors.New("body is not provided")
 	}
 
+	if timeout := CallOptionsFromContext(ctx).Timeout; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
 	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, requestBody)
 	if err != nil {
 		return nil, fmt.Errorf("http create request: %w", err)
@@ -131,8 +212,36 @@ func (h HTTPCaller) Call(ctx context.Context, url string, data *RequestData) (*R
 	return apiResp, nil
 }
 
+// Download is an http implementation of [Downloader]
+func (h HTTPCaller) Download(ctx context.Context, url string, offset int64) (*DownloadResponse, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("http create request: %w", err)
+	}
+	if offset > 0 {
+		request.Header.Set(RangeHeader, rangeFrom(offset))
+	}
+
+	response, err := h.Client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("http do request: %w", err)
+	}
+
+	return &DownloadResponse{
+		StatusCode:    response.StatusCode,
+		ContentLength: response.ContentLength,
+		Body:          response.Body,
+	}, nil
+}
+
+// rangeFrom returns range header value that requests data from offset to the end
+func rangeFrom(offset int64) string {
+	return "bytes=" + strconv.FormatInt(offset, 10) + "-"
+}
+
 // RetryCaller decorator over [Caller] that provides retries with exponential backoff
 // Depending on [RetryRateLimit] will wait for rate limit timeout to reset or abort, defaults to do nothing
+// Max attempts can be overridden per call using [CallMaxAttempts] or [NoRetry] call options
 // Delay = min((ExponentBase ^ AttemptNumber) * StartDelay, MaxDelay)
 type RetryCaller struct {
 	// Underling caller
@@ -152,6 +261,17 @@ type RetryCaller struct {
 	//
 	// Warning: Enabling this may lead to excessive memory consumption and OOMKill
 	BufferRequestData bool
+	// Instrumentation used to report retries, optional
+	Instrumentation telegometrics.Instrumentation
+}
+
+// Download downloads file using provided caller if it implements [Downloader], download is not retried
+func (r *RetryCaller) Download(ctx context.Context, url string, offset int64) (*DownloadResponse, error) {
+	downloader, ok := r.Caller.(Downloader)
+	if !ok {
+		return nil, ErrDownloadNotSupported
+	}
+	return downloader.Download(ctx, url, offset)
 }
 
 // RetryRateLimit mode for handling rate limits
@@ -181,7 +301,12 @@ func (r *RetryCaller) Call(ctx context.Context, url string, data *RequestData) (
 		data.BodyStream = nil
 	}
 
-	for i := 0; i < r.MaxAttempts; i++ {
+	maxAttempts := r.MaxAttempts
+	if attempts := CallOptionsFromContext(ctx).MaxAttempts; attempts > 0 {
+		maxAttempts = attempts
+	}
+
+	for i := 0; i < maxAttempts; i++ {
 		response, err = r.Caller.Call(ctx, url, data)
 		if err == nil && (response.Error == nil || response.ErrorCode == 0) {
 			return response, nil
@@ -190,7 +315,7 @@ func (r *RetryCaller) Call(ctx context.Context, url string, data *RequestData) (
 			err = response.Error
 		}
 
-		if i == r.MaxAttempts-1 {
+		if i == maxAttempts-1 {
 			break
 		}
 
@@ -202,6 +327,11 @@ func (r *RetryCaller) Call(ctx context.Context, url string, data *RequestData) (
 			delay = min(time.Duration(math.Pow(r.ExponentBase, float64(i)))*r.StartDelay, r.MaxDelay)
 		}
 
+		if r.Instrumentation != nil {
+			r.Instrumentation.AddCounter(telegometrics.MetricAPIRetries, 1,
+				telegometrics.L(telegometrics.LabelMethod, methodFromURL(url)))
+		}
+
 		select {
 		case <-ctx.Done():
 			return nil, errors.Join(err, ctx.Err())
@@ -213,6 +343,11 @@ func (r *RetryCaller) Call(ctx context.Context, url string, data *RequestData) (
 	return nil, errors.Join(err, ErrMaxRetryAttempts)
 }
 
+// methodFromURL returns API method name from request URL
+func methodFromURL(url string) string {
+	return url[strings.LastIndexByte(url, '/')+1:]
+}
+
 func (r *RetryCaller) handleError(err error) (time.Duration, bool) {
 	var apiErr *Error
 	if !errors.As(err, &apiErr) {
@@ -4,20 +4,26 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"io"
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/valyala/fasthttp"
 	"github.com/valyala/fasthttp/fasthttputil"
+
+	"github.com/mymmrac/telego/telegometrics"
 )
 
 const (
 	errJSONPath = "/json_err"
 	err500Path  = "/500"
+	slowPath    = "/slow"
 )
 
 var _ Caller = DefaultFastHTTPCaller
@@ -82,6 +88,13 @@ func TestFastHTTPCaller_Call(t *testing.T) {
 		require.Error(t, err)
 		assert.Nil(t, resp)
 	})
+
+	t.Run("error_timeout", func(t *testing.T) {
+		timeoutCtx := WithCallOptions(ctx, CallTimeout(time.Millisecond*10))
+		resp, err := caller.Call(timeoutCtx, "http://localhost"+slowPath, data)
+		require.ErrorIs(t, err, fasthttp.ErrTimeout)
+		assert.Nil(t, resp)
+	})
 }
 
 type fasthttpServer struct {
@@ -93,6 +106,9 @@ func (s *fasthttpServer) Handle(ctx *fasthttp.RequestCtx) {
 	assert.Equal(s.t, ContentTypeJSON, string(ctx.Request.Header.ContentType())) //nolint:testifylint
 
 	switch string(ctx.Path()) {
+	case slowPath:
+		time.Sleep(time.Millisecond * 200)
+		ctx.SetStatusCode(fasthttp.StatusOK)
 	case err500Path:
 		ctx.SetStatusCode(fasthttp.StatusInternalServerError)
 	case errJSONPath:
@@ -154,6 +170,13 @@ func TestHTTPCaller_Call(t *testing.T) {
 		require.Error(t, err)
 		assert.Nil(t, resp)
 	})
+
+	t.Run("error_timeout", func(t *testing.T) {
+		timeoutCtx := WithCallOptions(ctx, CallTimeout(time.Millisecond*10))
+		resp, err := caller.Call(timeoutCtx, srv.URL+slowPath, data)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Nil(t, resp)
+	})
 }
 
 type httpServer struct {
@@ -165,6 +188,9 @@ func (h *httpServer) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
 	assert.Equal(h.t, ContentTypeJSON, req.Header.Get(ContentTypeHeader)) //nolint:testifylint
 
 	switch req.RequestURI {
+	case slowPath:
+		time.Sleep(time.Millisecond * 200)
+		resp.WriteHeader(http.StatusOK)
 	case err500Path:
 		resp.WriteHeader(http.StatusInternalServerError)
 	case errJSONPath:
@@ -253,6 +279,42 @@ func TestRetryCaller_Call(t *testing.T) {
 		assert.Nil(t, resp)
 	})
 
+	t.Run("error_no_retry", func(t *testing.T) {
+		caller := &testRetryCaller{
+			resp: nil,
+			err:  errors.New("test"),
+		}
+		retryCaller := &RetryCaller{
+			Caller:      caller,
+			MaxAttempts: 3,
+		}
+		resp, err := retryCaller.Call(WithCallOptions(ctx, NoRetry()), "", &RequestData{})
+		require.ErrorIs(t, err, ErrMaxRetryAttempts)
+		assert.Nil(t, resp)
+		assert.Equal(t, 1, caller.attempts)
+	})
+
+	t.Run("error_retry_instrumentation", func(t *testing.T) {
+		exporter := telegometrics.NewPrometheusExporter()
+		retryCaller := &RetryCaller{
+			Caller: &testRetryCaller{
+				resp: nil,
+				err:  errors.New("test"),
+			},
+			MaxAttempts:     3,
+			Instrumentation: exporter,
+		}
+
+		resp, err := retryCaller.Call(ctx, "https://api.telegram.org/bot123/getMe", &RequestData{})
+		require.Error(t, err)
+		assert.Nil(t, resp)
+
+		text := &strings.Builder{}
+		_, err = exporter.WriteTo(text)
+		require.NoError(t, err)
+		assert.Contains(t, text.String(), `telego_api_retries_total{method="getMe"} 2`)
+	})
+
 	t.Run("max_delay", func(t *testing.T) {
 		retryCaller := &RetryCaller{
 			Caller: &testRetryCaller{
@@ -367,3 +429,74 @@ func TestRetryCaller_Call(t *testing.T) {
 		assert.Nil(t, resp)
 	})
 }
+
+var (
+	_ Downloader = FastHTTPCaller{}
+	_ Downloader = HTTPCaller{}
+	_ Downloader = &RetryCaller{}
+	_ Downloader = &RateLimitCaller{}
+)
+
+const downloadData = "0123456789"
+
+func testDownload(t *testing.T, downloader Downloader, url string) {
+	t.Helper()
+
+	t.Run("success", func(t *testing.T) {
+		resp, err := downloader.Download(t.Context(), url, 0)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.EqualValues(t, len(downloadData), resp.ContentLength)
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		assert.Equal(t, downloadData, string(body))
+	})
+
+	t.Run("offset", func(t *testing.T) {
+		resp, err := downloader.Download(t.Context(), url, 4)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusPartialContent, resp.StatusCode)
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		assert.Equal(t, downloadData[4:], string(body))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		resp, err := downloader.Download(t.Context(), "abc", 0)
+		require.Error(t, err)
+		assert.Nil(t, resp)
+	})
+}
+
+func downloadHandler(writer http.ResponseWriter, request *http.Request) {
+	http.ServeContent(writer, request, "file", time.Time{}, strings.NewReader(downloadData))
+}
+
+func TestHTTPCaller_Download(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(downloadHandler))
+	defer srv.Close()
+
+	testDownload(t, HTTPCaller{Client: srv.Client()}, srv.URL)
+}
+
+func TestFastHTTPCaller_Download(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(downloadHandler))
+	defer srv.Close()
+
+	testDownload(t, FastHTTPCaller{Client: &fasthttp.Client{}}, srv.URL)
+}
+
+func TestRetryCaller_Download(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(downloadHandler))
+	defer srv.Close()
+
+	testDownload(t, &RetryCaller{Caller: HTTPCaller{Client: srv.Client()}}, srv.URL)
+
+	resp, err := (&RetryCaller{Caller: &testRetryCaller{}}).Download(t.Context(), srv.URL, 0)
+	require.ErrorIs(t, err, ErrDownloadNotSupported)
+	assert.Nil(t, resp)
+}
@@ -0,0 +1,375 @@
+package telegoapi
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/mymmrac/telego/internal/json"
+)
+
+// CassetteTokenReplacement used instead of bot token in recorded URLs
+const CassetteTokenReplacement = "BOT_TOKEN"
+
+// ErrCassetteMismatch returned when replayed call doesn't match any recorded interaction
+var ErrCassetteMismatch = errors.New("call doesn't match cassette")
+
+// tokenPath matches bot token in URL path segment right before method name (and test environment segment)
+var tokenPath = regexp.MustCompile(`/bot([^/]+)/(?:test/)?[^/]+$`)
+
+// CassetteInteraction represents one recorded API call, stored as one line of JSONL cassette
+type CassetteInteraction struct {
+	// URL of the call with bot token redacted
+	URL string `json:"url"`
+	// Method name of the call
+	Method string `json:"method"`
+	// ContentType of the request
+	ContentType string `json:"content_type"`
+	// Body of JSON request
+	Body json.RawMessage `json:"body,omitempty"`
+	// Fields of multipart request
+	Fields map[string]string `json:"fields,omitempty"`
+	// Files of multipart request, field name to file name
+	Files map[string]string `json:"files,omitempty"`
+	// Response returned by API, nil if call failed
+	Response *Response `json:"response,omitempty"`
+	// Error returned by caller, empty if call succeeded
+	Error string `json:"error,omitempty"`
+}
+
+// params returns normalized parameters of the call, JSON encoded values are decoded, so formatting and order of
+// keys doesn't matter
+func (c *CassetteInteraction) params() map[string]any {
+	params := make(map[string]any)
+
+	if len(c.Body) > 0 {
+		_ = json.Unmarshal(c.Body, &params)
+	}
+
+	for field, value := range c.Fields {
+		var decoded any
+		if err := json.Unmarshal([]byte(value), &decoded); err == nil {
+			params[field] = decoded
+		} else {
+			params[field] = value
+		}
+	}
+
+	for field, name := range c.Files {
+		params[field] = "file:" + name
+	}
+
+	return params
+}
+
+// cassetteToken returns bot token from URL of the call, empty if URL doesn't contain it
+func cassetteToken(url string) string {
+	match := tokenPath.FindStringSubmatch(url)
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
+
+// redactToken replaces bot token in text
+func redactToken(text, token string) string {
+	if token == "" {
+		return text
+	}
+	return strings.ReplaceAll(text, "/bot"+token+"/", "/bot"+CassetteTokenReplacement+"/")
+}
+
+// newCassetteInteraction creates interaction from request, streamed body is read and replaced with raw body
+func newCassetteInteraction(url string, data *RequestData) (*CassetteInteraction, error) {
+	url = redactToken(url, cassetteToken(url))
+	interaction := &CassetteInteraction{
+		URL:         url,
+		Method:      url[strings.LastIndex(url, "/")+1:],
+		ContentType: data.ContentType,
+	}
+
+	if data.BodyRaw == nil && data.BodyStream != nil {
+		body, err := io.ReadAll(data.BodyStream)
+		if err != nil {
+			return nil, fmt.Errorf("read body: %w", err)
+		}
+		data.BodyRaw = body
+		data.BodyStream = nil
+	}
+
+	mediaType, mediaParams, err := mime.ParseMediaType(data.ContentType)
+	if err != nil || mediaType != "multipart/form-data" {
+		interaction.Body = data.BodyRaw
+		return interaction, nil
+	}
+
+	interaction.Fields = make(map[string]string)
+	interaction.Files = make(map[string]string)
+
+	reader := multipart.NewReader(bytes.NewReader(data.BodyRaw), mediaParams["boundary"])
+	for {
+		part, partErr := reader.NextPart()
+		if errors.Is(partErr, io.EOF) {
+			break
+		}
+		if partErr != nil {
+			return nil, fmt.Errorf("read multipart: %w", partErr)
+		}
+
+		if part.FileName() != "" {
+			interaction.Files[part.FormName()] = part.FileName()
+			continue
+		}
+
+		value, readErr := io.ReadAll(part)
+		if readErr != nil {
+			return nil, fmt.Errorf("read multipart field: %w", readErr)
+		}
+		interaction.Fields[part.FormName()] = string(value)
+	}
+
+	return interaction, nil
+}
+
+// RecordCaller decorator over [Caller] that records all calls and their responses to JSONL cassette, which can be
+// replayed using [ReplayCaller]
+// Note: Streamed request bodies (like multipart requests) are read into memory to be recorded, only names of files
+// are recorded
+type RecordCaller struct {
+	caller Caller
+
+	mutex   sync.Mutex
+	encoder *bufio.Writer
+}
+
+// NewRecordCaller creates new record caller that writes cassette to writer
+func NewRecordCaller(caller Caller, writer io.Writer) *RecordCaller {
+	return &RecordCaller{
+		caller:  caller,
+		encoder: bufio.NewWriter(writer),
+	}
+}
+
+// Call makes a call using provided caller and records it
+func (r *RecordCaller) Call(ctx context.Context, url string, data *RequestData) (*Response, error) {
+	interaction, err := newCassetteInteraction(url, data)
+	if err != nil {
+		return nil, fmt.Errorf("record: %w", err)
+	}
+
+	response, err := r.caller.Call(ctx, url, data)
+	interaction.Response = response
+	if err != nil {
+		interaction.Error = redactToken(err.Error(), cassetteToken(url))
+	}
+
+	line, marshalErr := json.Marshal(interaction)
+	if marshalErr != nil {
+		return nil, fmt.Errorf("record: encode interaction: %w", marshalErr)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	_, _ = r.encoder.Write(line)
+	_ = r.encoder.WriteByte('\n')
+	if flushErr := r.encoder.Flush(); flushErr != nil {
+		return nil, fmt.Errorf("record: write interaction: %w", flushErr)
+	}
+
+	return response, err
+}
+
+// ReplayMode represents how calls are matched with recorded interactions
+type ReplayMode uint
+
+const (
+	// ReplayStrict calls must be made in recorded order and match method and parameters
+	ReplayStrict ReplayMode = iota
+	// ReplayLenient calls can be made in any order, the first unused interaction with the same method and parameters
+	// is replayed, if all such interactions were used, the last one is replayed again
+	ReplayLenient
+)
+
+// ReplayCaller implementation of [Caller] that replays responses from cassette recorded by [RecordCaller] without
+// making real calls
+type ReplayCaller struct {
+	mode ReplayMode
+
+	mutex        sync.Mutex
+	interactions []*CassetteInteraction
+	used         []bool
+	next         int
+}
+
+// NewReplayCaller creates new replay caller that reads cassette from reader
+func NewReplayCaller(reader io.Reader, mode ReplayMode) (*ReplayCaller, error) {
+	var interactions []*CassetteInteraction
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(nil, 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		interaction := &CassetteInteraction{}
+		if err := json.Unmarshal(scanner.Bytes(), interaction); err != nil {
+			return nil, fmt.Errorf("replay: decode line %d: %w", line, err)
+		}
+		interactions = append(interactions, interaction)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("replay: read cassette: %w", err)
+	}
+
+	return &ReplayCaller{
+		mode:         mode,
+		interactions: interactions,
+		used:         make([]bool, len(interactions)),
+	}, nil
+}
+
+// Call returns recorded response of matching interaction
+func (r *ReplayCaller) Call(_ context.Context, url string, data *RequestData) (*Response, error) {
+	actual, err := newCassetteInteraction(url, data)
+	if err != nil {
+		return nil, fmt.Errorf("replay: %w", err)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	index, err := r.match(actual)
+	if err != nil {
+		return nil, err
+	}
+
+	r.used[index] = true
+	interaction := r.interactions[index]
+	if interaction.Error != "" {
+		return interaction.Response, errors.New(interaction.Error)
+	}
+	return interaction.Response, nil
+}
+
+// match returns index of interaction that matches actual call
+func (r *ReplayCaller) match(actual *CassetteInteraction) (int, error) {
+	actualParams := actual.params()
+
+	if r.mode == ReplayStrict {
+		if r.next >= len(r.interactions) {
+			return 0, fmt.Errorf("%w: unexpected call %s, all %d interactions were replayed",
+				ErrCassetteMismatch, actual.Method, len(r.interactions))
+		}
+
+		expected := r.interactions[r.next]
+		if expected.Method != actual.Method || !reflect.DeepEqual(expected.params(), actualParams) {
+			return 0, fmt.Errorf("%w: interaction %d:\n%s", ErrCassetteMismatch, r.next+1,
+				cassetteDiff(expected, actual))
+		}
+
+		r.next++
+		return r.next - 1, nil
+	}
+
+	lastMatch := -1
+	var closest *CassetteInteraction
+	for i, expected := range r.interactions {
+		if expected.Method != actual.Method {
+			continue
+		}
+		if closest == nil {
+			closest = expected
+		}
+
+		if !reflect.DeepEqual(expected.params(), actualParams) {
+			continue
+		}
+		if !r.used[i] {
+			return i, nil
+		}
+		lastMatch = i
+	}
+
+	if lastMatch != -1 {
+		return lastMatch, nil
+	}
+
+	if closest == nil {
+		return 0, fmt.Errorf("%w: no interactions recorded for method %s", ErrCassetteMismatch, actual.Method)
+	}
+	return 0, fmt.Errorf("%w: closest interaction:\n%s", ErrCassetteMismatch, cassetteDiff(closest, actual))
+}
+
+// Remaining returns number of recorded interactions that were not replayed
+func (r *ReplayCaller) Remaining() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	remaining := 0
+	for _, used := range r.used {
+		if !used {
+			remaining++
+		}
+	}
+	return remaining
+}
+
+// cassetteDiff returns readable difference between expected and actual interactions, lines starting with "-" are
+// expected, and with "+" are actual
+func cassetteDiff(expected, actual *CassetteInteraction) string {
+	diff := &strings.Builder{}
+
+	if expected.Method != actual.Method {
+		_, _ = fmt.Fprintf(diff, "- method: %s\n+ method: %s\n", expected.Method, actual.Method)
+	}
+
+	expectedParams, actualParams := expected.params(), actual.params()
+	keys := make([]string, 0, len(expectedParams)+len(actualParams))
+	for key := range expectedParams {
+		keys = append(keys, key)
+	}
+	for key := range actualParams {
+		if _, ok := expectedParams[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		expectedValue, expectedOk := expectedParams[key]
+		actualValue, actualOk := actualParams[key]
+		if expectedOk && actualOk && reflect.DeepEqual(expectedValue, actualValue) {
+			continue
+		}
+
+		if expectedOk {
+			_, _ = fmt.Fprintf(diff, "- %s: %s\n", key, formatCassetteValue(expectedValue))
+		}
+		if actualOk {
+			_, _ = fmt.Fprintf(diff, "+ %s: %s\n", key, formatCassetteValue(actualValue))
+		}
+	}
+
+	return strings.TrimSuffix(diff.String(), "\n")
+}
+
+// formatCassetteValue formats parameter value as JSON
+func formatCassetteValue(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
@@ -0,0 +1,174 @@
+package telegoapi
+
+import (
+	"bytes"
+	"errors"
+	neturl "net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	_ Caller = &RecordCaller{}
+	_ Caller = &ReplayCaller{}
+)
+
+const testCassetteURL = "https://api.telegram.org/bot123:secret/"
+
+var errTest = errors.New("test")
+
+func recordTestCassette(t *testing.T) string {
+	t.Helper()
+
+	inner := &testRetryCaller{resp: &Response{Ok: true, Result: []byte(`{"id":1}`)}}
+	cassette := &bytes.Buffer{}
+	caller := NewRecordCaller(inner, cassette)
+
+	resp, err := caller.Call(t.Context(), testCassetteURL+"getMe", jsonRequest(`{}`))
+	require.NoError(t, err)
+	assert.Equal(t, inner.resp, resp)
+
+	_, err = caller.Call(t.Context(), testCassetteURL+"sendMessage", jsonRequest(`{"chat_id":1,"text":"a"}`))
+	require.NoError(t, err)
+
+	data, err := DefaultConstructor{}.MultipartRequest(
+		map[string]string{"chat_id": "1", "reply_markup": `{"force_reply":true}`},
+		map[string]NamedReader{"photo": newTestFile("image.png", "image")},
+	)
+	require.NoError(t, err)
+	_, err = caller.Call(t.Context(), testCassetteURL+"sendPhoto", data)
+	require.NoError(t, err)
+	assert.Nil(t, data.BodyStream)
+	assert.Contains(t, string(data.BodyRaw), "image")
+
+	inner.resp, inner.err = nil, errTest
+	_, err = caller.Call(t.Context(), testCassetteURL+"sendMessage", jsonRequest(`{"chat_id":2,"text":"b"}`))
+	require.ErrorIs(t, err, errTest)
+
+	return cassette.String()
+}
+
+func TestRecordCaller_Call(t *testing.T) {
+	cassette := recordTestCassette(t)
+	assert.NotContains(t, cassette, "secret")
+
+	lines := strings.Split(strings.TrimSpace(cassette), "\n")
+	require.Len(t, lines, 4)
+	assert.Contains(t, lines[0], `"url":"https://api.telegram.org/bot`+CassetteTokenReplacement+`/getMe"`)
+	assert.Contains(t, lines[2], `"fields":{"chat_id":"1","reply_markup":"{\"force_reply\":true}"}`)
+	assert.Contains(t, lines[2], `"files":{"photo":"image.png"}`)
+	assert.Contains(t, lines[3], `"error":"`+errTest.Error()+`"`)
+
+	t.Run("error_url", func(t *testing.T) {
+		url := testCassetteURL + "getMe"
+		buffer := &bytes.Buffer{}
+		caller := NewRecordCaller(&testRetryCaller{err: &neturl.Error{Op: "Post", URL: url, Err: errTest}}, buffer)
+		_, err := caller.Call(t.Context(), url, jsonRequest(`{}`))
+		require.Error(t, err)
+		assert.NotContains(t, buffer.String(), "secret")
+		assert.Contains(t, buffer.String(), `"error":"Post \"https://api.telegram.org/bot`+CassetteTokenReplacement)
+	})
+
+	t.Run("api_path", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		caller := NewRecordCaller(&testRetryCaller{resp: &Response{Ok: true}}, buffer)
+		_, err := caller.Call(t.Context(), "https://example.com/botapi/bot123:secret/test/getMe", jsonRequest(`{}`))
+		require.NoError(t, err)
+		assert.Contains(t, buffer.String(),
+			`"url":"https://example.com/botapi/bot`+CassetteTokenReplacement+`/test/getMe"`)
+	})
+
+	t.Run("error_body", func(t *testing.T) {
+		caller := NewRecordCaller(&testRetryCaller{}, &bytes.Buffer{})
+		_, err := caller.Call(t.Context(), testCassetteURL+"getMe", &RequestData{BodyStream: errReader{}})
+		require.Error(t, err)
+	})
+}
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) {
+	return 0, errTest
+}
+
+func TestReplayCaller_Call(t *testing.T) {
+	cassette := recordTestCassette(t)
+
+	t.Run("strict", func(t *testing.T) {
+		caller, err := NewReplayCaller(strings.NewReader(cassette), ReplayStrict)
+		require.NoError(t, err)
+		assert.Equal(t, 4, caller.Remaining())
+
+		resp, err := caller.Call(t.Context(), "https://example.com/botother/getMe", jsonRequest(`{}`))
+		require.NoError(t, err)
+		assert.Equal(t, `{"id":1}`, string(resp.Result))
+
+		_, err = caller.Call(t.Context(), testCassetteURL+"sendPhoto", jsonRequest(`{}`))
+		require.ErrorIs(t, err, ErrCassetteMismatch)
+		assert.Contains(t, err.Error(), "- method: sendMessage\n+ method: sendPhoto")
+
+		_, err = caller.Call(t.Context(), testCassetteURL+"sendMessage", jsonRequest(`{ "text": "a", "chat_id": 1 }`))
+		require.NoError(t, err)
+
+		data, err := DefaultConstructor{}.MultipartRequest(
+			map[string]string{"chat_id": "1", "reply_markup": `{ "force_reply": true }`},
+			map[string]NamedReader{"photo": newTestFile("image.png", "other")},
+		)
+		require.NoError(t, err)
+		_, err = caller.Call(t.Context(), testCassetteURL+"sendPhoto", data)
+		require.NoError(t, err)
+
+		_, err = caller.Call(t.Context(), testCassetteURL+"sendMessage", jsonRequest(`{"chat_id":2,"text":"c"}`))
+		require.ErrorIs(t, err, ErrCassetteMismatch)
+		assert.Contains(t, err.Error(), "- text: \"b\"\n+ text: \"c\"")
+
+		_, err = caller.Call(t.Context(), testCassetteURL+"sendMessage", jsonRequest(`{"chat_id":2,"text":"b"}`))
+		require.EqualError(t, err, errTest.Error())
+		assert.Equal(t, 0, caller.Remaining())
+
+		_, err = caller.Call(t.Context(), testCassetteURL+"getMe", jsonRequest(`{}`))
+		require.ErrorIs(t, err, ErrCassetteMismatch)
+	})
+
+	t.Run("lenient", func(t *testing.T) {
+		caller, err := NewReplayCaller(strings.NewReader(cassette), ReplayLenient)
+		require.NoError(t, err)
+
+		_, err = caller.Call(t.Context(), testCassetteURL+"sendMessage", jsonRequest(`{"chat_id":2,"text":"b"}`))
+		require.Error(t, err)
+
+		resp, err := caller.Call(t.Context(), testCassetteURL+"sendMessage", jsonRequest(`{"chat_id":1,"text":"a"}`))
+		require.NoError(t, err)
+		assert.True(t, resp.Ok)
+
+		_, err = caller.Call(t.Context(), testCassetteURL+"getMe", jsonRequest(`{}`))
+		require.NoError(t, err)
+		_, err = caller.Call(t.Context(), testCassetteURL+"getMe", jsonRequest(`{}`))
+		require.NoError(t, err)
+		assert.Equal(t, 1, caller.Remaining())
+
+		_, err = caller.Call(t.Context(), testCassetteURL+"sendMessage", jsonRequest(`{"chat_id":1,"text":"c"}`))
+		require.ErrorIs(t, err, ErrCassetteMismatch)
+		assert.Contains(t, err.Error(), "- text: \"a\"\n+ text: \"c\"")
+
+		_, err = caller.Call(t.Context(), testCassetteURL+"close", jsonRequest(`{}`))
+		require.ErrorIs(t, err, ErrCassetteMismatch)
+		assert.Contains(t, err.Error(), "no interactions recorded for method close")
+	})
+
+	t.Run("error_cassette", func(t *testing.T) {
+		_, err := NewReplayCaller(strings.NewReader("{}\n\nnot json"), ReplayStrict)
+		require.ErrorContains(t, err, "line 3")
+	})
+
+	t.Run("error_body", func(t *testing.T) {
+		caller, err := NewReplayCaller(strings.NewReader(cassette), ReplayStrict)
+		require.NoError(t, err)
+
+		_, err = caller.Call(t.Context(), testCassetteURL+"getMe", &RequestData{BodyStream: errReader{}})
+		require.ErrorIs(t, err, errTest)
+	})
+}
@@ -4,10 +4,16 @@ Package telegoapi provides an API for calling Telegram for Telego.
 This API package describes the main part of communication with Telegram Bot API.
 
 The [Response] represents the API response from Telegram with respectful result and error values.
+[Error] can be matched against sentinel errors (like [ErrBotBlocked] or [ErrMessageNotModified]) using [errors.Is].
 
 [Caller] interface represents the general logic of sending requests to API and receiving responses from it.
 Currently, Telego provides valyala/fasthttp and net/http implementation, but your own can be defined and specified
 via bot options.
+[RetryCaller] and [RateLimitCaller] are decorators over any [Caller] that retry failed requests and proactively limit
+the rate of requests respectively.
+[RecordCaller] records calls to a JSONL cassette, that [ReplayCaller] can later replay without network access.
+[CallOptions] attached to the context using [WithCallOptions] override timeout, retries, priority and API server of a
+single call.
 
 [RequestConstructor] interface represents a general way of constructing [RequestData] used in [Caller].
 Currently, Telego provides only default implementation that uses goccy/go-json instead of encoding/json and std
@@ -0,0 +1,168 @@
+package telegoapi
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Errors that can be matched against [Error] using [errors.Is], matching is done by error code and description
+// returned by Telegram
+var (
+	// ErrBadRequest any error with 400 Bad Request code
+	ErrBadRequest = errors.New("bad request")
+	// ErrUnauthorized any error with 401 Unauthorized code, usually bot token is invalid
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden any error with 403 Forbidden code
+	ErrForbidden = errors.New("forbidden")
+	// ErrNotFound any error with 404 Not Found code
+	ErrNotFound = errors.New("not found")
+	// ErrConflict any error with 409 Conflict code, usually another instance of the bot is getting updates or webhook
+	// is set while getting updates using long polling
+	ErrConflict = errors.New("conflict")
+	// ErrTooManyRequests any error with 429 Too Many Requests code, see [RetryAfter]
+	ErrTooManyRequests = errors.New("too many requests")
+
+	// ErrBotBlocked bot was blocked by the user
+	ErrBotBlocked = errors.New("bot was blocked by the user")
+	// ErrBotKicked bot was kicked from the chat
+	ErrBotKicked = errors.New("bot was kicked from the chat")
+	// ErrBotNotMember bot is not a member of the chat
+	ErrBotNotMember = errors.New("bot is not a member of the chat")
+	// ErrUserDeactivated user account is deleted
+	ErrUserDeactivated = errors.New("user is deactivated")
+	// ErrCantInitiateConversation user never started conversation with the bot
+	ErrCantInitiateConversation = errors.New("bot can't initiate conversation with a user")
+	// ErrChatNotFound chat doesn't exist or bot has no access to it
+	ErrChatNotFound = errors.New("chat not found")
+	// ErrUserNotFound user doesn't exist or bot has no access to it
+	ErrUserNotFound = errors.New("user not found")
+	// ErrMigrated group was upgraded to a supergroup, see [MigrateToChatID]
+	ErrMigrated = errors.New("group migrated to supergroup")
+	// ErrNotEnoughRights bot doesn't have enough rights to perform an action in the chat
+	ErrNotEnoughRights = errors.New("not enough rights")
+	// ErrMessageNotModified message content and reply markup are exactly the same as current ones
+	ErrMessageNotModified = errors.New("message is not modified")
+	// ErrMessageToEditNotFound message to edit doesn't exist
+	ErrMessageToEditNotFound = errors.New("message to edit not found")
+	// ErrMessageToDeleteNotFound message to delete doesn't exist
+	ErrMessageToDeleteNotFound = errors.New("message to delete not found")
+	// ErrMessageToReplyNotFound message to reply doesn't exist
+	ErrMessageToReplyNotFound = errors.New("message to reply not found")
+	// ErrMessageCantBeEdited message can't be edited
+	ErrMessageCantBeEdited = errors.New("message can't be edited")
+	// ErrMessageCantBeDeleted message can't be deleted
+	ErrMessageCantBeDeleted = errors.New("message can't be deleted")
+	// ErrMessageTextEmpty message text is empty
+	ErrMessageTextEmpty = errors.New("message text is empty")
+	// ErrMessageTooLong message text is too long
+	ErrMessageTooLong = errors.New("message is too long")
+	// ErrCantParseEntities message text or caption has invalid formatting
+	ErrCantParseEntities = errors.New("can't parse entities")
+	// ErrQueryTooOld callback or inline query is too old to answer, or its ID is invalid
+	ErrQueryTooOld = errors.New("query is too old")
+	// ErrWrongFileID file ID or URL is invalid
+	ErrWrongFileID = errors.New("wrong file identifier or URL")
+)
+
+// errorRule describes how to match [Error] against sentinel error
+type errorRule struct {
+	code         int
+	descriptions []string
+	match        func(apiErr *Error) bool
+}
+
+// matches reports if API error matches the rule
+func (r errorRule) matches(apiErr *Error) bool {
+	if r.match != nil {
+		return r.match(apiErr)
+	}
+
+	if r.code != 0 && apiErr.ErrorCode != r.code {
+		return false
+	}
+	if len(r.descriptions) == 0 {
+		return true
+	}
+
+	description := strings.ToLower(apiErr.Description)
+	for _, d := range r.descriptions {
+		if strings.Contains(description, d) {
+			return true
+		}
+	}
+	return false
+}
+
+// errorRules maps sentinel errors to rules
+var errorRules = map[error]errorRule{
+	ErrBadRequest:      {code: http.StatusBadRequest},
+	ErrUnauthorized:    {code: http.StatusUnauthorized},
+	ErrForbidden:       {code: http.StatusForbidden},
+	ErrNotFound:        {code: http.StatusNotFound},
+	ErrConflict:        {code: http.StatusConflict},
+	ErrTooManyRequests: {code: http.StatusTooManyRequests},
+
+	ErrBotBlocked: {code: http.StatusForbidden, descriptions: []string{"bot was blocked by the user"}},
+	ErrBotKicked: {code: http.StatusForbidden, descriptions: []string{
+		"bot was kicked from", "bot was removed from",
+	}},
+	ErrBotNotMember:    {code: http.StatusForbidden, descriptions: []string{"bot is not a member of"}},
+	ErrUserDeactivated: {code: http.StatusForbidden, descriptions: []string{"user is deactivated"}},
+	ErrCantInitiateConversation: {code: http.StatusForbidden, descriptions: []string{
+		"bot can't initiate conversation with a user",
+	}},
+	ErrChatNotFound: {code: http.StatusBadRequest, descriptions: []string{"chat not found"}},
+	ErrUserNotFound: {code: http.StatusBadRequest, descriptions: []string{"user not found"}},
+	ErrMigrated: {match: func(apiErr *Error) bool {
+		return apiErr.Parameters != nil && apiErr.Parameters.MigrateToChatID != 0
+	}},
+	ErrNotEnoughRights: {descriptions: []string{
+		"not enough rights", "have no rights", "need administrator rights", "chat_admin_required",
+	}},
+	ErrMessageNotModified:      {code: http.StatusBadRequest, descriptions: []string{"message is not modified"}},
+	ErrMessageToEditNotFound:   {code: http.StatusBadRequest, descriptions: []string{"message to edit not found"}},
+	ErrMessageToDeleteNotFound: {code: http.StatusBadRequest, descriptions: []string{"message to delete not found"}},
+	ErrMessageToReplyNotFound: {code: http.StatusBadRequest, descriptions: []string{
+		"message to reply not found", "message to be replied not found",
+	}},
+	ErrMessageCantBeEdited:  {code: http.StatusBadRequest, descriptions: []string{"message can't be edited"}},
+	ErrMessageCantBeDeleted: {code: http.StatusBadRequest, descriptions: []string{"message can't be deleted"}},
+	ErrMessageTextEmpty:     {code: http.StatusBadRequest, descriptions: []string{"message text is empty"}},
+	ErrMessageTooLong:       {code: http.StatusBadRequest, descriptions: []string{"message is too long"}},
+	ErrCantParseEntities:    {code: http.StatusBadRequest, descriptions: []string{"can't parse entities"}},
+	ErrQueryTooOld: {code: http.StatusBadRequest, descriptions: []string{
+		"query is too old", "query id is invalid",
+	}},
+	ErrWrongFileID: {code: http.StatusBadRequest, descriptions: []string{
+		"wrong file identifier", "wrong remote file identifier", "failed to get http url content",
+	}},
+}
+
+// Is reports if API error matches one of the sentinel errors (like [ErrBotBlocked]), allows using [errors.Is]
+func (a *Error) Is(target error) bool {
+	rule, ok := errorRules[target]
+	if !ok {
+		return false
+	}
+	return rule.matches(a)
+}
+
+// RetryAfter returns time to wait before the request can be repeated if error was caused by exceeding flood control
+func RetryAfter(err error) (time.Duration, bool) {
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.ErrorCode != http.StatusTooManyRequests || apiErr.Parameters == nil {
+		return 0, false
+	}
+	return time.Duration(apiErr.Parameters.RetryAfter) * time.Second, true
+}
+
+// MigrateToChatID returns the supergroup chat ID if error was caused by group being migrated to supergroup
+func MigrateToChatID(err error) (int64, bool) {
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.Parameters == nil || apiErr.Parameters.MigrateToChatID == 0 {
+		return 0, false
+	}
+	return apiErr.Parameters.MigrateToChatID, true
+}
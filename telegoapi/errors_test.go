@@ -0,0 +1,119 @@
+package telegoapi
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestError_Is(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      *Error
+		matches  []error
+		excludes []error
+	}{
+		{
+			name: "bot_blocked",
+			err: &Error{
+				ErrorCode:   403,
+				Description: "Forbidden: bot was blocked by the user",
+			},
+			matches:  []error{ErrForbidden, ErrBotBlocked},
+			excludes: []error{ErrBadRequest, ErrChatNotFound, ErrMigrated},
+		},
+		{
+			name: "chat_not_found",
+			err: &Error{
+				ErrorCode:   400,
+				Description: "Bad Request: chat not found",
+			},
+			matches:  []error{ErrBadRequest, ErrChatNotFound},
+			excludes: []error{ErrForbidden, ErrBotBlocked, ErrUserNotFound},
+		},
+		{
+			name: "message_not_modified",
+			err: &Error{
+				ErrorCode: 400,
+				Description: "Bad Request: message is not modified: specified new message content and reply markup " +
+					"are exactly the same as a current content and reply markup of the message",
+			},
+			matches:  []error{ErrBadRequest, ErrMessageNotModified},
+			excludes: []error{ErrMessageToEditNotFound},
+		},
+		{
+			name: "not_enough_rights",
+			err: &Error{
+				ErrorCode:   400,
+				Description: "Bad Request: not enough rights to send text messages to the chat",
+			},
+			matches:  []error{ErrBadRequest, ErrNotEnoughRights},
+			excludes: []error{ErrForbidden},
+		},
+		{
+			name: "migrated",
+			err: &Error{
+				ErrorCode:   400,
+				Description: "Bad Request: group chat was upgraded to a supergroup chat",
+				Parameters:  &ResponseParameters{MigrateToChatID: -100},
+			},
+			matches:  []error{ErrBadRequest, ErrMigrated},
+			excludes: []error{ErrTooManyRequests},
+		},
+		{
+			name: "too_many_requests",
+			err: &Error{
+				ErrorCode:   429,
+				Description: "Too Many Requests: retry after 5",
+				Parameters:  &ResponseParameters{RetryAfter: 5},
+			},
+			matches:  []error{ErrTooManyRequests},
+			excludes: []error{ErrBadRequest, ErrMigrated, ErrMaxRetryAttempts},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := fmt.Errorf("wrapped: %w", tt.err)
+			for _, target := range tt.matches {
+				assert.ErrorIs(t, err, target)
+			}
+			for _, target := range tt.excludes {
+				assert.NotErrorIs(t, err, target)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	retryAfter, ok := RetryAfter(fmt.Errorf("wrapped: %w", &Error{
+		ErrorCode:  429,
+		Parameters: &ResponseParameters{RetryAfter: 5},
+	}))
+	assert.True(t, ok)
+	assert.Equal(t, time.Second*5, retryAfter)
+
+	_, ok = RetryAfter(&Error{ErrorCode: 429})
+	assert.False(t, ok)
+
+	_, ok = RetryAfter(errors.New("test"))
+	assert.False(t, ok)
+}
+
+func TestMigrateToChatID(t *testing.T) {
+	chatID, ok := MigrateToChatID(fmt.Errorf("wrapped: %w", &Error{
+		ErrorCode:  400,
+		Parameters: &ResponseParameters{MigrateToChatID: -100},
+	}))
+	assert.True(t, ok)
+	assert.Equal(t, int64(-100), chatID)
+
+	_, ok = MigrateToChatID(&Error{ErrorCode: 400, Parameters: &ResponseParameters{}})
+	assert.False(t, ok)
+
+	_, ok = MigrateToChatID(errors.New("test"))
+	assert.False(t, ok)
+}
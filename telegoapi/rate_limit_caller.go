@@ -0,0 +1,334 @@
+package telegoapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mymmrac/telego/internal/json"
+)
+
+// chatIDParameter name of chat ID parameter used to identify chat the request is related to
+const chatIDParameter = "chat_id"
+
+// rateLimitCleanupInterval is how often idle per chat limiters are removed
+const rateLimitCleanupInterval = time.Minute
+
+// RateLimit represents the maximum number of requests allowed per interval, zero value means no limit
+type RateLimit struct {
+	// Number of requests allowed per interval
+	Count int
+	// Interval in which requests are counted
+	Interval time.Duration
+}
+
+// enabled reports if rate limit should be applied
+func (l RateLimit) enabled() bool {
+	return l.Count > 0 && l.Interval > 0
+}
+
+// Telegram documented rate limits
+// More info: https://core.telegram.org/bots/faq#my-bot-is-hitting-limits-how-do-i-avoid-this
+var (
+	// DefaultGlobalRateLimit no more than 30 messages per second in total
+	DefaultGlobalRateLimit = RateLimit{Count: 30, Interval: time.Second}
+	// DefaultChatRateLimit no more than one message per second in a single chat
+	DefaultChatRateLimit = RateLimit{Count: 1, Interval: time.Second}
+	// DefaultGroupRateLimit no more than 20 messages per minute in a single group
+	DefaultGroupRateLimit = RateLimit{Count: 20, Interval: time.Minute}
+)
+
+// ErrRateLimited returned when the request can't be sent before context deadline without exceeding rate limits
+var ErrRateLimited = errors.New("rate limited")
+
+// RateLimitCaller decorator over [Caller] that proactively limits the rate of requests before sending them
+// Only requests that have `chat_id` parameter (in JSON or multipart body) are limited, each of them counts towards the
+// global limit and depending on chat either towards the chat limit (private chats) or the group limit (groups and
+// channels, chat ID is negative or username)
+// If context has a deadline and the request can't be sent before it, [ErrRateLimited] is returned without waiting,
+// else the call blocks until the request can be sent or the context is done
+// Calls with [CallPriorityLow] priority don't reserve future time slots, so they are sent after normal calls that
+// are waiting
+//
+// Note: [RetryCaller] can be used as an underlying caller to handle rate limits that still happen
+type RateLimitCaller struct {
+	// Underling caller
+	Caller Caller
+	// Limit applied to all requests
+	GlobalLimit RateLimit
+	// Limit applied to requests in one private chat
+	ChatLimit RateLimit
+	// Limit applied to requests in one group or channel
+	GroupLimit RateLimit
+
+	mutex       sync.Mutex
+	global      rateLimiter
+	chats       map[string]*rateLimiter
+	lastCleanup time.Time
+}
+
+// NewRateLimitCaller creates new rate limit caller with Telegram's default limits
+func NewRateLimitCaller(caller Caller) *RateLimitCaller {
+	return &RateLimitCaller{
+		Caller:      caller,
+		GlobalLimit: DefaultGlobalRateLimit,
+		ChatLimit:   DefaultChatRateLimit,
+		GroupLimit:  DefaultGroupRateLimit,
+	}
+}
+
+// Download downloads file using provided caller if it implements [Downloader], downloads are not rate limited
+func (r *RateLimitCaller) Download(ctx context.Context, url string, offset int64) (*DownloadResponse, error) {
+	downloader, ok := r.Caller.(Downloader)
+	if !ok {
+		return nil, ErrDownloadNotSupported
+	}
+	return downloader.Download(ctx, url, offset)
+}
+
+// Call waits until the request can be sent without exceeding rate limits and makes a call using provided caller
+func (r *RateLimitCaller) Call(ctx context.Context, url string, data *RequestData) (*Response, error) {
+	chatID, found, err := requestChatID(data)
+	if err != nil {
+		return nil, fmt.Errorf("chat ID: %w", err)
+	}
+
+	if found {
+		if err = r.wait(ctx, chatID); err != nil {
+			return nil, err
+		}
+	}
+
+	return r.Caller.Call(ctx, url, data)
+}
+
+// wait reserves time slot for request to chat and waits for it
+func (r *RateLimitCaller) wait(ctx context.Context, chatID string) error {
+	var deadline time.Time
+	if ctxDeadline, ok := ctx.Deadline(); ok {
+		deadline = ctxDeadline
+	}
+
+	lowPriority := CallOptionsFromContext(ctx).Priority == CallPriorityLow
+	for {
+		sendAt, ok := r.reserveAt(time.Now(), chatID, deadline, !lowPriority)
+		if !ok {
+			return ErrRateLimited
+		}
+
+		delay := time.Until(sendAt)
+		if delay <= 0 {
+			return nil
+		}
+
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+
+		if !lowPriority {
+			return nil
+		}
+	}
+}
+
+// sleep waits for delay or until context is done
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// reserveAt finds the earliest time when request to chat can be sent and reserves it, if deadline is not zero and
+// request can't be sent before it, nothing is reserved, if future is false, only time slot that is available now is
+// reserved, the earliest time is still returned
+func (r *RateLimitCaller) reserveAt(now time.Time, chatID string, deadline time.Time, future bool) (time.Time, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.chats == nil {
+		r.chats = make(map[string]*rateLimiter)
+	}
+	r.cleanup(now)
+
+	chatLimit := r.ChatLimit
+	if isGroupChatID(chatID) {
+		chatLimit = r.GroupLimit
+	}
+
+	chat, ok := r.chats[chatID]
+	if !ok {
+		chat = &rateLimiter{}
+	}
+
+	sendAt := now
+	if r.GlobalLimit.enabled() {
+		sendAt = later(sendAt, r.global.conformsAt(r.GlobalLimit))
+	}
+	if chatLimit.enabled() {
+		sendAt = later(sendAt, chat.conformsAt(chatLimit))
+	}
+
+	if !deadline.IsZero() && sendAt.After(deadline) {
+		return time.Time{}, false
+	}
+	if !future && sendAt.After(now) {
+		return sendAt, true
+	}
+
+	if r.GlobalLimit.enabled() {
+		r.global.reserve(r.GlobalLimit, sendAt)
+	}
+	if chatLimit.enabled() {
+		chat.reserve(chatLimit, sendAt)
+		r.chats[chatID] = chat
+	}
+
+	return sendAt, true
+}
+
+// cleanup removes limiters that no longer affect any request
+func (r *RateLimitCaller) cleanup(now time.Time) {
+	if now.Sub(r.lastCleanup) < rateLimitCleanupInterval {
+		return
+	}
+	r.lastCleanup = now
+
+	for chatID, chat := range r.chats {
+		if chat.tat.Before(now) {
+			delete(r.chats, chatID)
+		}
+	}
+}
+
+// rateLimiter implements generic cell rate algorithm, it stores theoretical arrival time of the next request
+type rateLimiter struct {
+	tat time.Time
+}
+
+// conformsAt returns the earliest time when the request conforms to the limit
+func (l *rateLimiter) conformsAt(limit RateLimit) time.Time {
+	emission := limit.Interval / time.Duration(limit.Count)
+	return l.tat.Add(-(limit.Interval - emission))
+}
+
+// reserve records request sent at provided time
+func (l *rateLimiter) reserve(limit RateLimit, sendAt time.Time) {
+	emission := limit.Interval / time.Duration(limit.Count)
+	l.tat = later(l.tat, sendAt).Add(emission)
+}
+
+// later returns the latest of two times
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// isGroupChatID reports if chat ID belongs to group or channel (negative IDs and usernames)
+func isGroupChatID(chatID string) bool {
+	return strings.HasPrefix(chatID, "-") || strings.HasPrefix(chatID, "@")
+}
+
+// requestChatID returns chat ID from request data, multipart body stream will be replaced with equivalent one
+func requestChatID(data *RequestData) (string, bool, error) {
+	mediaType, params, err := mime.ParseMediaType(data.ContentType)
+	if err != nil {
+		return "", false, nil //nolint:nilerr // Unknown content type means that no chat ID can be found
+	}
+
+	switch mediaType {
+	case ContentTypeJSON:
+		if data.BodyRaw == nil {
+			return "", false, nil
+		}
+		return jsonChatID(data.BodyRaw)
+	case "multipart/form-data":
+		boundary := params["boundary"]
+		if boundary == "" {
+			return "", false, nil
+		}
+
+		if data.BodyRaw != nil {
+			return multipartChatID(bytes.NewReader(data.BodyRaw), boundary)
+		}
+		if data.BodyStream == nil {
+			return "", false, nil
+		}
+
+		consumed := &bytes.Buffer{}
+		chatID, found, err := multipartChatID(io.TeeReader(data.BodyStream, consumed), boundary)
+		data.BodyStream = io.MultiReader(consumed, data.BodyStream)
+		return chatID, found, err
+	default:
+		return "", false, nil
+	}
+}
+
+// jsonChatID returns chat ID from JSON body
+func jsonChatID(body []byte) (string, bool, error) {
+	parser := json.ParserPoll.Get()
+	defer json.ParserPoll.Put(parser)
+
+	value, err := parser.ParseBytes(body)
+	if err != nil {
+		return "", false, fmt.Errorf("parse json: %w", err)
+	}
+
+	chatID := value.Get(chatIDParameter)
+	if chatID == nil {
+		return "", false, nil
+	}
+
+	if chatIDStr, err := chatID.StringBytes(); err == nil {
+		return string(chatIDStr), true, nil
+	}
+
+	chatIDInt, err := chatID.Int64()
+	if err != nil {
+		return "", false, fmt.Errorf("parse json: %w", err)
+	}
+	return strconv.FormatInt(chatIDInt, 10), true, nil
+}
+
+// multipartChatID returns chat ID from multipart body, stops reading at first file, so file contents are not buffered
+// ([DefaultConstructor] writes all parameters before files)
+func multipartChatID(body io.Reader, boundary string) (string, bool, error) {
+	reader := multipart.NewReader(body, boundary)
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return "", false, nil
+		}
+		if err != nil {
+			return "", false, fmt.Errorf("parse multipart: %w", err)
+		}
+
+		if part.FileName() != "" {
+			return "", false, nil
+		}
+		if part.FormName() != chatIDParameter {
+			continue
+		}
+
+		chatID, err := io.ReadAll(part)
+		if err != nil {
+			return "", false, fmt.Errorf("parse multipart: %w", err)
+		}
+		return string(chatID), true, nil
+	}
+}
@@ -0,0 +1,290 @@
+package telegoapi
+
+import (
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ Caller = &RateLimitCaller{}
+
+type testBodyCaller struct {
+	mutex  sync.Mutex
+	calls  int
+	bodies []string
+}
+
+func (t *testBodyCaller) Call(_ context.Context, _ string, data *RequestData) (*Response, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.calls++
+	if data.BodyStream != nil {
+		body, err := io.ReadAll(data.BodyStream)
+		if err != nil {
+			return nil, err
+		}
+		t.bodies = append(t.bodies, string(body))
+	} else {
+		t.bodies = append(t.bodies, string(data.BodyRaw))
+	}
+	return &Response{Ok: true}, nil
+}
+
+func jsonRequest(body string) *RequestData {
+	return &RequestData{
+		ContentType: ContentTypeJSON,
+		BodyRaw:     []byte(body),
+	}
+}
+
+func TestRateLimitCaller_Call(t *testing.T) {
+	ctx := t.Context()
+
+	t.Run("success_no_chat_id", func(t *testing.T) {
+		caller := &testBodyCaller{}
+		rateLimitCaller := &RateLimitCaller{
+			Caller:      caller,
+			GlobalLimit: RateLimit{Count: 1, Interval: time.Hour},
+		}
+
+		for range 3 {
+			resp, err := rateLimitCaller.Call(ctx, "", jsonRequest(`{"offset":1}`))
+			require.NoError(t, err)
+			assert.True(t, resp.Ok)
+		}
+		assert.Equal(t, 3, caller.calls)
+	})
+
+	t.Run("success_burst", func(t *testing.T) {
+		caller := &testBodyCaller{}
+		rateLimitCaller := NewRateLimitCaller(caller)
+
+		for i := range 3 {
+			_, err := rateLimitCaller.Call(ctx, "", jsonRequest(`{"chat_id":`+strconv.Itoa(i)+`}`))
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 3, caller.calls)
+	})
+
+	t.Run("error_rate_limited_deadline", func(t *testing.T) {
+		caller := &testBodyCaller{}
+		rateLimitCaller := NewRateLimitCaller(caller)
+
+		deadlineCtx, cancel := context.WithTimeout(ctx, time.Millisecond*100)
+		defer cancel()
+
+		_, err := rateLimitCaller.Call(deadlineCtx, "", jsonRequest(`{"chat_id":1}`))
+		require.NoError(t, err)
+
+		resp, err := rateLimitCaller.Call(deadlineCtx, "", jsonRequest(`{"chat_id":1}`))
+		require.ErrorIs(t, err, ErrRateLimited)
+		assert.Nil(t, resp)
+		assert.Equal(t, 1, caller.calls)
+	})
+
+	t.Run("error_canceled", func(t *testing.T) {
+		caller := &testBodyCaller{}
+		rateLimitCaller := NewRateLimitCaller(caller)
+
+		cancelCtx, cancel := context.WithCancel(ctx)
+
+		_, err := rateLimitCaller.Call(cancelCtx, "", jsonRequest(`{"chat_id":"1"}`))
+		require.NoError(t, err)
+
+		cancel()
+		resp, err := rateLimitCaller.Call(cancelCtx, "", jsonRequest(`{"chat_id":"1"}`))
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, resp)
+	})
+
+	t.Run("success_multipart", func(t *testing.T) {
+		caller := &testBodyCaller{}
+		rateLimitCaller := NewRateLimitCaller(caller)
+		rateLimitCaller.GroupLimit = RateLimit{Count: 1, Interval: time.Minute}
+
+		data, err := DefaultConstructor{}.MultipartRequest(
+			map[string]string{"chat_id": "-100"},
+			map[string]NamedReader{"document": newTestFile("doc", "Hello World")},
+		)
+		require.NoError(t, err)
+
+		_, err = rateLimitCaller.Call(ctx, "", data)
+		require.NoError(t, err)
+		require.Len(t, caller.bodies, 1)
+		assert.Contains(t, caller.bodies[0], "Hello World")
+
+		deadlineCtx, cancel := context.WithTimeout(ctx, time.Second*2)
+		defer cancel()
+
+		_, err = rateLimitCaller.Call(deadlineCtx, "", jsonRequest(`{"chat_id":-100}`))
+		require.ErrorIs(t, err, ErrRateLimited)
+	})
+
+	t.Run("success_low_priority", func(t *testing.T) {
+		caller := &testBodyCaller{}
+		rateLimitCaller := NewRateLimitCaller(caller)
+		rateLimitCaller.ChatLimit = RateLimit{Count: 1, Interval: time.Millisecond * 100}
+
+		_, err := rateLimitCaller.Call(ctx, "", jsonRequest(`{"chat_id":1,"text":"first"}`))
+		require.NoError(t, err)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			lowCtx := WithCallOptions(ctx, Priority(CallPriorityLow))
+			_, lowErr := rateLimitCaller.Call(lowCtx, "", jsonRequest(`{"chat_id":1,"text":"low"}`))
+			assert.NoError(t, lowErr)
+		}()
+
+		time.Sleep(time.Millisecond * 10)
+		_, err = rateLimitCaller.Call(ctx, "", jsonRequest(`{"chat_id":1,"text":"normal"}`))
+		require.NoError(t, err)
+		<-done
+
+		caller.mutex.Lock()
+		defer caller.mutex.Unlock()
+
+		assert.Equal(t, []string{
+			`{"chat_id":1,"text":"first"}`,
+			`{"chat_id":1,"text":"normal"}`,
+			`{"chat_id":1,"text":"low"}`,
+		}, caller.bodies)
+	})
+
+	t.Run("error_json", func(t *testing.T) {
+		rateLimitCaller := NewRateLimitCaller(&testBodyCaller{})
+
+		resp, err := rateLimitCaller.Call(ctx, "", jsonRequest(`{"chat_id":true}`))
+		require.Error(t, err)
+		assert.Nil(t, resp)
+	})
+}
+
+func TestRateLimitCaller_reserveAt_future(t *testing.T) {
+	rateLimitCaller := &RateLimitCaller{
+		GlobalLimit: RateLimit{Count: 2, Interval: time.Second},
+		ChatLimit:   RateLimit{Count: 1, Interval: time.Second},
+		GroupLimit:  RateLimit{Count: 2, Interval: time.Minute},
+	}
+	now := time.Now()
+
+	sendAt, ok := rateLimitCaller.reserveAt(now, "1", time.Time{}, true)
+	require.True(t, ok)
+	assert.Equal(t, now, sendAt)
+
+	sendAt, ok = rateLimitCaller.reserveAt(now, "2", time.Time{}, true)
+	require.True(t, ok)
+	assert.Equal(t, now, sendAt)
+
+	sendAt, ok = rateLimitCaller.reserveAt(now, "3", time.Time{}, true)
+	require.True(t, ok)
+	assert.Equal(t, now.Add(time.Millisecond*500), sendAt)
+
+	sendAt, ok = rateLimitCaller.reserveAt(now, "1", time.Time{}, true)
+	require.True(t, ok)
+	assert.Equal(t, now.Add(time.Second), sendAt)
+
+	_, ok = rateLimitCaller.reserveAt(now, "-1", now, true)
+	require.False(t, ok)
+
+	sendAt, ok = rateLimitCaller.reserveAt(now.Add(time.Hour), "-1", time.Time{}, true)
+	require.True(t, ok)
+	assert.Equal(t, now.Add(time.Hour), sendAt)
+
+	sendAt, ok = rateLimitCaller.reserveAt(now.Add(time.Hour), "-1", time.Time{}, true)
+	require.True(t, ok)
+	assert.Equal(t, now.Add(time.Hour), sendAt)
+
+	sendAt, ok = rateLimitCaller.reserveAt(now.Add(time.Hour), "-1", time.Time{}, true)
+	require.True(t, ok)
+	assert.Equal(t, now.Add(time.Hour+time.Second*30), sendAt)
+	assert.Len(t, rateLimitCaller.chats, 1)
+}
+
+func TestRateLimitCaller_reserveAt(t *testing.T) {
+	rateLimitCaller := &RateLimitCaller{
+		ChatLimit: RateLimit{Count: 1, Interval: time.Second},
+	}
+	now := time.Now()
+
+	sendAt, ok := rateLimitCaller.reserveAt(now, "1", time.Time{}, false)
+	require.True(t, ok)
+	assert.Equal(t, now, sendAt)
+
+	sendAt, ok = rateLimitCaller.reserveAt(now, "1", time.Time{}, false)
+	require.True(t, ok)
+	assert.Equal(t, now.Add(time.Second), sendAt)
+
+	sendAt, ok = rateLimitCaller.reserveAt(now, "1", time.Time{}, true)
+	require.True(t, ok)
+	assert.Equal(t, now.Add(time.Second), sendAt)
+}
+
+func Test_multipartChatID(t *testing.T) {
+	body := &strings.Builder{}
+	writer := multipart.NewWriter(body)
+	require.NoError(t, writer.WriteField("text", "test"))
+	require.NoError(t, writer.Close())
+
+	chatID, found, err := multipartChatID(strings.NewReader(body.String()), writer.Boundary())
+	require.NoError(t, err)
+	assert.False(t, found)
+	assert.Empty(t, chatID)
+
+	t.Run("stream_file", func(t *testing.T) {
+		body = &strings.Builder{}
+		writer = multipart.NewWriter(body)
+		file, err := writer.CreateFormFile("sticker", "sticker.png")
+		require.NoError(t, err)
+		_, err = file.Write([]byte(strings.Repeat("a", 1<<16)))
+		require.NoError(t, err)
+		require.NoError(t, writer.WriteField("user_id", "1"))
+		require.NoError(t, writer.Close())
+
+		stream := &countingReader{reader: strings.NewReader(body.String())}
+		data := &RequestData{ContentType: writer.FormDataContentType(), BodyStream: stream}
+
+		chatID, found, err = requestChatID(data)
+		require.NoError(t, err)
+		assert.False(t, found)
+		assert.Empty(t, chatID)
+		assert.Less(t, stream.read, 1<<13)
+
+		streamed, err := io.ReadAll(data.BodyStream)
+		require.NoError(t, err)
+		assert.Equal(t, body.String(), string(streamed))
+	})
+}
+
+type countingReader struct {
+	reader io.Reader
+	read   int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.read += n
+	return n, err
+}
+
+func TestRateLimitCaller_Download(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(downloadHandler))
+	defer srv.Close()
+
+	testDownload(t, NewRateLimitCaller(HTTPCaller{Client: srv.Client()}), srv.URL)
+
+	resp, err := NewRateLimitCaller(&testBodyCaller{}).Download(t.Context(), srv.URL, 0)
+	require.ErrorIs(t, err, ErrDownloadNotSupported)
+	assert.Nil(t, resp)
+}
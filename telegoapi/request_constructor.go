@@ -27,7 +27,8 @@ func (d DefaultConstructor) JSONRequest(parameters any) (*RequestData, error) {
 	}, nil
 }
 
-// MultipartRequest is default implementation
+// MultipartRequest is default implementation, body is streamed through a pipe, so files are read only as the request
+// is being sent and never fully loaded into memory
 func (d DefaultConstructor) MultipartRequest(
 	parameters map[string]string, filesParameters map[string]NamedReader,
 ) (*RequestData, error) {
@@ -4,7 +4,9 @@ import (
 	"errors"
 	"io"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -151,6 +153,41 @@ func (t testErrFile) Read(_ []byte) (n int, err error) {
 	return 0, t.err
 }
 
+type testCountingReader struct {
+	read      atomic.Int64
+	remaining int64
+}
+
+func (r *testCountingReader) Read(p []byte) (int, error) {
+	if r.remaining == 0 {
+		return 0, io.EOF
+	}
+	n := min(int64(len(p)), r.remaining)
+	r.remaining -= n
+	r.read.Add(n)
+	return int(n), nil
+}
+
+func (r *testCountingReader) Name() string {
+	return "large"
+}
+
+func TestDefaultConstructor_MultipartRequest_streaming(t *testing.T) {
+	const size = 16 * 1024 * 1024
+	file := &testCountingReader{remaining: size}
+
+	data, err := DefaultConstructor{}.MultipartRequest(nil, map[string]NamedReader{"file": file})
+	require.NoError(t, err)
+
+	time.Sleep(time.Millisecond * 10)
+	assert.LessOrEqual(t, file.read.Load(), int64(64*1024))
+
+	n, err := io.Copy(io.Discard, data.BodyStream)
+	require.NoError(t, err)
+	assert.Greater(t, n, int64(size))
+	assert.Equal(t, int64(size), file.read.Load())
+}
+
 func Test_isNil(t *testing.T) {
 	var nr NamedReader
 	var ns any
@@ -4,9 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/mymmrac/telego"
+	tm "github.com/mymmrac/telego/telegometrics"
 )
 
 // Handler handles update that came from bot
@@ -19,12 +25,45 @@ type Predicate func(ctx context.Context, update telego.Update) bool
 // ErrorHandler handles error that came from bot handing update
 type ErrorHandler func(ctx *Context, update telego.Update, err error)
 
+// RouteHook is called when handler matched the update
+type RouteHook func(ctx *Context, update telego.Update, route Route)
+
+// Route represents matched handler
+type Route struct {
+	// Path of indexes of groups and the handler in order of registration (middlewares are counted as well),
+	// starting from the base group
+	Path []int
+	// Handler that matched the update
+	Handler Handler
+}
+
+// String returns route path and name of handler function with its location
+func (r Route) String() string {
+	path := make([]string, len(r.Path))
+	for i, index := range r.Path {
+		path[i] = strconv.Itoa(index)
+	}
+
+	if r.Handler == nil {
+		return strings.Join(path, ".")
+	}
+
+	fn := runtime.FuncForPC(reflect.ValueOf(r.Handler).Pointer())
+	if fn == nil {
+		return strings.Join(path, ".")
+	}
+
+	file, line := fn.FileLine(fn.Entry())
+	return fmt.Sprintf("%s (%s at %s:%d)", strings.Join(path, "."), fn.Name(), file, line)
+}
+
 // BotHandler represents a bot handler that can handle updated matching by predicates
 type BotHandler struct {
 	bot          *telego.Bot
 	updates      <-chan telego.Update
 	baseGroup    *HandlerGroup
 	errorHandler ErrorHandler
+	routeHook    RouteHook
 
 	running  bool
 	lock     sync.RWMutex
@@ -101,29 +140,63 @@ func (h *BotHandler) Start() error {
 					}
 				}()
 
-				bCtx := &Context{
-					ctx: ctx,
-					ctxBase: &ctxBase{
-						bot:        h.bot,
-						updateID:   update.UpdateID,
-						group:      h.baseGroup,
-						finalGroup: nil, // Not set
-						stack:      append(make([]int, 0, depth), -1),
-					},
-				}
-
-				if err := bCtx.Next(update); err != nil {
-					if h.errorHandler != nil {
-						h.errorHandler(bCtx, update, err)
-					} else {
-						h.bot.Logger().Errorf("Error processing update %d, err: %s", update.UpdateID, err)
-					}
-				}
+				_ = h.handleUpdate(ctx, update, depth)
 			})
 		}
 	}
 }
 
+// HandleUpdate handles a single update synchronously, returns once all handlers and middlewares complete. Error
+// returned by handlers is passed to error handler (or logged) and returned.
+// Note: It can be used without starting bot handler, for example, in tests or with custom update sources
+func (h *BotHandler) HandleUpdate(ctx context.Context, update telego.Update) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	return h.handleUpdate(ctx, update, h.baseGroup.depth(1))
+}
+
+// handleUpdate processes update by matching route and reports it to instrumentation
+func (h *BotHandler) handleUpdate(ctx context.Context, update telego.Update, depth int) error {
+	bCtx := &Context{
+		ctx: ctx,
+		ctxBase: &ctxBase{
+			bot:        h.bot,
+			updateID:   update.UpdateID,
+			group:      h.baseGroup,
+			finalGroup: nil, // Not set
+			stack:      append(make([]int, 0, depth), -1),
+			routeHook:  h.routeHook,
+		},
+	}
+
+	instrumentation := h.bot.Instrumentation()
+	var span tm.Span
+	bCtx.ctx, span = instrumentation.StartSpan(ctx, tm.SpanHandleUpdate,
+		tm.L(tm.AttributeUpdateID, strconv.Itoa(update.UpdateID)))
+	defer span.End()
+
+	start := time.Now()
+	status := tm.StatusOK
+
+	err := bCtx.Next(update)
+	if err != nil {
+		status = tm.StatusError
+		span.RecordError(err)
+
+		if h.errorHandler != nil {
+			h.errorHandler(bCtx, update, err)
+		} else {
+			h.bot.Logger().Errorf("Error processing update %d, err: %s", update.UpdateID, err)
+		}
+	}
+
+	instrumentation.ObserveHistogram(tm.MetricHandlerDuration, time.Since(start).Seconds(),
+		tm.L(tm.LabelStatus, status))
+
+	return err
+}
+
 // IsRunning tells if Start is running
 func (h *BotHandler) IsRunning() bool {
 	h.lock.RLock()
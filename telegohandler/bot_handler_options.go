@@ -10,3 +10,12 @@ func WithErrorHandler(handler ErrorHandler) BotHandlerOption {
 		return nil
 	}
 }
+
+// WithRouteHook sets hook that is called every time a handler (not middleware) matched the update, just before the
+// handler is called, hook can be nil (this is the default)
+func WithRouteHook(hook RouteHook) BotHandlerOption {
+	return func(bh *BotHandler) error {
+		bh.routeHook = hook
+		return nil
+	}
+}
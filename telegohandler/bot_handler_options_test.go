@@ -15,3 +15,12 @@ func TestWithErrorHandler(t *testing.T) {
 	err := WithErrorHandler(handler)(bh)
 	require.NoError(t, err)
 }
+
+func TestWithRouteHook(t *testing.T) {
+	bh := &BotHandler{}
+	hook := func(ctx *Context, update telego.Update, route Route) {}
+
+	err := WithRouteHook(hook)(bh)
+	require.NoError(t, err)
+	require.NotNil(t, bh.routeHook)
+}
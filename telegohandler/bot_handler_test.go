@@ -3,6 +3,7 @@ package telegohandler
 import (
 	"context"
 	"errors"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -12,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/mymmrac/telego"
+	tm "github.com/mymmrac/telego/telegometrics"
 )
 
 const (
@@ -189,6 +191,58 @@ func TestBotHandler_HandleError(t *testing.T) {
 	})
 }
 
+func TestBotHandler_HandleUpdate(t *testing.T) {
+	bot, err := telego.NewBot(token)
+	require.NoError(t, err)
+
+	var routes []Route
+	var receivedError error
+	bh, err := NewBotHandler(bot, nil,
+		WithRouteHook(func(_ *Context, _ telego.Update, route Route) {
+			routes = append(routes, route)
+		}),
+		WithErrorHandler(func(_ *Context, _ telego.Update, err error) {
+			receivedError = err
+		}),
+	)
+	require.NoError(t, err)
+
+	bh.Use(func(ctx *Context, update telego.Update) error {
+		return ctx.Next(update)
+	})
+	bh.Handle(func(_ *Context, _ telego.Update) error {
+		return nil
+	}, func(_ context.Context, update telego.Update) bool {
+		return update.UpdateID == 1
+	})
+
+	group := bh.Group()
+	group.Handle(func(_ *Context, _ telego.Update) error {
+		return errTest
+	})
+
+	err = bh.HandleUpdate(t.Context(), telego.Update{UpdateID: 1})
+	require.NoError(t, err)
+
+	err = bh.HandleUpdate(t.Context(), telego.Update{UpdateID: 2})
+	require.ErrorIs(t, err, errTest)
+	assert.Equal(t, errTest, receivedError)
+
+	require.Len(t, routes, 2)
+	assert.Equal(t, []int{1}, routes[0].Path)
+	assert.Equal(t, []int{2, 0}, routes[1].Path)
+	assert.False(t, bh.IsRunning())
+}
+
+func TestRoute_String(t *testing.T) {
+	assert.Equal(t, "1.2", Route{Path: []int{1, 2}}.String())
+
+	route := Route{Path: []int{0}, Handler: func(_ *Context, _ telego.Update) error { return nil }}
+	assert.Regexp(t,
+		`^0 \(github.com/mymmrac/telego/telegohandler.TestRoute_String.func1 at .+bot_handler_test.go:\d+\)$`,
+		route.String())
+}
+
 func TestBotHandler_Stop(t *testing.T) {
 	t.Run("basic", func(t *testing.T) {
 		bh := newTestBotHandler(t)
@@ -513,3 +567,40 @@ func TestBotHandler_BaseGroup(t *testing.T) {
 
 	assert.Equal(t, bh.baseGroup, bh.BaseGroup())
 }
+
+func TestBotHandler_Start_instrumentation(t *testing.T) {
+	exporter := tm.NewPrometheusExporter()
+	bot, err := telego.NewBot(token, telego.WithInstrumentation(exporter), telego.WithDiscardLogger())
+	require.NoError(t, err)
+
+	updates := make(chan telego.Update)
+	bh, err := NewBotHandler(bot, updates)
+	require.NoError(t, err)
+
+	bh.Handle(func(_ *Context, update telego.Update) error {
+		if update.UpdateID == 1 {
+			return errTest
+		}
+		return nil
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		errStart := bh.Start()
+		assert.NoError(t, errStart)
+	}()
+
+	updates <- telego.Update{}
+	updates <- telego.Update{UpdateID: 1}
+	close(updates)
+
+	<-done
+	require.NoError(t, bh.Stop())
+
+	text := &strings.Builder{}
+	_, err = exporter.WriteTo(text)
+	require.NoError(t, err)
+	assert.Contains(t, text.String(), `telego_handler_duration_seconds_count{status="ok"} 1`)
+	assert.Contains(t, text.String(), `telego_handler_duration_seconds_count{status="error"} 1`)
+}
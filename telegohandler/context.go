@@ -2,6 +2,7 @@ package telegohandler
 
 import (
 	"context"
+	"slices"
 	"time"
 
 	"github.com/mymmrac/telego"
@@ -22,6 +23,8 @@ type ctxBase struct {
 	group      *HandlerGroup
 	finalGroup *HandlerGroup
 	stack      []int
+
+	routeHook RouteHook
 }
 
 // Deadline implements [context.Context.Deadline]
@@ -118,6 +121,12 @@ func (c *Context) Next(update telego.Update) error {
 
 			// Go into handler or middleware
 			if r.handler != nil {
+				if !r.middleware && c.routeHook != nil {
+					c.routeHook(c, update, Route{
+						Path:    slices.Clone(c.stack),
+						Handler: r.handler,
+					})
+				}
 				return r.handler(c, update)
 			}
 
@@ -11,8 +11,9 @@ import (
 type route struct {
 	predicates []Predicate
 
-	group   *HandlerGroup
-	handler Handler
+	group      *HandlerGroup
+	handler    Handler
+	middleware bool
 }
 
 // match matches the current update by predicates
@@ -135,7 +136,8 @@ func (h *HandlerGroup) Use(middlewares ...Handler) {
 	h.routes = slices.Grow(h.routes, len(middlewares))
 	for _, middleware := range middlewares {
 		h.routes = append(h.routes, route{
-			handler: middleware,
+			handler:    middleware,
+			middleware: true,
 		})
 	}
 }
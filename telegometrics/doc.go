@@ -0,0 +1,14 @@
+/*
+Package telegometrics provides metrics and tracing instrumentation for Telego.
+
+[Instrumentation] interface represents a general way of reporting counters, histograms and spans. Bot, retry caller,
+long polling, webhook and bot handler report API calls, retries, received updates and update handling to
+instrumentation specified via options.
+
+Telego provides [PrometheusExporter] that collects metrics in memory and serves them in Prometheus text format, and
+span interface compatible with OpenTelemetry semantic, so any tracer can be adapted by implementing [Instrumentation].
+Multiple instrumentations can be combined using [Multi].
+
+Dev Note: This package is designed to be self-contained, and it should not depend on other packages of Telego.
+*/
+package telegometrics
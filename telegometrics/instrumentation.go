@@ -0,0 +1,177 @@
+package telegometrics
+
+import (
+	"context"
+)
+
+// Metric names reported by Telego
+const (
+	// MetricAPIRequests counter of API method calls, labels: [LabelMethod], [LabelStatus]
+	MetricAPIRequests = "telego_api_requests_total"
+	// MetricAPIRequestDuration histogram of API method calls duration in seconds, labels: [LabelMethod]
+	MetricAPIRequestDuration = "telego_api_request_duration_seconds"
+	// MetricAPIErrors counter of failed API method calls, labels: [LabelMethod], [LabelErrorCode]
+	MetricAPIErrors = "telego_api_errors_total"
+	// MetricAPIRetries counter of API requests retries, labels: [LabelMethod]
+	MetricAPIRetries = "telego_api_retries_total"
+	// MetricUpdates counter of received updates, labels: [LabelSource]
+	MetricUpdates = "telego_updates_total"
+	// MetricHandlerDuration histogram of update handling duration in seconds, labels: [LabelStatus]
+	MetricHandlerDuration = "telego_handler_duration_seconds"
+)
+
+// Label keys used in metrics reported by Telego
+const (
+	// LabelMethod name of the API method
+	LabelMethod = "method"
+	// LabelStatus result of the operation, one of [StatusOK] or [StatusError]
+	LabelStatus = "status"
+	// LabelErrorCode error code returned by Telegram or [ErrorCodeInternal]
+	LabelErrorCode = "error_code"
+	// LabelSource source of updates, one of [SourceLongPolling] or [SourceWebhook]
+	LabelSource = "source"
+)
+
+// Label values used in metrics reported by Telego
+const (
+	StatusOK          = "ok"
+	StatusError       = "error"
+	ErrorCodeInternal = "internal"
+	SourceLongPolling = "long_polling"
+	SourceWebhook     = "webhook"
+)
+
+// Span names and attributes reported by Telego, attributes follow OpenTelemetry RPC semantic conventions
+const (
+	// SpanAPIPrefix prefix of API method call span name, full name is prefix followed by method name
+	SpanAPIPrefix = "telegram/"
+	// SpanHandleUpdate name of update handling span
+	SpanHandleUpdate = "telego.handle_update"
+
+	// AttributeRPCSystem RPC system, always [RPCSystemTelegram]
+	AttributeRPCSystem = "rpc.system"
+	// AttributeRPCMethod name of the API method
+	AttributeRPCMethod = "rpc.method"
+	// AttributeUpdateID ID of handled update
+	AttributeUpdateID = "telegram.update_id"
+
+	// RPCSystemTelegram value of [AttributeRPCSystem]
+	RPCSystemTelegram = "telegram"
+)
+
+// Label represents key value pair used as a metric label or a span attribute
+type Label struct {
+	Key   string
+	Value string
+}
+
+// L creates new label
+func L(key, value string) Label {
+	return Label{
+		Key:   key,
+		Value: value,
+	}
+}
+
+// Instrumentation represents a way to report metrics and traces, implementations must be safe for concurrent use
+type Instrumentation interface {
+	// AddCounter adds non-negative value to the counter
+	AddCounter(name string, value float64, labels ...Label)
+	// ObserveHistogram records value in the histogram
+	ObserveHistogram(name string, value float64, labels ...Label)
+	// StartSpan starts new span as a child of span in context (if any), returned span must be ended
+	StartSpan(ctx context.Context, name string, attributes ...Label) (context.Context, Span)
+}
+
+// Span represents a single traced operation, compatible with OpenTelemetry span semantic
+type Span interface {
+	// SetAttributes sets attributes of the span
+	SetAttributes(attributes ...Label)
+	// RecordError records error and marks span as failed
+	RecordError(err error)
+	// End ends the span
+	End()
+}
+
+// Nop is an instrumentation that does nothing
+type Nop struct{}
+
+// AddCounter does nothing
+func (Nop) AddCounter(_ string, _ float64, _ ...Label) {}
+
+// ObserveHistogram does nothing
+func (Nop) ObserveHistogram(_ string, _ float64, _ ...Label) {}
+
+// StartSpan returns the same context and span that does nothing
+func (Nop) StartSpan(ctx context.Context, _ string, _ ...Label) (context.Context, Span) {
+	return ctx, NopSpan{}
+}
+
+// NopSpan is a span that does nothing
+type NopSpan struct{}
+
+// SetAttributes does nothing
+func (NopSpan) SetAttributes(_ ...Label) {}
+
+// RecordError does nothing
+func (NopSpan) RecordError(_ error) {}
+
+// End does nothing
+func (NopSpan) End() {}
+
+// multi represents multiple instrumentations
+type multi []Instrumentation
+
+// Multi combines multiple instrumentations into one (for example, metrics exporter and tracer)
+func Multi(instrumentations ...Instrumentation) Instrumentation {
+	return multi(instrumentations)
+}
+
+// AddCounter adds value to counter of all instrumentations
+func (m multi) AddCounter(name string, value float64, labels ...Label) {
+	for _, instrumentation := range m {
+		instrumentation.AddCounter(name, value, labels...)
+	}
+}
+
+// ObserveHistogram records value in histogram of all instrumentations
+func (m multi) ObserveHistogram(name string, value float64, labels ...Label) {
+	for _, instrumentation := range m {
+		instrumentation.ObserveHistogram(name, value, labels...)
+	}
+}
+
+// StartSpan starts span in all instrumentations, context is passed from one instrumentation to the next one
+func (m multi) StartSpan(ctx context.Context, name string, attributes ...Label) (context.Context, Span) {
+	spans := make(multiSpan, 0, len(m))
+	for _, instrumentation := range m {
+		var span Span
+		ctx, span = instrumentation.StartSpan(ctx, name, attributes...)
+		spans = append(spans, span)
+	}
+	return ctx, spans
+}
+
+// multiSpan represents multiple spans
+type multiSpan []Span
+
+// SetAttributes sets attributes of all spans
+func (m multiSpan) SetAttributes(attributes ...Label) {
+	for _, span := range m {
+		span.SetAttributes(attributes...)
+	}
+}
+
+// RecordError records error in all spans
+func (m multiSpan) RecordError(err error) {
+	for _, span := range m {
+		span.RecordError(err)
+	}
+}
+
+// End ends all spans in reverse order
+func (m multiSpan) End() {
+	for i := len(m) - 1; i >= 0; i-- {
+		m[i].End()
+	}
+}
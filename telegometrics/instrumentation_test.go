@@ -0,0 +1,87 @@
+package telegometrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var _ Instrumentation = Nop{}
+
+type testInstrumentation struct {
+	name   string
+	events *[]string
+}
+
+func (t testInstrumentation) AddCounter(name string, _ float64, _ ...Label) {
+	*t.events = append(*t.events, t.name+":counter:"+name)
+}
+
+func (t testInstrumentation) ObserveHistogram(name string, _ float64, _ ...Label) {
+	*t.events = append(*t.events, t.name+":histogram:"+name)
+}
+
+type testCtxKey string
+
+func (t testInstrumentation) StartSpan(ctx context.Context, name string, _ ...Label) (context.Context, Span) {
+	*t.events = append(*t.events, t.name+":start:"+name)
+	return context.WithValue(ctx, testCtxKey(t.name), true), testSpan(t)
+}
+
+type testSpan testInstrumentation
+
+func (t testSpan) SetAttributes(_ ...Label) {
+	*t.events = append(*t.events, t.name+":attributes")
+}
+
+func (t testSpan) RecordError(err error) {
+	*t.events = append(*t.events, t.name+":error:"+err.Error())
+}
+
+func (t testSpan) End() {
+	*t.events = append(*t.events, t.name+":end")
+}
+
+func TestMulti(t *testing.T) {
+	var events []string
+	instrumentation := Multi(
+		testInstrumentation{name: "a", events: &events},
+		testInstrumentation{name: "b", events: &events},
+	)
+
+	instrumentation.AddCounter("c", 1)
+	instrumentation.ObserveHistogram("h", 1)
+
+	ctx, span := instrumentation.StartSpan(t.Context(), "s")
+	assert.Equal(t, true, ctx.Value(testCtxKey("a")))
+	assert.Equal(t, true, ctx.Value(testCtxKey("b")))
+
+	span.SetAttributes(L("k", "v"))
+	span.RecordError(errors.New("e"))
+	span.End()
+
+	assert.Equal(t, []string{
+		"a:counter:c", "b:counter:c",
+		"a:histogram:h", "b:histogram:h",
+		"a:start:s", "b:start:s",
+		"a:attributes", "b:attributes",
+		"a:error:e", "b:error:e",
+		"b:end", "a:end",
+	}, events)
+}
+
+func TestNop(t *testing.T) {
+	assert.NotPanics(t, func() {
+		Nop{}.AddCounter("c", 1)
+		Nop{}.ObserveHistogram("h", 1)
+
+		ctx, span := Nop{}.StartSpan(t.Context(), "s")
+		assert.Equal(t, t.Context(), ctx)
+
+		span.SetAttributes(L("k", "v"))
+		span.RecordError(errors.New("e"))
+		span.End()
+	})
+}
@@ -0,0 +1,253 @@
+package telegometrics
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// PrometheusContentType content type of Prometheus text exposition format
+const PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultBuckets default histogram buckets in seconds
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60}
+
+// Metric types
+const (
+	metricCounter   = "counter"
+	metricHistogram = "histogram"
+)
+
+// promSeries represents one time series of metric
+type promSeries struct {
+	labels string
+	value  float64
+	counts []uint64
+	count  uint64
+}
+
+// promMetric represents metric with all its time series
+type promMetric struct {
+	kind   string
+	series map[string]*promSeries
+}
+
+// PrometheusExporter is an instrumentation that collects metrics in memory and exposes them in Prometheus text format,
+// spans are not recorded, use [Multi] to combine it with a tracer
+type PrometheusExporter struct {
+	buckets []float64
+
+	mutex   sync.Mutex
+	metrics map[string]*promMetric
+}
+
+// NewPrometheusExporter creates new Prometheus exporter with histogram buckets, if no buckets specified
+// [DefaultBuckets] are used
+func NewPrometheusExporter(buckets ...float64) *PrometheusExporter {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+
+	buckets = slices.Clone(buckets)
+	slices.Sort(buckets)
+
+	return &PrometheusExporter{
+		buckets: buckets,
+		metrics: make(map[string]*promMetric),
+	}
+}
+
+// AddCounter adds value to the counter
+func (p *PrometheusExporter) AddCounter(name string, value float64, labels ...Label) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	series, ok := p.series(name, metricCounter, labels)
+	if !ok {
+		return
+	}
+	series.value += value
+}
+
+// ObserveHistogram records value in the histogram
+func (p *PrometheusExporter) ObserveHistogram(name string, value float64, labels ...Label) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	series, ok := p.series(name, metricHistogram, labels)
+	if !ok {
+		return
+	}
+
+	if series.counts == nil {
+		series.counts = make([]uint64, len(p.buckets))
+	}
+	for i, bucket := range p.buckets {
+		if value <= bucket {
+			series.counts[i]++
+		}
+	}
+	series.count++
+	series.value += value
+}
+
+// StartSpan does nothing, returns the same context and span that does nothing
+func (p *PrometheusExporter) StartSpan(ctx context.Context, _ string, _ ...Label) (context.Context, Span) {
+	return ctx, NopSpan{}
+}
+
+// series returns time series of metric, false is returned if metric with the same name but different kind exists
+func (p *PrometheusExporter) series(name, kind string, labels []Label) (*promSeries, bool) {
+	metric, ok := p.metrics[name]
+	if !ok {
+		metric = &promMetric{
+			kind:   kind,
+			series: make(map[string]*promSeries),
+		}
+		p.metrics[name] = metric
+	}
+	if metric.kind != kind {
+		return nil, false
+	}
+
+	key := formatLabels(labels)
+	series, ok := metric.series[key]
+	if !ok {
+		series = &promSeries{
+			labels: key,
+		}
+		metric.series[key] = series
+	}
+
+	return series, true
+}
+
+// WriteTo writes all metrics in Prometheus text format
+func (p *PrometheusExporter) WriteTo(w io.Writer) (int64, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	cw := &countingWriter{writer: bufio.NewWriter(w)}
+
+	names := make([]string, 0, len(p.metrics))
+	for name := range p.metrics {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		metric := p.metrics[name]
+		cw.writeString("# TYPE " + name + " " + metric.kind + "\n")
+
+		keys := make([]string, 0, len(metric.series))
+		for key := range metric.series {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+
+		for _, key := range keys {
+			series := metric.series[key]
+			if metric.kind == metricCounter {
+				cw.writeSample(name, series.labels, formatFloat(series.value))
+				continue
+			}
+
+			count := strconv.FormatUint(series.count, 10)
+			for i, bucket := range p.buckets {
+				cw.writeSample(name+"_bucket", joinLabels(series.labels, `le="`+formatFloat(bucket)+`"`),
+					strconv.FormatUint(series.counts[i], 10))
+			}
+			cw.writeSample(name+"_bucket", joinLabels(series.labels, `le="+Inf"`), count)
+			cw.writeSample(name+"_sum", series.labels, formatFloat(series.value))
+			cw.writeSample(name+"_count", series.labels, count)
+		}
+	}
+
+	return cw.finish()
+}
+
+// ServeHTTP writes all metrics in Prometheus text format as HTTP response
+func (p *PrometheusExporter) ServeHTTP(writer http.ResponseWriter, _ *http.Request) {
+	writer.Header().Set("Content-Type", PrometheusContentType)
+	_, _ = p.WriteTo(writer)
+}
+
+// countingWriter writes strings and counts written bytes, first error stops all writes
+type countingWriter struct {
+	writer *bufio.Writer
+	n      int64
+	err    error
+}
+
+// writeString writes string if no error occurred before
+func (c *countingWriter) writeString(s string) {
+	if c.err != nil {
+		return
+	}
+	var n int
+	n, c.err = c.writer.WriteString(s)
+	c.n += int64(n)
+}
+
+// writeSample writes one sample of metric
+func (c *countingWriter) writeSample(name, labels, value string) {
+	if labels != "" {
+		name += "{" + labels + "}"
+	}
+	c.writeString(name + " " + value + "\n")
+}
+
+// finish flushes written data
+func (c *countingWriter) finish() (int64, error) {
+	if c.err != nil {
+		return c.n, c.err
+	}
+	return c.n, c.writer.Flush()
+}
+
+// formatLabels formats labels sorted by key
+func formatLabels(labels []Label) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	labels = slices.Clone(labels)
+	slices.SortFunc(labels, func(a, b Label) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+
+	formatted := make([]string, 0, len(labels))
+	for _, label := range labels {
+		formatted = append(formatted, label.Key+`="`+labelValueReplacer.Replace(label.Value)+`"`)
+	}
+	return strings.Join(formatted, ",")
+}
+
+// labelValueReplacer escapes label values
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// joinLabels joins formatted labels
+func joinLabels(labels, label string) string {
+	if labels == "" {
+		return label
+	}
+	return labels + "," + label
+}
+
+// formatFloat formats float value
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
@@ -0,0 +1,72 @@
+package telegometrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ Instrumentation = &PrometheusExporter{}
+
+func TestPrometheusExporter(t *testing.T) {
+	exporter := NewPrometheusExporter(0.1, 1)
+
+	exporter.AddCounter(MetricAPIRequests, 1, L(LabelStatus, StatusOK), L(LabelMethod, "sendMessage"))
+	exporter.AddCounter(MetricAPIRequests, 2, L(LabelMethod, "sendMessage"), L(LabelStatus, StatusOK))
+	exporter.AddCounter(MetricAPIRequests, 1, L(LabelMethod, "getMe"), L(LabelStatus, StatusError))
+	exporter.AddCounter(MetricUpdates, 1)
+	exporter.AddCounter("escaped", 1, L("value", "a\"b\\c\nd"))
+
+	exporter.ObserveHistogram(MetricAPIRequestDuration, 0.05, L(LabelMethod, "getMe"))
+	exporter.ObserveHistogram(MetricAPIRequestDuration, 0.5, L(LabelMethod, "getMe"))
+	exporter.ObserveHistogram(MetricAPIRequestDuration, 5, L(LabelMethod, "getMe"))
+
+	// Ignored, type mismatch
+	exporter.ObserveHistogram(MetricUpdates, 1)
+
+	ctx, span := exporter.StartSpan(t.Context(), "test")
+	assert.Equal(t, t.Context(), ctx)
+	span.SetAttributes(L("a", "b"))
+	span.RecordError(errors.New("test"))
+	span.End()
+
+	expected := `# TYPE escaped counter
+escaped{value="a\"b\\c\nd"} 1
+# TYPE telego_api_request_duration_seconds histogram
+telego_api_request_duration_seconds_bucket{method="getMe",le="0.1"} 1
+telego_api_request_duration_seconds_bucket{method="getMe",le="1"} 2
+telego_api_request_duration_seconds_bucket{method="getMe",le="+Inf"} 3
+telego_api_request_duration_seconds_sum{method="getMe"} 5.55
+telego_api_request_duration_seconds_count{method="getMe"} 3
+# TYPE telego_api_requests_total counter
+telego_api_requests_total{method="getMe",status="error"} 1
+telego_api_requests_total{method="sendMessage",status="ok"} 3
+# TYPE telego_updates_total counter
+telego_updates_total 1
+`
+
+	text := &strings.Builder{}
+	n, err := exporter.WriteTo(text)
+	require.NoError(t, err)
+	assert.Equal(t, expected, text.String())
+	assert.Equal(t, int64(len(expected)), n)
+
+	recorder := httptest.NewRecorder()
+	exporter.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, PrometheusContentType, recorder.Header().Get("Content-Type"))
+	assert.Equal(t, expected, recorder.Body.String())
+}
+
+func TestNewPrometheusExporter(t *testing.T) {
+	exporter := NewPrometheusExporter()
+	assert.Equal(t, DefaultBuckets, exporter.buckets)
+
+	exporter = NewPrometheusExporter(1, 0.5)
+	assert.Equal(t, []float64{0.5, 1}, exporter.buckets)
+}
@@ -0,0 +1,44 @@
+/*
+Package telegotest provides an in-process fake Telegram Bot API server for testing bots built with Telego.
+
+[Server] is based on [net/http/httptest] and keeps in-memory state of chats, messages, files, updates and webhook,
+so bots can be tested end-to-end without a real token or network access. Tests push synthetic updates
+(like [Server.PushMessage] or [Server.PushCallbackQuery]) and assert on requests made by the bot
+(like [Server.SentMessages] or [Server.WaitRequests]).
+
+Supported methods: getMe, getUpdates, setWebhook, deleteWebhook, getWebhookInfo, sendMessage, editMessageText,
+deleteMessage, answerCallbackQuery, sendPhoto, getFile and file downloads. Other methods return 404 Not Found error.
+
+[HandlerHarness] tests [telegohandler.BotHandler] routes without any server, it dispatches updates synchronously,
+captures all API calls made by handlers as typed parameters and reports which route matched the update
+(see [DispatchResult]).
+
+Update builders (like [NewMessage], [NewCallbackQuery], [NewInlineQuery], [NewChatMemberUpdate] or
+[NewBusinessConnection]) produce valid updates with sequential update IDs, for example, [MessageBuilder.Text]
+generates bot_command entities with offsets in UTF-16 code units:
+
+	update := telegotest.NewMessage().From(user).InChat(chat).Text("/start abc").Update()
+
+# Example
+
+	func TestEcho(t *testing.T) {
+		server, err := telegotest.NewServer()
+		require.NoError(t, err)
+		defer server.Close()
+
+		bot, err := server.NewBot()
+		require.NoError(t, err)
+
+		// Start bot using long polling or webhook
+		// ...
+
+		server.PushMessage(telegotest.NewMessage().Text("Hello").Build())
+
+		requests, err := server.WaitRequests(ctx, "sendMessage", 1)
+		require.NoError(t, err)
+		// ...
+	}
+
+Note: Formatting (parse mode) is not applied to text, it's stored as is.
+*/
+package telegotest
@@ -0,0 +1,428 @@
+package telegotest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mymmrac/telego"
+	"github.com/mymmrac/telego/internal/json"
+	ta "github.com/mymmrac/telego/telegoapi"
+	th "github.com/mymmrac/telego/telegohandler"
+)
+
+// Call represents API method call made by handlers
+type Call struct {
+	// Method name of the call
+	Method string
+	// Params of the call, pointer to parameters struct (like *telego.SendMessageParams) or nil for methods without
+	// parameters
+	Params any
+}
+
+// CallsOf returns parameters of calls with parameters of type T (like *telego.SendMessageParams)
+func CallsOf[T any](calls []Call) []T {
+	var params []T
+	for _, call := range calls {
+		if p, ok := call.Params.(T); ok {
+			params = append(params, p)
+		}
+	}
+	return params
+}
+
+// dispatchedCall represents API method call and ID of the update which handling it was made during
+type dispatchedCall struct {
+	call     Call
+	updateID int
+}
+
+// dispatchKey is a context key of the update ID being dispatched
+type dispatchKey struct{}
+
+// Responder returns result of API method call, returned error (like [ta.Error]) is returned from method call
+type Responder func(ctx context.Context, params any) (any, error)
+
+// HandlerHarnessOption represents an option that can be applied to [HandlerHarness]
+type HandlerHarnessOption func(h *HandlerHarness) error
+
+// WithResponder sets responder used for method calls instead of default one, default responder returns message
+// for methods that send or edit messages, bot user for getMe and true for all other methods
+func WithResponder(method string, responder Responder) HandlerHarnessOption {
+	return func(h *HandlerHarness) error {
+		if responder == nil {
+			return errors.New("nil responder")
+		}
+
+		h.responders[strings.ToLower(method)] = responder
+		return nil
+	}
+}
+
+// WithHarnessBotOptions sets options used to create bot
+func WithHarnessBotOptions(options ...telego.BotOption) HandlerHarnessOption {
+	return func(h *HandlerHarness) error {
+		h.botOptions = append(h.botOptions, options...)
+		return nil
+	}
+}
+
+// WithHarnessBotHandlerOptions sets options used to create bot handler
+func WithHarnessBotHandlerOptions(options ...th.BotHandlerOption) HandlerHarnessOption {
+	return func(h *HandlerHarness) error {
+		h.handlerOptions = append(h.handlerOptions, options...)
+		return nil
+	}
+}
+
+// HandlerHarness runs [th.BotHandler] routes synchronously without network access, capturing all API calls made
+// by handlers, it's safe for concurrent use
+type HandlerHarness struct {
+	bot            *telego.Bot
+	handler        *th.BotHandler
+	responders     map[string]Responder
+	botOptions     []telego.BotOption
+	handlerOptions []th.BotHandlerOption
+
+	mutex         sync.Mutex
+	calls         []dispatchedCall
+	routes        map[int]th.Route
+	lastUpdateID  int
+	lastMessageID int
+}
+
+// NewHandlerHarness creates new harness with bot handler which routes are registered by register function
+func NewHandlerHarness(register func(bh *th.BotHandler), options ...HandlerHarnessOption) (*HandlerHarness, error) {
+	h := &HandlerHarness{
+		responders: make(map[string]Responder),
+		routes:     make(map[int]th.Route),
+	}
+
+	for _, option := range options {
+		if err := option(h); err != nil {
+			return nil, fmt.Errorf("telegotest: handler harness options: %w", err)
+		}
+	}
+
+	botOptions := append([]telego.BotOption{telego.WithDiscardLogger()}, h.botOptions...)
+	botOptions = append(botOptions, telego.WithInterceptors(h.intercept))
+
+	var err error
+	h.bot, err = telego.NewBot(DefaultToken, botOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("telegotest: create bot: %w", err)
+	}
+
+	handlerOptions := append([]th.BotHandlerOption{}, h.handlerOptions...)
+	handlerOptions = append(handlerOptions, th.WithRouteHook(h.routeMatched))
+
+	h.handler, err = th.NewBotHandler(h.bot, nil, handlerOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("telegotest: create bot handler: %w", err)
+	}
+
+	register(h.handler)
+	return h, nil
+}
+
+// Bot returns bot used by handlers
+func (h *HandlerHarness) Bot() *telego.Bot {
+	return h.bot
+}
+
+// BotHandler returns bot handler
+func (h *HandlerHarness) BotHandler() *th.BotHandler {
+	return h.handler
+}
+
+// Calls returns all API calls made by handlers
+func (h *HandlerHarness) Calls() []Call {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	calls := make([]Call, 0, len(h.calls))
+	for _, call := range h.calls {
+		calls = append(calls, call.call)
+	}
+	return calls
+}
+
+// Dispatch handles update and returns once all handlers complete, update ID is assigned automatically if not set
+func (h *HandlerHarness) Dispatch(update telego.Update) *DispatchResult {
+	return h.DispatchContext(context.Background(), update)
+}
+
+// DispatchContext handles update with context and returns once all handlers complete, update ID is assigned
+// automatically if not set. Calls are attributed to the update by the context they are made with, so updates can be
+// dispatched concurrently as long as handlers make calls with context derived from the handler context.
+func (h *HandlerHarness) DispatchContext(ctx context.Context, update telego.Update) *DispatchResult {
+	h.mutex.Lock()
+	if update.UpdateID == 0 {
+		h.lastUpdateID++
+		update.UpdateID = h.lastUpdateID
+	} else {
+		h.lastUpdateID = max(h.lastUpdateID, update.UpdateID)
+	}
+	h.mutex.Unlock()
+
+	err := h.handler.HandleUpdate(context.WithValue(ctx, dispatchKey{}, update.UpdateID), update)
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	result := &DispatchResult{
+		Update: update,
+		Err:    err,
+	}
+	for _, call := range h.calls {
+		if call.updateID == update.UpdateID {
+			result.Calls = append(result.Calls, call.call)
+		}
+	}
+	if route, ok := h.routes[update.UpdateID]; ok {
+		result.Route = &route
+		delete(h.routes, update.UpdateID)
+	}
+
+	return result
+}
+
+// routeMatched records the last matched route of the update
+func (h *HandlerHarness) routeMatched(_ *th.Context, update telego.Update, route th.Route) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.routes[update.UpdateID] = route
+}
+
+// intercept captures API calls and returns responses without calling API
+func (h *HandlerHarness) intercept(ctx context.Context, methodName string, parameters any, _ telego.APICall) (
+	*ta.Response, error,
+) {
+	updateID, _ := ctx.Value(dispatchKey{}).(int)
+
+	h.mutex.Lock()
+	h.calls = append(h.calls, dispatchedCall{
+		call:     Call{Method: methodName, Params: parameters},
+		updateID: updateID,
+	})
+	responder, ok := h.responders[strings.ToLower(methodName)]
+	h.mutex.Unlock()
+
+	var result any
+	var err error
+	if ok {
+		result, err = responder(ctx, parameters)
+	} else {
+		result = h.defaultResult(methodName, parameters)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("telegotest: encode result: %w", err)
+	}
+
+	return &ta.Response{Ok: true, Result: data}, nil
+}
+
+// defaultResult returns result of method call based on its name and parameters
+func (h *HandlerHarness) defaultResult(methodName string, parameters any) any {
+	switch {
+	case methodName == "getMe":
+		return DefaultBotUser
+	case methodName == "sendChatAction":
+		return true
+	case methodName == "sendMediaGroup":
+		return []telego.Message{}
+	case strings.HasPrefix(methodName, "send"), strings.HasPrefix(methodName, "editMessage"),
+		methodName == "forwardMessage", methodName == "stopMessageLiveLocation":
+		return h.message(parameters)
+	case methodName == "copyMessage":
+		h.mutex.Lock()
+		defer h.mutex.Unlock()
+
+		h.lastMessageID++
+		return telego.MessageID{MessageID: h.lastMessageID}
+	default:
+		return true
+	}
+}
+
+// message returns message based on parameters that have chat ID, message ID, text or caption fields
+func (h *HandlerHarness) message(parameters any) *telego.Message {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	botUser := DefaultBotUser
+	message := &telego.Message{
+		From: &botUser,
+		Date: time.Now().Unix(),
+		Chat: telego.Chat{Type: telego.ChatTypePrivate},
+	}
+
+	params := reflect.Indirect(reflect.ValueOf(parameters))
+	if params.Kind() == reflect.Struct {
+		if chatID, ok := fieldValue[telego.ChatID](params, "ChatID"); ok {
+			message.Chat.ID = chatID.ID
+			message.Chat.Username = strings.TrimPrefix(chatID.Username, "@")
+		}
+		if messageID, ok := fieldValue[int](params, "MessageID"); ok {
+			message.MessageID = messageID
+			message.EditDate = message.Date
+		}
+		message.Text, _ = fieldValue[string](params, "Text")
+		message.Caption, _ = fieldValue[string](params, "Caption")
+	}
+
+	if message.MessageID == 0 {
+		h.lastMessageID++
+		message.MessageID = h.lastMessageID
+	}
+
+	return message
+}
+
+// fieldValue returns value of struct field by name if it exists and has type T
+func fieldValue[T any](v reflect.Value, name string) (T, bool) {
+	var zero T
+
+	field := v.FieldByName(name)
+	if !field.IsValid() || !field.CanInterface() {
+		return zero, false
+	}
+
+	value, ok := field.Interface().(T)
+	if !ok || field.IsZero() {
+		return zero, false
+	}
+	return value, true
+}
+
+// DispatchResult represents result of update handling
+type DispatchResult struct {
+	// Update that was handled
+	Update telego.Update
+	// Route of the last handler that matched the update, nil if no handler matched
+	Route *th.Route
+	// Calls made by handlers while handling the update
+	Calls []Call
+	// Err returned by handlers
+	Err error
+}
+
+// Matched returns true if any handler matched the update
+func (r *DispatchResult) Matched() bool {
+	return r.Route != nil
+}
+
+// String returns description of route and calls, used in assertion failures
+func (r *DispatchResult) String() string {
+	description := &strings.Builder{}
+
+	if r.Route != nil {
+		_, _ = fmt.Fprintf(description, "update %d matched route %s", r.Update.UpdateID, r.Route)
+	} else {
+		_, _ = fmt.Fprintf(description, "update %d matched no route", r.Update.UpdateID)
+	}
+
+	if r.Err != nil {
+		_, _ = fmt.Fprintf(description, ", error: %s", r.Err)
+	}
+
+	if len(r.Calls) == 0 {
+		description.WriteString(", no calls made")
+		return description.String()
+	}
+
+	description.WriteString(", calls:")
+	for _, call := range r.Calls {
+		params, err := json.Marshal(call.Params)
+		if err != nil {
+			params = []byte(fmt.Sprintf("%+v", call.Params))
+		}
+		_, _ = fmt.Fprintf(description, "\n\t%s %s", call.Method, params)
+	}
+
+	return description.String()
+}
+
+// AssertRoute asserts that handler with provided route path matched the update
+func (r *DispatchResult) AssertRoute(t testing.TB, path ...int) bool {
+	t.Helper()
+
+	if r.Route == nil || !reflect.DeepEqual(r.Route.Path, path) {
+		t.Errorf("expected route %v: %s", path, r)
+		return false
+	}
+	return true
+}
+
+// AssertNotMatched asserts that no handler matched the update
+func (r *DispatchResult) AssertNotMatched(t testing.TB) bool {
+	t.Helper()
+
+	if r.Route != nil {
+		t.Errorf("expected no route to match: %s", r)
+		return false
+	}
+	return true
+}
+
+// AssertReplied asserts that message was sent to the chat with text matching regular expression pattern
+func (r *DispatchResult) AssertReplied(t testing.TB, chatID telego.ChatID, pattern string) bool {
+	t.Helper()
+
+	textRegexp, err := regexp.Compile(pattern)
+	if err != nil {
+		t.Errorf("invalid pattern %q: %s", pattern, err)
+		return false
+	}
+
+	for _, params := range CallsOf[*telego.SendMessageParams](r.Calls) {
+		if params.ChatID == chatID && textRegexp.MatchString(params.Text) {
+			return true
+		}
+	}
+
+	t.Errorf("expected reply in chat %s with text matching %q: %s", chatID, pattern, r)
+	return false
+}
+
+// AssertAnsweredCallbackQuery asserts that callback query of the update was answered
+func (r *DispatchResult) AssertAnsweredCallbackQuery(t testing.TB) bool {
+	t.Helper()
+
+	if r.Update.CallbackQuery == nil {
+		t.Errorf("expected update with callback query: %s", r)
+		return false
+	}
+
+	for _, params := range CallsOf[*telego.AnswerCallbackQueryParams](r.Calls) {
+		if params.CallbackQueryID == r.Update.CallbackQuery.ID {
+			return true
+		}
+	}
+
+	t.Errorf("expected callback query %q to be answered: %s", r.Update.CallbackQuery.ID, r)
+	return false
+}
+
+// AssertNoCalls asserts that handlers made no API calls
+func (r *DispatchResult) AssertNoCalls(t testing.TB) bool {
+	t.Helper()
+
+	if len(r.Calls) != 0 {
+		t.Errorf("expected no calls: %s", r)
+		return false
+	}
+	return true
+}
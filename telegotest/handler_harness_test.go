@@ -0,0 +1,202 @@
+package telegotest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mymmrac/telego"
+	ta "github.com/mymmrac/telego/telegoapi"
+	th "github.com/mymmrac/telego/telegohandler"
+	tu "github.com/mymmrac/telego/telegoutil"
+)
+
+type testTB struct {
+	testing.TB
+	errors []string
+}
+
+func (t *testTB) Helper() {}
+
+func (t *testTB) Errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func registerTestRoutes(bh *th.BotHandler) {
+	bh.Use(func(ctx *th.Context, update telego.Update) error {
+		return ctx.Next(update)
+	})
+
+	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
+		_, err := ctx.Bot().SendMessage(ctx, tu.Messagef(message.Chat.ChatID(), "Hello %s", message.From.FirstName))
+		return err
+	}, th.CommandEqual("start"))
+
+	callbacks := bh.Group(th.AnyCallbackQuery())
+	callbacks.HandleCallbackQuery(func(ctx *th.Context, query telego.CallbackQuery) error {
+		return ctx.Bot().AnswerCallbackQuery(ctx, tu.CallbackQuery(query.ID))
+	}, th.CallbackDataEqual("ok"))
+}
+
+func TestHandlerHarness_Dispatch(t *testing.T) {
+	harness, err := NewHandlerHarness(registerTestRoutes)
+	require.NoError(t, err)
+	assert.NotNil(t, harness.Bot())
+	assert.NotNil(t, harness.BotHandler())
+
+	message := &telego.Message{
+		Chat: telego.Chat{ID: 1, Type: telego.ChatTypePrivate},
+		From: &telego.User{ID: 1, FirstName: "User"},
+		Text: "/start",
+	}
+
+	t.Run("message", func(t *testing.T) {
+		result := harness.Dispatch(telego.Update{Message: message})
+		require.NoError(t, result.Err)
+		assert.Equal(t, 1, result.Update.UpdateID)
+		assert.True(t, result.Matched())
+		assert.True(t, result.AssertRoute(t, 1))
+		assert.True(t, result.AssertReplied(t, tu.ID(1), "^Hello User$"))
+
+		sent := CallsOf[*telego.SendMessageParams](result.Calls)
+		require.Len(t, sent, 1)
+		assert.Equal(t, "Hello User", sent[0].Text)
+	})
+
+	t.Run("callback_query", func(t *testing.T) {
+		result := harness.Dispatch(telego.Update{CallbackQuery: &telego.CallbackQuery{ID: "query", Data: "ok"}})
+		require.NoError(t, result.Err)
+		assert.True(t, result.AssertRoute(t, 2, 0))
+		assert.True(t, result.AssertAnsweredCallbackQuery(t))
+	})
+
+	t.Run("not_matched", func(t *testing.T) {
+		result := harness.Dispatch(telego.Update{CallbackQuery: &telego.CallbackQuery{ID: "query", Data: "not ok"}})
+		assert.True(t, result.AssertNotMatched(t))
+		assert.True(t, result.AssertNoCalls(t))
+		assert.Contains(t, result.String(), "matched no route, no calls made")
+	})
+
+	t.Run("failed_assertions", func(t *testing.T) {
+		tb := &testTB{TB: t}
+
+		result := harness.Dispatch(telego.Update{Message: message})
+		assert.False(t, result.AssertRoute(tb, 2))
+		assert.False(t, result.AssertNotMatched(tb))
+		assert.False(t, result.AssertReplied(tb, tu.ID(2), "Hello"))
+		assert.False(t, result.AssertReplied(tb, tu.ID(1), "("))
+		assert.False(t, result.AssertAnsweredCallbackQuery(tb))
+		assert.False(t, result.AssertNoCalls(tb))
+		require.Len(t, tb.errors, 6)
+		assert.Contains(t, tb.errors[0], "matched route 1 (")
+		assert.Contains(t, tb.errors[0], `sendMessage {"chat_id":1,"text":"Hello User"}`)
+
+		result = harness.Dispatch(telego.Update{CallbackQuery: &telego.CallbackQuery{ID: "other", Data: "not ok"}})
+		assert.False(t, result.AssertAnsweredCallbackQuery(tb))
+		assert.False(t, result.AssertRoute(tb, 2, 0))
+	})
+
+	assert.Len(t, harness.Calls(), 3)
+}
+
+func TestHandlerHarness_DispatchConcurrent(t *testing.T) {
+	var started sync.WaitGroup
+	started.Add(2)
+
+	harness, err := NewHandlerHarness(func(bh *th.BotHandler) {
+		bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
+			_, err := ctx.Bot().SendMessage(ctx, tu.Message(message.Chat.ChatID(), "first"))
+			if err != nil {
+				return err
+			}
+
+			// Make sure calls of both updates interleave
+			started.Done()
+			started.Wait()
+
+			_, err = ctx.Bot().SendMessage(ctx, tu.Message(message.Chat.ChatID(), "second"))
+			return err
+		})
+	})
+	require.NoError(t, err)
+
+	results := make([]*DispatchResult, 2)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Go(func() {
+			results[i] = harness.Dispatch(NewMessage().InChat(telego.Chat{ID: int64(i + 1)}).Text("text").Update())
+		})
+	}
+	wg.Wait()
+
+	for i, result := range results {
+		require.NoError(t, result.Err)
+
+		params := CallsOf[*telego.SendMessageParams](result.Calls)
+		require.Len(t, params, 2)
+		for _, p := range params {
+			assert.Equal(t, int64(i+1), p.ChatID.ID)
+		}
+	}
+	assert.Len(t, harness.Calls(), 4)
+}
+
+func TestHandlerHarness_responders(t *testing.T) {
+	harness, err := NewHandlerHarness(registerTestRoutes,
+		WithResponder("sendMessage", func(context.Context, any) (any, error) {
+			return nil, &ta.Error{ErrorCode: 403, Description: "Forbidden: bot was blocked by the user"}
+		}),
+		WithHarnessBotOptions(telego.WithWarnings()),
+		WithHarnessBotHandlerOptions(th.WithErrorHandler(func(*th.Context, telego.Update, error) {})),
+	)
+	require.NoError(t, err)
+
+	result := harness.Dispatch(telego.Update{Message: &telego.Message{
+		Chat: telego.Chat{ID: 1},
+		From: &telego.User{ID: 1},
+		Text: "/start",
+	}})
+	require.ErrorIs(t, result.Err, ta.ErrBotBlocked)
+	assert.Contains(t, result.String(), "error:")
+
+	_, err = NewHandlerHarness(registerTestRoutes, WithResponder("getMe", nil))
+	require.Error(t, err)
+}
+
+func TestHandlerHarness_defaultResult(t *testing.T) {
+	harness, err := NewHandlerHarness(func(*th.BotHandler) {})
+	require.NoError(t, err)
+
+	bot := harness.Bot()
+	ctx := t.Context()
+
+	me, err := bot.GetMe(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultBotUser, *me)
+
+	photo, err := bot.SendPhoto(ctx, tu.Photo(tu.Username("@user"), tu.FileFromID("id")).WithCaption("caption"))
+	require.NoError(t, err)
+	assert.Equal(t, "user", photo.Chat.Username)
+	assert.Equal(t, "caption", photo.Caption)
+
+	edited, err := bot.EditMessageText(ctx, tu.EditMessageText(tu.ID(1), 42, "text"))
+	require.NoError(t, err)
+	assert.Equal(t, 42, edited.MessageID)
+	assert.NotZero(t, edited.EditDate)
+
+	messageID, err := bot.CopyMessage(ctx, tu.CopyMessage(tu.ID(1), tu.ID(2), 1))
+	require.NoError(t, err)
+	assert.NotZero(t, messageID.MessageID)
+
+	_, err = bot.SendMediaGroup(ctx, tu.MediaGroup(tu.ID(1)))
+	require.NoError(t, err)
+
+	require.NoError(t, bot.SendChatAction(ctx, tu.ChatAction(tu.ID(1), telego.ChatActionTyping)))
+	require.NoError(t, bot.DeleteMessage(ctx, tu.Delete(tu.ID(1), 1)))
+
+	assert.Len(t, harness.Calls(), 7)
+}
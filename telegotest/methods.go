@@ -0,0 +1,416 @@
+package telegotest
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/mymmrac/telego"
+	ta "github.com/mymmrac/telego/telegoapi"
+)
+
+// defaultUpdatesLimit is a default limit of updates returned by getUpdates method
+const defaultUpdatesLimit = 100
+
+// methodHandler handles API method request and returns its result
+type methodHandler func(ctx context.Context, request Request) (any, *ta.Error)
+
+// methods returns handlers of supported methods by lower-cased method name
+func (s *Server) methods() map[string]methodHandler {
+	return map[string]methodHandler{
+		"getme":               s.getMe,
+		"getupdates":          s.getUpdates,
+		"setwebhook":          s.setWebhook,
+		"deletewebhook":       s.deleteWebhook,
+		"getwebhookinfo":      s.getWebhookInfo,
+		"sendmessage":         s.sendMessage,
+		"editmessagetext":     s.editMessageText,
+		"deletemessage":       s.deleteMessage,
+		"answercallbackquery": s.answerCallbackQuery,
+		"sendphoto":           s.sendPhoto,
+		"getfile":             s.getFile,
+	}
+}
+
+// badRequest returns bad request error with description
+func badRequest(description string) *ta.Error {
+	return &ta.Error{ErrorCode: http.StatusBadRequest, Description: "Bad Request: " + description}
+}
+
+// decode decodes request into parameters returning bad request error on failure
+func decode(request Request, params any) *ta.Error {
+	if err := request.Decode(params); err != nil {
+		return badRequest(err.Error())
+	}
+	return nil
+}
+
+// chatMessageParams represents parameters that identify message
+type chatMessageParams struct {
+	ChatID    telego.ChatID `json:"chat_id"`
+	MessageID int           `json:"message_id"`
+}
+
+// sendMessageParams represents parameters of sendMessage method
+type sendMessageParams struct {
+	ChatID      telego.ChatID                `json:"chat_id"`
+	Text        string                       `json:"text"`
+	Entities    []telego.MessageEntity       `json:"entities"`
+	ReplyMarkup *telego.InlineKeyboardMarkup `json:"reply_markup"`
+}
+
+// editMessageTextParams represents parameters of editMessageText method
+type editMessageTextParams struct {
+	ChatID          telego.ChatID                `json:"chat_id"`
+	MessageID       int                          `json:"message_id"`
+	InlineMessageID string                       `json:"inline_message_id"`
+	Text            string                       `json:"text"`
+	Entities        []telego.MessageEntity       `json:"entities"`
+	ReplyMarkup     *telego.InlineKeyboardMarkup `json:"reply_markup"`
+}
+
+// sendPhotoParams represents parameters of sendPhoto method
+type sendPhotoParams struct {
+	ChatID          telego.ChatID                `json:"chat_id"`
+	Photo           string                       `json:"photo"`
+	Caption         string                       `json:"caption"`
+	CaptionEntities []telego.MessageEntity       `json:"caption_entities"`
+	ReplyMarkup     *telego.InlineKeyboardMarkup `json:"reply_markup"`
+}
+
+// setWebhookParams represents parameters of setWebhook method
+type setWebhookParams struct {
+	URL                string   `json:"url"`
+	AllowedUpdates     []string `json:"allowed_updates"`
+	DropPendingUpdates bool     `json:"drop_pending_updates"`
+	SecretToken        string   `json:"secret_token"`
+}
+
+// inlineKeyboard returns keyboard if it's inline keyboard, other keyboards are ignored
+func inlineKeyboard(keyboard *telego.InlineKeyboardMarkup) *telego.InlineKeyboardMarkup {
+	if keyboard == nil || len(keyboard.InlineKeyboard) == 0 {
+		return nil
+	}
+	return keyboard
+}
+
+// getMe returns bot user
+func (s *Server) getMe(context.Context, Request) (any, *ta.Error) {
+	return s.me, nil
+}
+
+// getUpdates returns pending updates, waiting for new ones up to timeout if there are none
+func (s *Server) getUpdates(ctx context.Context, request Request) (any, *ta.Error) {
+	var params telego.GetUpdatesParams
+	if err := decode(request, &params); err != nil {
+		return nil, err
+	}
+
+	limit := params.Limit
+	if limit <= 0 || limit > defaultUpdatesLimit {
+		limit = defaultUpdatesLimit
+	}
+
+	timeout := time.NewTimer(time.Duration(params.Timeout) * time.Second)
+	defer timeout.Stop()
+
+	for {
+		s.mutex.Lock()
+		if s.webhook.URL != "" {
+			s.mutex.Unlock()
+			return nil, &ta.Error{
+				ErrorCode:   http.StatusConflict,
+				Description: "Conflict: can't use getUpdates method while webhook is active",
+			}
+		}
+
+		if params.Offset != 0 {
+			for len(s.updates) > 0 && s.updates[0].UpdateID < params.Offset {
+				s.updates = s.updates[1:]
+			}
+		}
+
+		updates := make([]telego.Update, 0, min(limit, len(s.updates)))
+		updates = append(updates, s.updates[:min(limit, len(s.updates))]...)
+		changed := s.changed
+		s.mutex.Unlock()
+
+		if len(updates) > 0 || params.Timeout <= 0 {
+			return updates, nil
+		}
+
+		select {
+		case <-changed:
+		case <-timeout.C:
+			return updates, nil
+		case <-ctx.Done():
+			return updates, nil
+		case <-s.done:
+			return updates, nil
+		}
+	}
+}
+
+// setWebhook sets webhook, empty URL deletes it
+func (s *Server) setWebhook(_ context.Context, request Request) (any, *ta.Error) {
+	var params setWebhookParams
+	if err := decode(request, &params); err != nil {
+		return nil, err
+	}
+
+	if params.URL == "" {
+		return s.deleteWebhook(context.Background(), request)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.webhook = telego.WebhookInfo{
+		URL:            params.URL,
+		AllowedUpdates: params.AllowedUpdates,
+	}
+	s.secretToken = params.SecretToken
+	if params.DropPendingUpdates {
+		s.updates = nil
+	}
+	s.notify()
+
+	return true, nil
+}
+
+// deleteWebhook removes webhook and optionally drops pending updates
+func (s *Server) deleteWebhook(_ context.Context, request Request) (any, *ta.Error) {
+	var params telego.DeleteWebhookParams
+	if err := decode(request, &params); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.webhook = telego.WebhookInfo{}
+	s.secretToken = ""
+	if params.DropPendingUpdates {
+		s.updates = nil
+	}
+	s.notify()
+
+	return true, nil
+}
+
+// getWebhookInfo returns current webhook info
+func (s *Server) getWebhookInfo(context.Context, Request) (any, *ta.Error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	info := s.webhook
+	info.PendingUpdateCount = len(s.updates)
+	return info, nil
+}
+
+// sendBotMessage stores message sent by bot, must be called with mutex locked
+func (s *Server) sendBotMessage(chat *chatState, message telego.Message) telego.Message {
+	chat.lastMessageID++
+	message.MessageID = chat.lastMessageID
+	message.From = &s.me
+	message.Date = time.Now().Unix()
+	message.Chat = chat.chat
+
+	stored := message
+	chat.messages[message.MessageID] = &stored
+	s.sent = append(s.sent, message)
+	s.notify()
+
+	return message
+}
+
+// knownChat returns state of known chat, must be called with mutex locked
+func (s *Server) knownChat(chatID telego.ChatID) (*chatState, *ta.Error) {
+	if chatID.ID == 0 && chatID.Username != "" {
+		for _, chat := range s.chats {
+			if chat.chat.Username != "" && "@"+chat.chat.Username == chatID.Username {
+				return chat, nil
+			}
+		}
+	}
+
+	chat, ok := s.chats[chatID.ID]
+	if !ok || chatID.ID == 0 {
+		return nil, badRequest("chat not found")
+	}
+	return chat, nil
+}
+
+// sendMessage sends text message to known chat
+func (s *Server) sendMessage(_ context.Context, request Request) (any, *ta.Error) {
+	var params sendMessageParams
+	if err := decode(request, &params); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(params.Text) == "" {
+		return nil, badRequest("message text is empty")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	chat, err := s.knownChat(params.ChatID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.sendBotMessage(chat, telego.Message{
+		Text:        params.Text,
+		Entities:    params.Entities,
+		ReplyMarkup: inlineKeyboard(params.ReplyMarkup),
+	}), nil
+}
+
+// editMessageText edits text of message sent by bot
+func (s *Server) editMessageText(_ context.Context, request Request) (any, *ta.Error) {
+	var params editMessageTextParams
+	if err := decode(request, &params); err != nil {
+		return nil, err
+	}
+
+	if params.InlineMessageID != "" {
+		return nil, badRequest("inline messages are not supported")
+	}
+	if strings.TrimSpace(params.Text) == "" {
+		return nil, badRequest("message text is empty")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	chat, err := s.knownChat(params.ChatID)
+	if err != nil {
+		return nil, err
+	}
+
+	message, ok := chat.messages[params.MessageID]
+	if !ok {
+		return nil, badRequest("message to edit not found")
+	}
+	if message.From == nil || message.From.ID != s.me.ID {
+		return nil, badRequest("message can't be edited")
+	}
+	if message.Text == "" {
+		return nil, badRequest("there is no text in the message to edit")
+	}
+
+	replyMarkup := inlineKeyboard(params.ReplyMarkup)
+	if message.Text == params.Text && reflect.DeepEqual(message.Entities, params.Entities) &&
+		reflect.DeepEqual(message.ReplyMarkup, replyMarkup) {
+		return nil, badRequest("message is not modified: specified new message content and reply markup are " +
+			"exactly the same as a current content and reply markup of the message")
+	}
+
+	message.Text = params.Text
+	message.Entities = params.Entities
+	message.ReplyMarkup = replyMarkup
+	message.EditDate = time.Now().Unix()
+	s.notify()
+
+	return *message, nil
+}
+
+// deleteMessage deletes message from known chat
+func (s *Server) deleteMessage(_ context.Context, request Request) (any, *ta.Error) {
+	var params chatMessageParams
+	if err := decode(request, &params); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	chat, err := s.knownChat(params.ChatID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := chat.messages[params.MessageID]; !ok {
+		return nil, badRequest("message to delete not found")
+	}
+	delete(chat.messages, params.MessageID)
+	s.notify()
+
+	return true, nil
+}
+
+// answerCallbackQuery records answer to not yet answered callback query
+func (s *Server) answerCallbackQuery(_ context.Context, request Request) (any, *ta.Error) {
+	var params telego.AnswerCallbackQueryParams
+	if err := decode(request, &params); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	answered, ok := s.callbackQueries[params.CallbackQueryID]
+	if !ok || answered {
+		return nil, badRequest("query is too old and response timeout expired or query ID is invalid")
+	}
+
+	s.callbackQueries[params.CallbackQueryID] = true
+	s.callbackAnswers = append(s.callbackAnswers, params)
+	s.notify()
+
+	return true, nil
+}
+
+// sendPhoto sends photo uploaded or referenced by file ID to known chat
+func (s *Server) sendPhoto(_ context.Context, request Request) (any, *ta.Error) {
+	var params sendPhotoParams
+	if err := decode(request, &params); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	chat, err := s.knownChat(params.ChatID)
+	if err != nil {
+		return nil, err
+	}
+
+	var file *storedFile
+	if upload, ok := request.Files["photo"]; ok {
+		file = s.storeFile("photos", upload.Name, upload.Data)
+	} else if file, ok = s.files[params.Photo]; !ok {
+		return nil, badRequest("wrong file identifier/HTTP URL specified")
+	}
+
+	return s.sendBotMessage(chat, telego.Message{
+		Photo: []telego.PhotoSize{{
+			FileID:       file.file.FileID,
+			FileUniqueID: file.file.FileUniqueID,
+			FileSize:     int(file.file.FileSize),
+		}},
+		Caption:         params.Caption,
+		CaptionEntities: params.CaptionEntities,
+		ReplyMarkup:     inlineKeyboard(params.ReplyMarkup),
+	}), nil
+}
+
+// getFile returns stored file by its ID
+func (s *Server) getFile(_ context.Context, request Request) (any, *ta.Error) {
+	var params telego.GetFileParams
+	if err := decode(request, &params); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	file, ok := s.files[params.FileID]
+	if !ok {
+		return nil, badRequest("invalid file_id")
+	}
+	return file.file, nil
+}
@@ -0,0 +1,631 @@
+package telegotest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mymmrac/telego"
+	"github.com/mymmrac/telego/internal/json"
+	ta "github.com/mymmrac/telego/telegoapi"
+)
+
+// DefaultToken is a bot token used by server by default
+const DefaultToken = "123456789:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa" //nolint:gosec
+
+// DefaultBotUser is a bot user returned by getMe method by default
+var DefaultBotUser = telego.User{
+	ID:        123456789,
+	IsBot:     true,
+	FirstName: "Test Bot",
+	Username:  "test_bot",
+}
+
+// webhookRetryInterval is an interval between failed webhook delivery attempts
+const webhookRetryInterval = 100 * time.Millisecond
+
+// maxMultipartMemory is a max memory used to parse multipart requests
+const maxMultipartMemory = 32 << 20
+
+// UploadedFile represents file uploaded in multipart request
+type UploadedFile struct {
+	// Name of the file
+	Name string
+	// Data of the file
+	Data []byte
+}
+
+// Request represents API request received by server
+type Request struct {
+	// Method name of the request
+	Method string
+	// Fields of the request, string values are stored as is, other values are JSON encoded
+	Fields map[string]string
+	// Files uploaded with the request
+	Files map[string]UploadedFile
+}
+
+// Decode decodes request fields into v (usually pointer to parameters struct like [telego.SendMessageParams])
+func (r Request) Decode(v any) error {
+	for field, value := range r.Fields {
+		data, err := json.Marshal(map[string]json.RawMessage{field: json.RawMessage(value)})
+		if err == nil {
+			err = json.Unmarshal(data, v)
+		}
+		if err == nil {
+			continue
+		}
+
+		quoted, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("telegotest: encode field %q: %w", field, err)
+		}
+
+		data, err = json.Marshal(map[string]json.RawMessage{field: quoted})
+		if err != nil {
+			return fmt.Errorf("telegotest: encode field %q: %w", field, err)
+		}
+
+		if err = json.Unmarshal(data, v); err != nil {
+			return fmt.Errorf("telegotest: decode field %q: %w", field, err)
+		}
+	}
+	return nil
+}
+
+// ServerOption represents an option that can be applied to [Server]
+type ServerOption func(s *Server) error
+
+// WithToken sets bot token accepted by server, default is [DefaultToken]
+func WithToken(token string) ServerOption {
+	return func(s *Server) error {
+		if token == "" {
+			return errors.New("empty token")
+		}
+
+		s.token = token
+		return nil
+	}
+}
+
+// WithBotUser sets bot user returned by getMe method, default is [DefaultBotUser]
+func WithBotUser(user telego.User) ServerOption {
+	return func(s *Server) error {
+		if user.ID == 0 {
+			return errors.New("zero bot user ID")
+		}
+
+		user.IsBot = true
+		s.me = user
+		return nil
+	}
+}
+
+// storedFile represents file stored on server
+type storedFile struct {
+	file telego.File
+	data []byte
+}
+
+// chatState represents chat stored on server
+type chatState struct {
+	chat          telego.Chat
+	messages      map[int]*telego.Message
+	lastMessageID int
+}
+
+// Server is a fake Telegram Bot API server, it's safe for concurrent use
+type Server struct {
+	token    string
+	me       telego.User
+	server   *httptest.Server
+	handlers map[string]methodHandler
+	done     chan struct{}
+	wg       sync.WaitGroup
+
+	mutex           sync.Mutex
+	changed         chan struct{}
+	updates         []telego.Update
+	lastUpdateID    int
+	webhook         telego.WebhookInfo
+	secretToken     string
+	chats           map[int64]*chatState
+	callbackQueries map[string]bool
+	callbackAnswers []telego.AnswerCallbackQueryParams
+	files           map[string]*storedFile
+	filePaths       map[string]*storedFile
+	lastFileID      int
+	requests        []Request
+	sent            []telego.Message
+}
+
+// NewServer creates and starts new fake Bot API server, it should be closed after use by calling [Server.Close]
+func NewServer(options ...ServerOption) (*Server, error) {
+	s := &Server{
+		token:           DefaultToken,
+		me:              DefaultBotUser,
+		done:            make(chan struct{}),
+		changed:         make(chan struct{}),
+		chats:           make(map[int64]*chatState),
+		callbackQueries: make(map[string]bool),
+		files:           make(map[string]*storedFile),
+		filePaths:       make(map[string]*storedFile),
+	}
+
+	for _, option := range options {
+		if err := option(s); err != nil {
+			return nil, fmt.Errorf("telegotest: options: %w", err)
+		}
+	}
+
+	s.handlers = s.methods()
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+
+	s.wg.Add(1)
+	go s.deliverWebhooks()
+
+	return s, nil
+}
+
+// Close stops the server and waits for all pending requests to finish
+func (s *Server) Close() {
+	close(s.done)
+	s.wg.Wait()
+	s.server.Close()
+}
+
+// URL returns base URL of the server that can be used with [telego.WithAPIServer]
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// Token returns bot token accepted by server
+func (s *Server) Token() string {
+	return s.token
+}
+
+// Me returns bot user
+func (s *Server) Me() telego.User {
+	return s.me
+}
+
+// NewBot creates new bot that uses the server, provided options are applied after server options
+func (s *Server) NewBot(options ...telego.BotOption) (*telego.Bot, error) {
+	return telego.NewBot(s.token, append([]telego.BotOption{telego.WithAPIServer(s.URL())}, options...)...)
+}
+
+// AddChat adds chat to the server, bot can send messages only to known chats, chats of pushed updates are added
+// automatically
+func (s *Server) AddChat(chat telego.Chat) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.chatState(chat)
+}
+
+// AddFile stores file on the server, so it can be used in updates and downloaded by bot
+func (s *Server) AddFile(name string, data []byte) telego.File {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.storeFile("documents", name, data).file
+}
+
+// PushUpdate adds update to pending updates, update ID is assigned automatically
+func (s *Server) PushUpdate(update telego.Update) telego.Update {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.pushUpdate(update)
+}
+
+// PushMessage adds message sent by user to the chat and pushes update with it, message ID and date are assigned
+// automatically if not set
+func (s *Server) PushMessage(message telego.Message) telego.Message {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	chat := s.chatState(message.Chat)
+	if message.MessageID == 0 {
+		chat.lastMessageID++
+		message.MessageID = chat.lastMessageID
+	} else {
+		chat.lastMessageID = max(chat.lastMessageID, message.MessageID)
+	}
+	if message.Date == 0 {
+		message.Date = time.Now().Unix()
+	}
+	message.Chat = chat.chat
+
+	stored := message
+	chat.messages[message.MessageID] = &stored
+
+	s.pushUpdate(telego.Update{Message: &message})
+	return message
+}
+
+// PushCallbackQuery pushes update with callback query, its ID and chat instance are assigned automatically if not set
+func (s *Server) PushCallbackQuery(query telego.CallbackQuery) telego.CallbackQuery {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if query.ID == "" {
+		query.ID = fmt.Sprintf("query_%d", len(s.callbackQueries)+1)
+	}
+	if query.ChatInstance == "" {
+		query.ChatInstance = "chat_instance"
+	}
+	if query.Message != nil {
+		s.chatState(query.Message.GetChat())
+	}
+	s.callbackQueries[query.ID] = false
+
+	s.pushUpdate(telego.Update{CallbackQuery: &query})
+	return query
+}
+
+// Requests returns all API requests received by server
+func (s *Server) Requests() []Request {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return slices.Clone(s.requests)
+}
+
+// WaitRequests waits until server receives at least count requests of the method (all requests if method is empty)
+// and returns them
+func (s *Server) WaitRequests(ctx context.Context, method string, count int) ([]Request, error) {
+	for {
+		s.mutex.Lock()
+		var requests []Request
+		for _, request := range s.requests {
+			if method == "" || strings.EqualFold(request.Method, method) {
+				requests = append(requests, request)
+			}
+		}
+		changed := s.changed
+		s.mutex.Unlock()
+
+		if len(requests) >= count {
+			return requests, nil
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return requests, fmt.Errorf("telegotest: wait %d %q requests, got %d: %w",
+				count, method, len(requests), ctx.Err())
+		}
+	}
+}
+
+// SentMessages returns all messages sent by bot
+func (s *Server) SentMessages() []telego.Message {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return slices.Clone(s.sent)
+}
+
+// Messages returns current messages in the chat sorted by message ID, edited messages have their latest version and
+// deleted messages are not returned
+func (s *Server) Messages(chatID int64) []telego.Message {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	chat, ok := s.chats[chatID]
+	if !ok {
+		return nil
+	}
+
+	messages := make([]telego.Message, 0, len(chat.messages))
+	for _, message := range chat.messages {
+		messages = append(messages, *message)
+	}
+	slices.SortFunc(messages, func(a, b telego.Message) int { return a.MessageID - b.MessageID })
+
+	return messages
+}
+
+// CallbackAnswers returns all answers to callback queries
+func (s *Server) CallbackAnswers() []telego.AnswerCallbackQueryParams {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return slices.Clone(s.callbackAnswers)
+}
+
+// notify wakes up everyone waiting for state change, must be called with mutex locked
+func (s *Server) notify() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// pushUpdate adds update to pending updates, must be called with mutex locked
+func (s *Server) pushUpdate(update telego.Update) telego.Update {
+	s.lastUpdateID++
+	update.UpdateID = s.lastUpdateID
+	s.updates = append(s.updates, update)
+	s.notify()
+	return update
+}
+
+// chatState returns state of the chat creating it if needed, must be called with mutex locked
+func (s *Server) chatState(chat telego.Chat) *chatState {
+	state, ok := s.chats[chat.ID]
+	if !ok {
+		if chat.Type == "" {
+			chat.Type = telego.ChatTypePrivate
+		}
+
+		state = &chatState{
+			chat:     chat,
+			messages: make(map[int]*telego.Message),
+		}
+		s.chats[chat.ID] = state
+	}
+	return state
+}
+
+// storeFile stores file data, must be called with mutex locked
+func (s *Server) storeFile(dir, name string, data []byte) *storedFile {
+	s.lastFileID++
+	file := &storedFile{
+		file: telego.File{
+			FileID:       fmt.Sprintf("file_%d", s.lastFileID),
+			FileUniqueID: fmt.Sprintf("unique_%d", s.lastFileID),
+			FileSize:     int64(len(data)),
+			FilePath:     fmt.Sprintf("%s/file_%d%s", dir, s.lastFileID, path.Ext(name)),
+		},
+		data: data,
+	}
+
+	s.files[file.file.FileID] = file
+	s.filePaths[file.file.FilePath] = file
+	return file
+}
+
+// serveHTTP handles API and file download requests
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if filePath, ok := strings.CutPrefix(r.URL.Path, "/file/bot"+s.token+"/"); ok {
+		s.serveFile(w, filePath)
+		return
+	}
+
+	token, method, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, "/bot"), "/")
+	if !ok || token != s.token || !strings.HasPrefix(r.URL.Path, "/bot") {
+		writeError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	request, err := readRequest(r, method)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Bad Request: "+err.Error())
+		return
+	}
+
+	s.mutex.Lock()
+	s.requests = append(s.requests, request)
+	s.notify()
+	s.mutex.Unlock()
+
+	handler, ok := s.handlers[strings.ToLower(method)]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	result, apiErr := handler(r.Context(), request)
+	if apiErr != nil {
+		writeError(w, apiErr.ErrorCode, apiErr.Description)
+		return
+	}
+	writeResult(w, result)
+}
+
+// serveFile handles file download requests
+func (s *Server) serveFile(w http.ResponseWriter, filePath string) {
+	s.mutex.Lock()
+	file, ok := s.filePaths[filePath]
+	s.mutex.Unlock()
+
+	if !ok {
+		http.NotFound(w, nil)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	_, _ = w.Write(file.data)
+}
+
+// readRequest reads request fields and files from JSON, multipart or form encoded body
+func readRequest(r *http.Request, method string) (Request, error) {
+	request := Request{
+		Method: method,
+		Fields: make(map[string]string),
+		Files:  make(map[string]UploadedFile),
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case ta.ContentTypeJSON:
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return request, fmt.Errorf("read body: %w", err)
+		}
+		if len(bytes.TrimSpace(body)) == 0 {
+			return request, nil
+		}
+
+		var fields map[string]json.RawMessage
+		if err = json.Unmarshal(body, &fields); err != nil {
+			return request, fmt.Errorf("decode body: %w", err)
+		}
+
+		for field, value := range fields {
+			var text string
+			if err = json.Unmarshal(value, &text); err == nil {
+				request.Fields[field] = text
+			} else {
+				request.Fields[field] = string(value)
+			}
+		}
+	case "multipart/form-data":
+		if err := r.ParseMultipartForm(maxMultipartMemory); err != nil {
+			return request, fmt.Errorf("parse multipart: %w", err)
+		}
+
+		for field, values := range r.MultipartForm.Value {
+			request.Fields[field] = values[0]
+		}
+
+		for field, headers := range r.MultipartForm.File {
+			file, err := headers[0].Open()
+			if err != nil {
+				return request, fmt.Errorf("open file %q: %w", field, err)
+			}
+
+			data, err := io.ReadAll(file)
+			_ = file.Close()
+			if err != nil {
+				return request, fmt.Errorf("read file %q: %w", field, err)
+			}
+
+			request.Files[field] = UploadedFile{Name: headers[0].Filename, Data: data}
+		}
+	default:
+		if err := r.ParseForm(); err != nil {
+			return request, fmt.Errorf("parse form: %w", err)
+		}
+
+		for field, values := range r.Form {
+			request.Fields[field] = values[0]
+		}
+	}
+
+	return request, nil
+}
+
+// writeResult writes successful response
+func writeResult(w http.ResponseWriter, result any) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Internal Server Error: "+err.Error())
+		return
+	}
+
+	writeResponse(w, http.StatusOK, &ta.Response{Ok: true, Result: data})
+}
+
+// writeError writes error response
+func writeError(w http.ResponseWriter, code int, description string) {
+	writeResponse(w, code, &ta.Response{Ok: false, Error: &ta.Error{ErrorCode: code, Description: description}})
+}
+
+// writeResponse writes response with status code
+func writeResponse(w http.ResponseWriter, code int, response *ta.Response) {
+	data, err := json.Marshal(response)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", ta.ContentTypeJSON)
+	w.WriteHeader(code)
+	_, _ = w.Write(data)
+}
+
+// deliverWebhooks sends pending updates to webhook URL if it's set
+func (s *Server) deliverWebhooks() {
+	defer s.wg.Done()
+
+	for {
+		s.mutex.Lock()
+		url, secretToken := s.webhook.URL, s.secretToken
+		var update *telego.Update
+		if url != "" && len(s.updates) > 0 {
+			update = &s.updates[0]
+		}
+		changed := s.changed
+		s.mutex.Unlock()
+
+		if update == nil {
+			select {
+			case <-changed:
+				continue
+			case <-s.done:
+				return
+			}
+		}
+
+		err := s.deliverWebhook(url, secretToken, update)
+
+		s.mutex.Lock()
+		if err == nil {
+			if len(s.updates) > 0 && s.updates[0].UpdateID == update.UpdateID {
+				s.updates = s.updates[1:]
+			}
+		} else {
+			s.webhook.LastErrorDate = time.Now().Unix()
+			s.webhook.LastErrorMessage = err.Error()
+		}
+		s.notify()
+		s.mutex.Unlock()
+
+		if err != nil {
+			select {
+			case <-time.After(webhookRetryInterval):
+			case <-s.done:
+				return
+			}
+		}
+	}
+}
+
+// deliverWebhook sends update to webhook URL
+func (s *Server) deliverWebhook(url, secretToken string, update *telego.Update) error {
+	data, err := json.Marshal(update)
+	if err != nil {
+		return fmt.Errorf("encode update: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-s.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", ta.ContentTypeJSON)
+	if secretToken != "" {
+		req.Header.Set(telego.WebhookSecretTokenHeader, secretToken)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("send update: %w", err)
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("wrong response from the webhook: %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,301 @@
+package telegotest
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mymmrac/telego"
+	"github.com/mymmrac/telego/internal/json"
+	ta "github.com/mymmrac/telego/telegoapi"
+	tu "github.com/mymmrac/telego/telegoutil"
+)
+
+var testUser = telego.User{ID: 1, FirstName: "User"}
+
+func newTestServer(t *testing.T) (*Server, *telego.Bot) {
+	t.Helper()
+
+	server, err := NewServer()
+	require.NoError(t, err)
+	t.Cleanup(server.Close)
+
+	bot, err := server.NewBot(telego.WithDiscardLogger())
+	require.NoError(t, err)
+
+	return server, bot
+}
+
+type testNamedReader struct {
+	io.Reader
+}
+
+func (testNamedReader) Name() string {
+	return "photo.jpg"
+}
+
+func TestNewServer(t *testing.T) {
+	user := telego.User{ID: 42, FirstName: "Bot"}
+	token := "42:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+
+	server, err := NewServer(WithToken(token), WithBotUser(user))
+	require.NoError(t, err)
+	defer server.Close()
+
+	user.IsBot = true
+	assert.Equal(t, user, server.Me())
+	assert.Equal(t, token, server.Token())
+
+	bot, err := server.NewBot(telego.WithDiscardLogger())
+	require.NoError(t, err)
+
+	me, err := bot.GetMe(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, &user, me)
+
+	_, err = NewServer(WithToken(""))
+	require.Error(t, err)
+
+	_, err = NewServer(WithBotUser(telego.User{}))
+	require.Error(t, err)
+}
+
+func TestServer_longPolling(t *testing.T) {
+	server, bot := newTestServer(t)
+
+	updates, err := bot.UpdatesViaLongPolling(t.Context(), nil)
+	require.NoError(t, err)
+
+	pushed := server.PushMessage(telego.Message{
+		Chat: telego.Chat{ID: 1},
+		From: &testUser,
+		Text: "Hello",
+	})
+	assert.Equal(t, 1, pushed.MessageID)
+	assert.Equal(t, telego.ChatTypePrivate, pushed.Chat.Type)
+
+	select {
+	case update := <-updates:
+		require.NotNil(t, update.Message)
+		assert.Equal(t, 1, update.UpdateID)
+		assert.Equal(t, "Hello", update.Message.Text)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+
+	sent, err := bot.SendMessage(t.Context(), tu.Message(tu.ID(1), "Hi"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, sent.MessageID)
+	assert.Equal(t, server.Me().ID, sent.From.ID)
+
+	requests, err := server.WaitRequests(t.Context(), "sendMessage", 1)
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+
+	var params telego.SendMessageParams
+	require.NoError(t, requests[0].Decode(&params))
+	assert.Equal(t, tu.ID(1), params.ChatID)
+	assert.Equal(t, "Hi", params.Text)
+
+	require.Len(t, server.SentMessages(), 1)
+	assert.Equal(t, "Hi", server.SentMessages()[0].Text)
+	assert.Len(t, server.Messages(1), 2)
+	assert.Empty(t, server.Messages(2))
+}
+
+func TestServer_messages(t *testing.T) {
+	server, bot := newTestServer(t)
+	ctx := t.Context()
+
+	_, err := bot.SendMessage(ctx, tu.Message(tu.ID(1), "text"))
+	require.ErrorIs(t, err, ta.ErrChatNotFound)
+
+	server.AddChat(telego.Chat{ID: 1, Type: telego.ChatTypePrivate})
+
+	_, err = bot.SendMessage(ctx, tu.Message(tu.ID(1), " "))
+	require.ErrorIs(t, err, ta.ErrMessageTextEmpty)
+
+	keyboard := tu.InlineKeyboard(tu.InlineKeyboardRow(tu.InlineKeyboardButton("1").WithCallbackData("1")))
+	sent, err := bot.SendMessage(ctx, tu.Message(tu.ID(1), "text").WithReplyMarkup(keyboard))
+	require.NoError(t, err)
+	assert.Equal(t, keyboard, sent.ReplyMarkup)
+
+	edited, err := bot.EditMessageText(ctx, tu.EditMessageText(tu.ID(1), sent.MessageID, "edited"))
+	require.NoError(t, err)
+	assert.Equal(t, "edited", edited.Text)
+	assert.Nil(t, edited.ReplyMarkup)
+	assert.NotZero(t, edited.EditDate)
+
+	_, err = bot.EditMessageText(ctx, tu.EditMessageText(tu.ID(1), sent.MessageID, "edited"))
+	require.ErrorIs(t, err, ta.ErrMessageNotModified)
+
+	_, err = bot.EditMessageText(ctx, tu.EditMessageText(tu.ID(1), 42, "edited"))
+	require.ErrorIs(t, err, ta.ErrMessageToEditNotFound)
+
+	user := server.PushMessage(telego.Message{Chat: telego.Chat{ID: 1}, From: &testUser, Text: "user"})
+	_, err = bot.EditMessageText(ctx, tu.EditMessageText(tu.ID(1), user.MessageID, "edited"))
+	require.ErrorIs(t, err, ta.ErrMessageCantBeEdited)
+
+	messages := server.Messages(1)
+	require.Len(t, messages, 2)
+	assert.Equal(t, "edited", messages[0].Text)
+
+	err = bot.DeleteMessage(ctx, tu.Delete(tu.ID(1), sent.MessageID))
+	require.NoError(t, err)
+	assert.Len(t, server.Messages(1), 1)
+
+	err = bot.DeleteMessage(ctx, tu.Delete(tu.ID(1), sent.MessageID))
+	require.ErrorIs(t, err, ta.ErrMessageToDeleteNotFound)
+}
+
+func TestServer_callbackQuery(t *testing.T) {
+	server, bot := newTestServer(t)
+
+	query := server.PushCallbackQuery(telego.CallbackQuery{From: testUser, Data: "data"})
+	assert.NotEmpty(t, query.ID)
+
+	err := bot.AnswerCallbackQuery(t.Context(), tu.CallbackQuery(query.ID).WithText("done"))
+	require.NoError(t, err)
+
+	err = bot.AnswerCallbackQuery(t.Context(), tu.CallbackQuery(query.ID))
+	require.ErrorIs(t, err, ta.ErrQueryTooOld)
+
+	answers := server.CallbackAnswers()
+	require.Len(t, answers, 1)
+	assert.Equal(t, "done", answers[0].Text)
+}
+
+func TestServer_files(t *testing.T) {
+	server, bot := newTestServer(t)
+	ctx := t.Context()
+
+	server.AddChat(telego.Chat{ID: 1})
+
+	sent, err := bot.SendPhoto(ctx, tu.Photo(tu.ID(1), tu.File(testNamedReader{Reader: strings.NewReader("image")})).
+		WithCaption("123"))
+	require.NoError(t, err)
+	require.Len(t, sent.Photo, 1)
+	assert.Equal(t, "123", sent.Caption)
+
+	requests := server.Requests()
+	require.Len(t, requests, 1)
+	assert.Equal(t, UploadedFile{Name: "photo.jpg", Data: []byte("image")}, requests[0].Files["photo"])
+
+	buf := &bytes.Buffer{}
+	file, err := bot.DownloadFile(ctx, sent.Photo[0].FileID, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "image", buf.String())
+	assert.True(t, strings.HasPrefix(file.FilePath, "photos/"))
+
+	resent, err := bot.SendPhoto(ctx, tu.Photo(tu.ID(1), tu.FileFromID(sent.Photo[0].FileID)))
+	require.NoError(t, err)
+	assert.Equal(t, sent.Photo, resent.Photo)
+
+	_, err = bot.SendPhoto(ctx, tu.Photo(tu.ID(1), tu.FileFromID("unknown")))
+	require.ErrorIs(t, err, ta.ErrWrongFileID)
+
+	document := server.AddFile("doc.txt", []byte("document"))
+	buf.Reset()
+	_, err = bot.DownloadFile(ctx, document.FileID, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "document", buf.String())
+
+	_, err = bot.GetFile(ctx, &telego.GetFileParams{FileID: "unknown"})
+	require.ErrorIs(t, err, ta.ErrBadRequest)
+
+	resp, err := http.Get(server.URL() + "/file/bot" + server.Token() + "/unknown") //nolint:noctx
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestServer_webhook(t *testing.T) {
+	server, bot := newTestServer(t)
+	ctx := t.Context()
+
+	failures := 1
+	received := make(chan telego.Update, 1)
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get(telego.WebhookSecretTokenHeader))
+
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		var update telego.Update
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.NoError(t, json.Unmarshal(body, &update))
+		received <- update
+	}))
+	defer webhook.Close()
+
+	server.PushMessage(telego.Message{Chat: telego.Chat{ID: 1}, Text: "dropped"})
+
+	err := bot.SetWebhook(ctx, tu.Webhook(webhook.URL).WithSecretToken("secret").WithDropPendingUpdates())
+	require.NoError(t, err)
+
+	_, err = bot.GetUpdates(ctx, nil)
+	require.ErrorIs(t, err, ta.ErrConflict)
+
+	server.PushMessage(telego.Message{Chat: telego.Chat{ID: 1}, Text: "delivered"})
+
+	select {
+	case update := <-received:
+		require.NotNil(t, update.Message)
+		assert.Equal(t, "delivered", update.Message.Text)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+
+	info, err := bot.GetWebhookInfo(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, webhook.URL, info.URL)
+	assert.NotEmpty(t, info.LastErrorMessage)
+
+	err = bot.DeleteWebhook(ctx, nil)
+	require.NoError(t, err)
+
+	updates, err := bot.GetUpdates(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, updates)
+}
+
+func TestServer_errors(t *testing.T) {
+	server, bot := newTestServer(t)
+
+	otherBot, err := telego.NewBot("1:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+		telego.WithAPIServer(server.URL()), telego.WithDiscardLogger())
+	require.NoError(t, err)
+
+	_, err = otherBot.GetMe(t.Context())
+	require.ErrorIs(t, err, ta.ErrUnauthorized)
+
+	err = bot.LogOut(t.Context())
+	require.ErrorIs(t, err, ta.ErrNotFound)
+}
+
+func TestRequest_Decode(t *testing.T) {
+	request := Request{Fields: map[string]string{
+		"chat_id":  "@user",
+		"text":     "true",
+		"entities": `[{"type":"bold","offset":0,"length":4}]`,
+	}}
+
+	var params telego.SendMessageParams
+	require.NoError(t, request.Decode(&params))
+	assert.Equal(t, telego.SendMessageParams{
+		ChatID:   tu.Username("@user"),
+		Text:     "true",
+		Entities: []telego.MessageEntity{{Type: telego.EntityTypeBold, Length: 4}},
+	}, params)
+}
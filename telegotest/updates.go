@@ -0,0 +1,605 @@
+package telegotest
+
+import (
+	"regexp"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf16"
+
+	"github.com/mymmrac/telego"
+)
+
+// DefaultUser is a user used by update builders by default
+var DefaultUser = telego.User{
+	ID:           1,
+	FirstName:    "User",
+	Username:     "user",
+	LanguageCode: "en",
+}
+
+// DefaultGroup is a chat used by chat member update builder by default
+var DefaultGroup = telego.Chat{
+	ID:    -1001,
+	Type:  telego.ChatTypeSupergroup,
+	Title: "Group",
+}
+
+// botCommandRegexp matches bot commands that Telegram converts to bot_command entities
+var botCommandRegexp = regexp.MustCompile(`(?:^|\s)(/[a-zA-Z0-9_]{1,64}(?:@[a-zA-Z0-9_]{1,32})?)`)
+
+var (
+	lastUpdateID  atomic.Int64
+	lastMessageID atomic.Int64
+	lastQueryID   atomic.Int64
+)
+
+// nextUpdateID returns next sequential update ID
+func nextUpdateID() int {
+	return int(lastUpdateID.Add(1))
+}
+
+// UpdateBuilder represents builder of update
+type UpdateBuilder interface {
+	// Update builds update with next sequential update ID
+	Update() telego.Update
+}
+
+// UpdateSequence assigns sequential update IDs starting from 1, it's useful when deterministic update IDs are needed,
+// by default builders use IDs that are sequential across all builders
+type UpdateSequence struct {
+	mutex sync.Mutex
+	last  int
+}
+
+// NewUpdateSequence creates new update sequence
+func NewUpdateSequence() *UpdateSequence {
+	return &UpdateSequence{}
+}
+
+// Next builds update and assigns next update ID of the sequence
+func (s *UpdateSequence) Next(builder UpdateBuilder) telego.Update {
+	update := builder.Update()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.last++
+	update.UpdateID = s.last
+	return update
+}
+
+// botCommandEntities returns bot_command entities of commands in text with offsets in UTF-16 code units
+func botCommandEntities(text string) []telego.MessageEntity {
+	var entities []telego.MessageEntity
+	for _, match := range botCommandRegexp.FindAllStringSubmatchIndex(text, -1) {
+		entities = append(entities, telego.MessageEntity{
+			Type:   telego.EntityTypeBotCommand,
+			Offset: utf16TextLen(text[:match[2]]),
+			Length: utf16TextLen(text[match[2]:match[3]]),
+		})
+	}
+	return entities
+}
+
+// utf16TextLen returns length of text in UTF-16 code units
+func utf16TextLen(text string) int {
+	length := 0
+	for _, r := range text {
+		length += utf16.RuneLen(r)
+	}
+	return length
+}
+
+// messageKind represents update field that contains message
+type messageKind int
+
+const (
+	messageKindMessage messageKind = iota
+	messageKindEditedMessage
+	messageKindChannelPost
+	messageKindEditedChannelPost
+	messageKindBusinessMessage
+	messageKindEditedBusinessMessage
+	messageKindGuestMessage
+)
+
+// MessageBuilder builds message and update with it
+type MessageBuilder struct {
+	message  telego.Message
+	kind     messageKind
+	chatSet  bool
+	entities []telego.MessageEntity
+	captions []telego.MessageEntity
+}
+
+// NewMessage creates new message builder, by default message is sent by [DefaultUser] in private chat with the user
+func NewMessage() *MessageBuilder {
+	user := DefaultUser
+	return &MessageBuilder{
+		message: telego.Message{
+			MessageID: int(lastMessageID.Add(1)),
+			From:      &user,
+			Date:      time.Now().Unix(),
+			Chat:      privateChat(DefaultUser),
+		},
+	}
+}
+
+// privateChat returns private chat with user
+func privateChat(user telego.User) telego.Chat {
+	return telego.Chat{
+		ID:        user.ID,
+		Type:      telego.ChatTypePrivate,
+		Username:  user.Username,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+	}
+}
+
+// ID sets message ID
+func (b *MessageBuilder) ID(messageID int) *MessageBuilder {
+	b.message.MessageID = messageID
+	return b
+}
+
+// From sets sender of the message, if chat wasn't set, private chat with the user is used
+func (b *MessageBuilder) From(user telego.User) *MessageBuilder {
+	b.message.From = &user
+	if !b.chatSet {
+		b.message.Chat = privateChat(user)
+	}
+	return b
+}
+
+// InChat sets chat of the message
+func (b *MessageBuilder) InChat(chat telego.Chat) *MessageBuilder {
+	b.message.Chat = chat
+	b.chatSet = true
+	return b
+}
+
+// Date sets date of the message
+func (b *MessageBuilder) Date(date time.Time) *MessageBuilder {
+	b.message.Date = date.Unix()
+	return b
+}
+
+// Text sets text of the message, bot_command entities are generated for commands in the text
+func (b *MessageBuilder) Text(text string) *MessageBuilder {
+	b.message.Text = text
+	b.message.Entities = append(botCommandEntities(text), b.entities...)
+	return b
+}
+
+// Entities adds entities of the message text
+func (b *MessageBuilder) Entities(entities ...telego.MessageEntity) *MessageBuilder {
+	b.entities = append(b.entities, entities...)
+	b.message.Entities = append(botCommandEntities(b.message.Text), b.entities...)
+	return b
+}
+
+// Caption sets caption of the message, bot_command entities are generated for commands in the caption
+func (b *MessageBuilder) Caption(caption string) *MessageBuilder {
+	b.message.Caption = caption
+	b.message.CaptionEntities = append(botCommandEntities(caption), b.captions...)
+	return b
+}
+
+// CaptionEntities adds entities of the message caption
+func (b *MessageBuilder) CaptionEntities(entities ...telego.MessageEntity) *MessageBuilder {
+	b.captions = append(b.captions, entities...)
+	b.message.CaptionEntities = append(botCommandEntities(b.message.Caption), b.captions...)
+	return b
+}
+
+// Photo sets photo of the message
+func (b *MessageBuilder) Photo(photo ...telego.PhotoSize) *MessageBuilder {
+	b.message.Photo = photo
+	return b
+}
+
+// Document sets document of the message
+func (b *MessageBuilder) Document(document telego.Document) *MessageBuilder {
+	b.message.Document = &document
+	return b
+}
+
+// ReplyTo sets message that this message replies to
+func (b *MessageBuilder) ReplyTo(message telego.Message) *MessageBuilder {
+	b.message.ReplyToMessage = &message
+	return b
+}
+
+// AsEdited puts message into edited_message (or edited_channel_post, edited_business_message) field of update and
+// sets edit date
+func (b *MessageBuilder) AsEdited() *MessageBuilder {
+	switch b.kind {
+	case messageKindChannelPost:
+		b.kind = messageKindEditedChannelPost
+	case messageKindBusinessMessage:
+		b.kind = messageKindEditedBusinessMessage
+	case messageKindMessage:
+		b.kind = messageKindEditedMessage
+	default:
+		// Already edited or can't be edited
+	}
+	b.message.EditDate = time.Now().Unix()
+	return b
+}
+
+// AsChannelPost puts message into channel_post field of update, if chat wasn't set, channel chat is used
+func (b *MessageBuilder) AsChannelPost() *MessageBuilder {
+	b.kind = messageKindChannelPost
+	if !b.chatSet {
+		b.InChat(telego.Chat{ID: -1002, Type: telego.ChatTypeChannel, Title: "Channel"})
+	}
+	b.message.SenderChat = &b.message.Chat
+	b.message.From = nil
+	return b
+}
+
+// AsBusiness puts message into business_message field of update and sets business connection ID
+func (b *MessageBuilder) AsBusiness(connectionID string) *MessageBuilder {
+	b.kind = messageKindBusinessMessage
+	b.message.BusinessConnectionID = connectionID
+	return b
+}
+
+// AsGuest puts message into guest_message field of update and sets guest query ID
+func (b *MessageBuilder) AsGuest(queryID string) *MessageBuilder {
+	b.kind = messageKindGuestMessage
+	b.message.GuestQueryID = queryID
+	return b
+}
+
+// Build returns message
+func (b *MessageBuilder) Build() telego.Message {
+	message := b.message
+	if message.SenderChat != nil {
+		chat := message.Chat
+		message.SenderChat = &chat
+	}
+	return message
+}
+
+// Update builds update with message
+func (b *MessageBuilder) Update() telego.Update {
+	message := b.Build()
+	update := telego.Update{UpdateID: nextUpdateID()}
+
+	switch b.kind {
+	case messageKindEditedMessage:
+		update.EditedMessage = &message
+	case messageKindChannelPost:
+		update.ChannelPost = &message
+	case messageKindEditedChannelPost:
+		update.EditedChannelPost = &message
+	case messageKindBusinessMessage:
+		update.BusinessMessage = &message
+	case messageKindEditedBusinessMessage:
+		update.EditedBusinessMessage = &message
+	case messageKindGuestMessage:
+		update.GuestMessage = &message
+	default:
+		update.Message = &message
+	}
+
+	return update
+}
+
+// CallbackQueryBuilder builds callback query and update with it
+type CallbackQueryBuilder struct {
+	query telego.CallbackQuery
+}
+
+// NewCallbackQuery creates new callback query builder, by default query is sent by [DefaultUser] without message
+func NewCallbackQuery() *CallbackQueryBuilder {
+	return &CallbackQueryBuilder{
+		query: telego.CallbackQuery{
+			ID:           strconv.FormatInt(lastQueryID.Add(1), 10),
+			From:         DefaultUser,
+			ChatInstance: "1",
+		},
+	}
+}
+
+// ID sets query ID
+func (b *CallbackQueryBuilder) ID(queryID string) *CallbackQueryBuilder {
+	b.query.ID = queryID
+	return b
+}
+
+// From sets sender of the query
+func (b *CallbackQueryBuilder) From(user telego.User) *CallbackQueryBuilder {
+	b.query.From = user
+	return b
+}
+
+// Data sets callback data
+func (b *CallbackQueryBuilder) Data(data string) *CallbackQueryBuilder {
+	b.query.Data = data
+	return b
+}
+
+// Message sets accessible message with the callback button
+func (b *CallbackQueryBuilder) Message(message telego.Message) *CallbackQueryBuilder {
+	b.query.Message = &message
+	b.query.InlineMessageID = ""
+	return b
+}
+
+// InaccessibleMessage sets message with the callback button that is no longer accessible for bot
+func (b *CallbackQueryBuilder) InaccessibleMessage(chat telego.Chat, messageID int) *CallbackQueryBuilder {
+	b.query.Message = &telego.InaccessibleMessage{Chat: chat, MessageID: messageID}
+	b.query.InlineMessageID = ""
+	return b
+}
+
+// InlineMessageID sets ID of inline message with the callback button
+func (b *CallbackQueryBuilder) InlineMessageID(inlineMessageID string) *CallbackQueryBuilder {
+	b.query.InlineMessageID = inlineMessageID
+	b.query.Message = nil
+	return b
+}
+
+// GameShortName sets short name of the game
+func (b *CallbackQueryBuilder) GameShortName(name string) *CallbackQueryBuilder {
+	b.query.GameShortName = name
+	return b
+}
+
+// Build returns callback query
+func (b *CallbackQueryBuilder) Build() telego.CallbackQuery {
+	return b.query
+}
+
+// Update builds update with callback query
+func (b *CallbackQueryBuilder) Update() telego.Update {
+	query := b.Build()
+	return telego.Update{UpdateID: nextUpdateID(), CallbackQuery: &query}
+}
+
+// InlineQueryBuilder builds inline query and update with it
+type InlineQueryBuilder struct {
+	query telego.InlineQuery
+}
+
+// NewInlineQuery creates new inline query builder, by default query is sent by [DefaultUser] from private chat
+func NewInlineQuery() *InlineQueryBuilder {
+	return &InlineQueryBuilder{
+		query: telego.InlineQuery{
+			ID:       strconv.FormatInt(lastQueryID.Add(1), 10),
+			From:     DefaultUser,
+			ChatType: telego.ChatTypeSender,
+		},
+	}
+}
+
+// ID sets query ID
+func (b *InlineQueryBuilder) ID(queryID string) *InlineQueryBuilder {
+	b.query.ID = queryID
+	return b
+}
+
+// From sets sender of the query
+func (b *InlineQueryBuilder) From(user telego.User) *InlineQueryBuilder {
+	b.query.From = user
+	return b
+}
+
+// Query sets text of the query
+func (b *InlineQueryBuilder) Query(query string) *InlineQueryBuilder {
+	b.query.Query = query
+	return b
+}
+
+// Offset sets offset of the results to be returned
+func (b *InlineQueryBuilder) Offset(offset string) *InlineQueryBuilder {
+	b.query.Offset = offset
+	return b
+}
+
+// ChatType sets type of the chat from which the query was sent
+func (b *InlineQueryBuilder) ChatType(chatType string) *InlineQueryBuilder {
+	b.query.ChatType = chatType
+	return b
+}
+
+// Location sets location of the sender
+func (b *InlineQueryBuilder) Location(location telego.Location) *InlineQueryBuilder {
+	b.query.Location = &location
+	return b
+}
+
+// Build returns inline query
+func (b *InlineQueryBuilder) Build() telego.InlineQuery {
+	return b.query
+}
+
+// Update builds update with inline query
+func (b *InlineQueryBuilder) Update() telego.Update {
+	query := b.Build()
+	return telego.Update{UpdateID: nextUpdateID(), InlineQuery: &query}
+}
+
+// ChatMemberBuilder builds chat member update, by default [DefaultUser] joins [DefaultGroup]
+type ChatMemberBuilder struct {
+	update    telego.ChatMemberUpdated
+	member    telego.User
+	oldStatus string
+	newStatus string
+	fromSet   bool
+	forBot    bool
+}
+
+// NewChatMemberUpdate creates new chat member update builder
+func NewChatMemberUpdate() *ChatMemberBuilder {
+	return &ChatMemberBuilder{
+		update: telego.ChatMemberUpdated{
+			Chat: DefaultGroup,
+			From: DefaultUser,
+			Date: time.Now().Unix(),
+		},
+		member:    DefaultUser,
+		oldStatus: telego.MemberStatusLeft,
+		newStatus: telego.MemberStatusMember,
+	}
+}
+
+// InChat sets chat where member status changed
+func (b *ChatMemberBuilder) InChat(chat telego.Chat) *ChatMemberBuilder {
+	b.update.Chat = chat
+	return b
+}
+
+// From sets user who changed member status, by default it's the member itself
+func (b *ChatMemberBuilder) From(user telego.User) *ChatMemberBuilder {
+	b.update.From = user
+	b.fromSet = true
+	return b
+}
+
+// Member sets user whose status changed
+func (b *ChatMemberBuilder) Member(user telego.User) *ChatMemberBuilder {
+	b.member = user
+	if !b.fromSet {
+		b.update.From = user
+	}
+	return b
+}
+
+// ForBot makes update describe status change of the bot itself ([DefaultBotUser]), such update is put into
+// my_chat_member field of update
+func (b *ChatMemberBuilder) ForBot() *ChatMemberBuilder {
+	b.forBot = true
+	b.member = DefaultBotUser
+	return b
+}
+
+// Transition sets old and new member statuses (like [telego.MemberStatusMember]), unknown statuses are treated as
+// member status
+func (b *ChatMemberBuilder) Transition(oldStatus, newStatus string) *ChatMemberBuilder {
+	b.oldStatus = oldStatus
+	b.newStatus = newStatus
+	return b
+}
+
+// Joined sets transition from left to member
+func (b *ChatMemberBuilder) Joined() *ChatMemberBuilder {
+	return b.Transition(telego.MemberStatusLeft, telego.MemberStatusMember)
+}
+
+// Left sets transition from member to left
+func (b *ChatMemberBuilder) Left() *ChatMemberBuilder {
+	return b.Transition(telego.MemberStatusMember, telego.MemberStatusLeft)
+}
+
+// Banned sets transition from member to banned
+func (b *ChatMemberBuilder) Banned() *ChatMemberBuilder {
+	return b.Transition(telego.MemberStatusMember, telego.MemberStatusBanned)
+}
+
+// Promoted sets transition from member to administrator
+func (b *ChatMemberBuilder) Promoted() *ChatMemberBuilder {
+	return b.Transition(telego.MemberStatusMember, telego.MemberStatusAdministrator)
+}
+
+// ViaJoinRequest marks that user joined the chat after sending a join request
+func (b *ChatMemberBuilder) ViaJoinRequest() *ChatMemberBuilder {
+	b.update.ViaJoinRequest = true
+	return b
+}
+
+// Build returns chat member update
+func (b *ChatMemberBuilder) Build() telego.ChatMemberUpdated {
+	update := b.update
+	update.OldChatMember = chatMember(b.oldStatus, b.member)
+	update.NewChatMember = chatMember(b.newStatus, b.member)
+	return update
+}
+
+// Update builds update with chat member update, see [ChatMemberBuilder.ForBot]
+func (b *ChatMemberBuilder) Update() telego.Update {
+	chatMemberUpdated := b.Build()
+	update := telego.Update{UpdateID: nextUpdateID()}
+	if b.forBot {
+		update.MyChatMember = &chatMemberUpdated
+	} else {
+		update.ChatMember = &chatMemberUpdated
+	}
+	return update
+}
+
+// chatMember returns chat member with status
+func chatMember(status string, user telego.User) telego.ChatMember {
+	switch status {
+	case telego.MemberStatusCreator:
+		return &telego.ChatMemberOwner{Status: status, User: user}
+	case telego.MemberStatusAdministrator:
+		return &telego.ChatMemberAdministrator{Status: status, User: user}
+	case telego.MemberStatusRestricted:
+		return &telego.ChatMemberRestricted{Status: status, User: user, IsMember: true}
+	case telego.MemberStatusLeft:
+		return &telego.ChatMemberLeft{Status: status, User: user}
+	case telego.MemberStatusBanned:
+		return &telego.ChatMemberBanned{Status: status, User: user}
+	default:
+		return &telego.ChatMemberMember{Status: telego.MemberStatusMember, User: user}
+	}
+}
+
+// BusinessConnectionBuilder builds business connection and update with it
+type BusinessConnectionBuilder struct {
+	connection telego.BusinessConnection
+}
+
+// NewBusinessConnection creates new business connection builder, by default [DefaultUser] enabled connection
+func NewBusinessConnection() *BusinessConnectionBuilder {
+	return &BusinessConnectionBuilder{
+		connection: telego.BusinessConnection{
+			ID:         "business_connection",
+			User:       DefaultUser,
+			UserChatID: DefaultUser.ID,
+			Date:       time.Now().Unix(),
+			IsEnabled:  true,
+		},
+	}
+}
+
+// ID sets connection ID
+func (b *BusinessConnectionBuilder) ID(connectionID string) *BusinessConnectionBuilder {
+	b.connection.ID = connectionID
+	return b
+}
+
+// User sets business account user and private chat with the user
+func (b *BusinessConnectionBuilder) User(user telego.User) *BusinessConnectionBuilder {
+	b.connection.User = user
+	b.connection.UserChatID = user.ID
+	return b
+}
+
+// Rights sets rights of the business bot
+func (b *BusinessConnectionBuilder) Rights(rights telego.BusinessBotRights) *BusinessConnectionBuilder {
+	b.connection.Rights = &rights
+	return b
+}
+
+// Disabled marks connection as disabled
+func (b *BusinessConnectionBuilder) Disabled() *BusinessConnectionBuilder {
+	b.connection.IsEnabled = false
+	return b
+}
+
+// Build returns business connection
+func (b *BusinessConnectionBuilder) Build() telego.BusinessConnection {
+	return b.connection
+}
+
+// Update builds update with business connection
+func (b *BusinessConnectionBuilder) Update() telego.Update {
+	connection := b.Build()
+	return telego.Update{UpdateID: nextUpdateID(), BusinessConnection: &connection}
+}
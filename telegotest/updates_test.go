@@ -0,0 +1,202 @@
+package telegotest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mymmrac/telego"
+	"github.com/mymmrac/telego/internal/json"
+	th "github.com/mymmrac/telego/telegohandler"
+)
+
+func requireValidUpdate(t *testing.T, update telego.Update) {
+	t.Helper()
+
+	data, err := json.Marshal(update)
+	require.NoError(t, err)
+
+	var decoded telego.Update
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, update, decoded)
+}
+
+func TestNewMessage(t *testing.T) {
+	user := telego.User{ID: 2, FirstName: "Other"}
+
+	t.Run("command", func(t *testing.T) {
+		update := NewMessage().From(user).Text("/start abc").Update()
+		requireValidUpdate(t, update)
+
+		require.NotNil(t, update.Message)
+		assert.Equal(t, int64(2), update.Message.Chat.ID)
+		assert.Equal(t, telego.ChatTypePrivate, update.Message.Chat.Type)
+		assert.Equal(t, []telego.MessageEntity{{Type: telego.EntityTypeBotCommand, Length: 6}}, update.Message.Entities)
+
+		assert.True(t, th.CommandEqualArgv("start", "abc")(t.Context(), update))
+	})
+
+	t.Run("utf16", func(t *testing.T) {
+		bold := telego.MessageEntity{Type: telego.EntityTypeBold, Offset: 0, Length: 2}
+		message := NewMessage().InChat(DefaultGroup).From(user).Entities(bold).
+			Text("🙂 run /cmd@bot, /help").Build()
+		assert.Equal(t, DefaultGroup, message.Chat)
+		assert.Equal(t, []telego.MessageEntity{
+			{Type: telego.EntityTypeBotCommand, Offset: 7, Length: 8},
+			{Type: telego.EntityTypeBotCommand, Offset: 17, Length: 5},
+			bold,
+		}, message.Entities)
+
+		message = NewMessage().Text("a/b not/command").Build()
+		assert.Empty(t, message.Entities)
+	})
+
+	t.Run("caption", func(t *testing.T) {
+		bold := telego.MessageEntity{Type: telego.EntityTypeBold, Length: 1}
+		date := time.Unix(100, 0)
+		reply := NewMessage().Text("reply").Build()
+		message := NewMessage().ID(7).Date(date).CaptionEntities(bold).Caption("/photo").
+			Photo(telego.PhotoSize{FileID: "photo"}).Document(telego.Document{FileID: "doc"}).ReplyTo(reply).Build()
+
+		assert.Equal(t, 7, message.MessageID)
+		assert.Equal(t, int64(100), message.Date)
+		assert.Len(t, message.CaptionEntities, 2)
+		assert.Equal(t, "photo", message.Photo[0].FileID)
+		assert.Equal(t, "doc", message.Document.FileID)
+		assert.Equal(t, &reply, message.ReplyToMessage)
+	})
+
+	t.Run("kinds", func(t *testing.T) {
+		update := NewMessage().AsEdited().Update()
+		requireValidUpdate(t, update)
+		require.NotNil(t, update.EditedMessage)
+		assert.NotZero(t, update.EditedMessage.EditDate)
+
+		update = NewMessage().AsChannelPost().Text("post").Update()
+		requireValidUpdate(t, update)
+		require.NotNil(t, update.ChannelPost)
+		assert.Equal(t, telego.ChatTypeChannel, update.ChannelPost.Chat.Type)
+		assert.Nil(t, update.ChannelPost.From)
+		assert.Equal(t, update.ChannelPost.Chat, *update.ChannelPost.SenderChat)
+
+		update = NewMessage().AsChannelPost().AsEdited().Update()
+		require.NotNil(t, update.EditedChannelPost)
+
+		update = NewMessage().AsBusiness("connection").Update()
+		requireValidUpdate(t, update)
+		require.NotNil(t, update.BusinessMessage)
+		assert.Equal(t, "connection", update.BusinessMessage.BusinessConnectionID)
+
+		update = NewMessage().AsBusiness("connection").AsEdited().Update()
+		require.NotNil(t, update.EditedBusinessMessage)
+
+		update = NewMessage().AsGuest("query").AsEdited().Update()
+		requireValidUpdate(t, update)
+		require.NotNil(t, update.GuestMessage)
+		assert.Equal(t, "query", update.GuestMessage.GuestQueryID)
+	})
+
+	t.Run("default_user_copy", func(t *testing.T) {
+		message := NewMessage().Build()
+		require.NotNil(t, message.From)
+		message.From.FirstName = "Changed"
+
+		assert.Equal(t, "User", DefaultUser.FirstName)
+	})
+}
+
+func TestNewCallbackQuery(t *testing.T) {
+	message := NewMessage().Text("text").Build()
+
+	update := NewCallbackQuery().ID("id").From(DefaultUser).Data("data").Message(message).Update()
+	requireValidUpdate(t, update)
+	require.NotNil(t, update.CallbackQuery)
+	assert.Equal(t, "id", update.CallbackQuery.ID)
+	assert.Equal(t, &message, update.CallbackQuery.Message.Message())
+
+	update = NewCallbackQuery().InaccessibleMessage(DefaultGroup, 1).Update()
+	requireValidUpdate(t, update)
+	assert.False(t, update.CallbackQuery.Message.IsAccessible())
+
+	query := NewCallbackQuery().Message(message).InlineMessageID("inline").GameShortName("game").Build()
+	assert.Nil(t, query.Message)
+	assert.Equal(t, "inline", query.InlineMessageID)
+	assert.Equal(t, "game", query.GameShortName)
+
+	assert.NotEqual(t, NewCallbackQuery().Build().ID, NewCallbackQuery().Build().ID)
+}
+
+func TestNewInlineQuery(t *testing.T) {
+	update := NewInlineQuery().ID("id").From(DefaultUser).Query("query").Offset("10").
+		ChatType(telego.ChatTypeGroup).Location(telego.Location{Latitude: 1}).Update()
+	requireValidUpdate(t, update)
+	require.NotNil(t, update.InlineQuery)
+	assert.Equal(t, "query", update.InlineQuery.Query)
+	assert.Equal(t, "10", update.InlineQuery.Offset)
+	assert.Equal(t, telego.ChatTypeGroup, update.InlineQuery.ChatType)
+}
+
+func TestNewChatMemberUpdate(t *testing.T) {
+	admin := telego.User{ID: 2, FirstName: "Admin"}
+	member := telego.User{ID: 3, FirstName: "Member"}
+
+	update := NewChatMemberUpdate().Update()
+	requireValidUpdate(t, update)
+	require.NotNil(t, update.ChatMember)
+	assert.Equal(t, telego.MemberStatusLeft, update.ChatMember.OldChatMember.MemberStatus())
+	assert.Equal(t, telego.MemberStatusMember, update.ChatMember.NewChatMember.MemberStatus())
+
+	tests := []struct {
+		builder  *ChatMemberBuilder
+		old, new string
+	}{
+		{NewChatMemberUpdate().Joined(), telego.MemberStatusLeft, telego.MemberStatusMember},
+		{NewChatMemberUpdate().Left(), telego.MemberStatusMember, telego.MemberStatusLeft},
+		{NewChatMemberUpdate().Banned(), telego.MemberStatusMember, telego.MemberStatusBanned},
+		{NewChatMemberUpdate().Promoted(), telego.MemberStatusMember, telego.MemberStatusAdministrator},
+		{
+			NewChatMemberUpdate().Transition(telego.MemberStatusRestricted, telego.MemberStatusCreator),
+			telego.MemberStatusRestricted, telego.MemberStatusCreator,
+		},
+		{NewChatMemberUpdate().Transition("unknown", "unknown"), telego.MemberStatusMember, telego.MemberStatusMember},
+	}
+	for _, tt := range tests {
+		update = tt.builder.From(admin).Member(member).Update()
+		requireValidUpdate(t, update)
+		assert.Equal(t, tt.old, update.ChatMember.OldChatMember.MemberStatus())
+		assert.Equal(t, tt.new, update.ChatMember.NewChatMember.MemberStatus())
+		assert.Equal(t, admin, update.ChatMember.From)
+		assert.Equal(t, member, update.ChatMember.NewChatMember.MemberUser())
+	}
+
+	update = NewChatMemberUpdate().InChat(telego.Chat{ID: -1, Type: telego.ChatTypeGroup}).ForBot().ViaJoinRequest().
+		Update()
+	requireValidUpdate(t, update)
+	require.NotNil(t, update.MyChatMember)
+	assert.Equal(t, DefaultBotUser, update.MyChatMember.NewChatMember.MemberUser())
+	assert.True(t, update.MyChatMember.ViaJoinRequest)
+}
+
+func TestNewBusinessConnection(t *testing.T) {
+	user := telego.User{ID: 2, FirstName: "Business"}
+
+	update := NewBusinessConnection().ID("id").User(user).Rights(telego.BusinessBotRights{CanReply: true}).
+		Disabled().Update()
+	requireValidUpdate(t, update)
+	require.NotNil(t, update.BusinessConnection)
+	assert.Equal(t, int64(2), update.BusinessConnection.UserChatID)
+	assert.False(t, update.BusinessConnection.IsEnabled)
+	assert.True(t, update.BusinessConnection.Rights.CanReply)
+}
+
+func TestUpdateSequence(t *testing.T) {
+	first := NewMessage().Update()
+	second := NewCallbackQuery().Update()
+	assert.Greater(t, second.UpdateID, first.UpdateID)
+
+	sequence := NewUpdateSequence()
+	assert.Equal(t, 1, sequence.Next(NewMessage()).UpdateID)
+	assert.Equal(t, 2, sequence.Next(NewInlineQuery()).UpdateID)
+}
@@ -36,10 +36,22 @@ func NameReader(reader io.Reader, name string) ta.NamedReader {
 	}
 }
 
-// NameBytes "names" slice of bytes and returns valid [ta.NamedReader]
+// namedBytesImpl represents [ta.NamedReader] of slice of bytes that knows size of unread data
+type namedBytesImpl struct {
+	*bytes.Reader
+	name string
+}
+
+// Name returns name of bytes
+func (r namedBytesImpl) Name() string {
+	return r.name
+}
+
+// NameBytes "names" slice of bytes and returns valid [ta.NamedReader], returned reader also implements Len and
+// [io.Seeker] methods
 func NameBytes(data []byte, name string) ta.NamedReader {
-	return namedReaderImpl{
-		reader: bytes.NewReader(data),
+	return namedBytesImpl{
+		Reader: bytes.NewReader(data),
 		name:   name,
 	}
 }
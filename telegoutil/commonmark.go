@@ -0,0 +1,401 @@
+package telegoutil
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mymmrac/telego"
+)
+
+// List item markers used when CommonMark lists are converted to plain text
+const (
+	commonMarkBullet        = "•"
+	commonMarkNestedBullet  = "◦"
+	commonMarkChecked       = "☑"
+	commonMarkUnchecked     = "☐"
+	commonMarkThematicBreak = "———"
+)
+
+// ParseCommonMark converts standard Markdown (CommonMark with GFM tables, task lists and strikethrough), like the one
+// produced by LLMs, into plain text and entities without any escaping required. Formatting that Telegram doesn't
+// support degrades gracefully: headings become bold, list items are prefixed with bullets or numbers, tables are
+// aligned inside pre entity and images become links. Malformed Markdown is never an error, it's kept as text.
+//
+// Note: Raw HTML is not interpreted and is kept as text
+func ParseCommonMark(markdown string) (string, []telego.MessageEntity) {
+	parser := newCommonMarkParser()
+	blocks := parser.parseBlocks(commonMarkLines(markdown))
+
+	writer := &commonMarkWriter{parser: parser}
+	writer.writeBlocks(blocks, false)
+
+	return writer.builder.result()
+}
+
+// RichMessageCommonMark converts standard Markdown (CommonMark with GFM tables, task lists and strikethrough) into
+// rich message blocks that can be sent using [telego.Bot.SendRichMessage], headings, lists, tables, quotes, code
+// blocks and thematic breaks are converted into corresponding blocks
+func RichMessageCommonMark(markdown string) telego.InputRichMessage {
+	parser := newCommonMarkParser()
+	blocks := parser.parseBlocks(commonMarkLines(markdown))
+	return RichMessage(parser.richBlocks(blocks)...)
+}
+
+// newCommonMarkParser creates new CommonMark parser
+func newCommonMarkParser() *commonMarkParser {
+	return &commonMarkParser{
+		references: make(map[string]string),
+	}
+}
+
+// commonMarkLines splits Markdown into lines with normalized line endings and leading tabs expanded to spaces
+func commonMarkLines(markdown string) []string {
+	markdown = strings.ReplaceAll(markdown, "\r\n", "\n")
+	lines := strings.Split(strings.ReplaceAll(markdown, "\r", "\n"), "\n")
+
+	for i, line := range lines {
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if strings.Contains(line[:indent], "\t") {
+			lines[i] = strings.ReplaceAll(line[:indent], "\t", "    ") + line[indent:]
+		}
+	}
+
+	return lines
+}
+
+// commonMarkWriter writes CommonMark blocks as plain text and entities
+type commonMarkWriter struct {
+	parser  *commonMarkParser
+	builder entityBuilder
+	// prefix is written after each new line, used to indent content of list items
+	prefix string
+	depth  int
+}
+
+// lineBreak starts new line
+func (w *commonMarkWriter) lineBreak() {
+	w.builder.writeString("\n" + w.prefix)
+}
+
+// writeBlocks writes blocks separated by new lines in tight lists or empty lines otherwise
+func (w *commonMarkWriter) writeBlocks(blocks []*commonMarkBlock, tight bool) {
+	for i, block := range blocks {
+		if i > 0 {
+			if !tight {
+				w.builder.writeString("\n")
+			}
+			w.lineBreak()
+		}
+		w.writeBlock(block)
+	}
+}
+
+// writeBlock writes block
+func (w *commonMarkWriter) writeBlock(block *commonMarkBlock) {
+	offset := w.builder.offset
+
+	switch block.kind {
+	case commonMarkParagraph:
+		w.writeInlines(w.parser.parseInline(block.text))
+	case commonMarkHeading:
+		w.writeInlines(w.parser.parseInline(block.text))
+		w.builder.add(offset, telego.MessageEntity{Type: telego.EntityTypeBold})
+	case commonMarkCode:
+		w.writeLines(block.text)
+		w.builder.add(offset, telego.MessageEntity{Type: telego.EntityTypePre, Language: block.language})
+	case commonMarkQuote:
+		w.writeBlocks(block.children, false)
+		w.builder.add(offset, telego.MessageEntity{Type: telego.EntityTypeBlockquote})
+	case commonMarkList:
+		w.writeList(block)
+	case commonMarkTable:
+		w.writeLines(w.formatTable(block))
+		w.builder.add(offset, telego.MessageEntity{Type: telego.EntityTypePre})
+	case commonMarkRule:
+		w.builder.writeString(commonMarkThematicBreak)
+	case commonMarkItem:
+		w.writeBlocks(block.children, true)
+	}
+}
+
+// writeList writes list items prefixed with bullets or numbers, content of items is indented
+func (w *commonMarkWriter) writeList(list *commonMarkBlock) {
+	bullet := commonMarkBullet
+	if w.depth > 0 {
+		bullet = commonMarkNestedBullet
+	}
+
+	prefix := w.prefix
+	w.depth++
+	defer func() {
+		w.prefix = prefix
+		w.depth--
+	}()
+
+	for i, item := range list.children {
+		if i > 0 {
+			w.prefix = prefix
+			if list.loose {
+				w.builder.writeString("\n")
+			}
+			w.lineBreak()
+		}
+
+		var marker string
+		switch {
+		case list.ordered:
+			marker = strconv.Itoa(list.start+i) + "."
+			if item.task {
+				marker += " " + taskMarker(item.checked)
+			}
+		case item.task:
+			marker = taskMarker(item.checked)
+		default:
+			marker = bullet
+		}
+
+		w.builder.writeString(marker + " ")
+		w.prefix = prefix + strings.Repeat(" ", utf8.RuneCountInString(marker)+1)
+		w.writeBlocks(item.children, !list.loose)
+	}
+}
+
+// taskMarker returns marker of the task list item
+func taskMarker(checked bool) string {
+	if checked {
+		return commonMarkChecked
+	}
+	return commonMarkUnchecked
+}
+
+// writeLines writes text line by line, so that lines are indented
+func (w *commonMarkWriter) writeLines(text string) {
+	for i, line := range strings.Split(text, "\n") {
+		if i > 0 {
+			w.lineBreak()
+		}
+		w.builder.writeString(line)
+	}
+}
+
+// writeInlines writes inline elements
+func (w *commonMarkWriter) writeInlines(nodes []*commonMarkInline) {
+	for _, node := range nodes {
+		offset := w.builder.offset
+
+		switch node.kind {
+		case commonMarkText:
+			w.builder.writeString(node.text)
+		case commonMarkLineBreak:
+			w.lineBreak()
+		case commonMarkStrong:
+			w.writeInlines(node.children)
+			w.builder.add(offset, telego.MessageEntity{Type: telego.EntityTypeBold})
+		case commonMarkEmphasis:
+			w.writeInlines(node.children)
+			w.builder.add(offset, telego.MessageEntity{Type: telego.EntityTypeItalic})
+		case commonMarkStrikethrough:
+			w.writeInlines(node.children)
+			w.builder.add(offset, telego.MessageEntity{Type: telego.EntityTypeStrikethrough})
+		case commonMarkCodeSpan:
+			w.builder.writeString(node.text)
+			w.builder.add(offset, telego.MessageEntity{Type: telego.EntityTypeCode})
+		case commonMarkLink, commonMarkImage:
+			if len(node.children) == 0 {
+				w.builder.writeString(node.url)
+			} else {
+				w.writeInlines(node.children)
+			}
+
+			if entity, ok := linkEntity(node.url); ok {
+				w.builder.add(offset, entity)
+			}
+		}
+	}
+}
+
+// formatTable formats table as text with aligned columns
+func (w *commonMarkWriter) formatTable(table *commonMarkBlock) string {
+	rows := make([][]string, len(table.rows))
+	widths := make([]int, len(table.align))
+	for i, row := range table.rows {
+		rows[i] = make([]string, len(row))
+		for j, cell := range row {
+			rows[i][j] = plainText(w.parser.parseInline(cell))
+			widths[j] = max(widths[j], utf8.RuneCountInString(rows[i][j]))
+		}
+	}
+
+	lines := make([]string, 0, len(rows)+1)
+	for i, row := range rows {
+		cells := make([]string, len(row))
+		for j, cell := range row {
+			cells[j] = alignCell(cell, widths[j], table.align[j])
+		}
+		lines = append(lines, strings.TrimRight(strings.Join(cells, " | "), " "))
+
+		if i == 0 {
+			separators := make([]string, len(widths))
+			for j, width := range widths {
+				separators[j] = strings.Repeat("-", width)
+			}
+			lines = append(lines, strings.Join(separators, "-+-"))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// alignCell pads cell text to the width according to alignment
+func alignCell(text string, width int, align string) string {
+	padding := width - utf8.RuneCountInString(text)
+	switch align {
+	case telego.CellAlignRight:
+		return strings.Repeat(" ", padding) + text
+	case telego.CellAlignCenter:
+		return strings.Repeat(" ", padding/2) + text + strings.Repeat(" ", padding-padding/2) //nolint:mnd
+	default:
+		return text + strings.Repeat(" ", padding)
+	}
+}
+
+// plainText returns text of inline elements without formatting
+func plainText(nodes []*commonMarkInline) string {
+	text := strings.Builder{}
+	for _, node := range nodes {
+		switch node.kind {
+		case commonMarkText, commonMarkCodeSpan:
+			text.WriteString(node.text)
+		case commonMarkLineBreak:
+			text.WriteString(" ")
+		case commonMarkLink, commonMarkImage:
+			if len(node.children) == 0 {
+				text.WriteString(node.url)
+			} else {
+				text.WriteString(plainText(node.children))
+			}
+		default:
+			text.WriteString(plainText(node.children))
+		}
+	}
+	return text.String()
+}
+
+// richBlocks converts blocks into rich message blocks
+func (p *commonMarkParser) richBlocks(blocks []*commonMarkBlock) []telego.InputRichBlock {
+	richBlocks := make([]telego.InputRichBlock, 0, len(blocks))
+	for _, block := range blocks {
+		switch block.kind {
+		case commonMarkParagraph:
+			richBlocks = append(richBlocks, RichBlockParagraph(p.richText(p.parseInline(block.text))))
+		case commonMarkHeading:
+			richBlocks = append(richBlocks, RichBlockSectionHeading(p.richText(p.parseInline(block.text)), block.level))
+		case commonMarkCode:
+			code := RichBlockPreformatted(RichTextPlain(block.text))
+			code.Language = block.language
+			richBlocks = append(richBlocks, code)
+		case commonMarkQuote:
+			richBlocks = append(richBlocks, RichBlockBlockQuotation(p.richBlocks(block.children)...))
+		case commonMarkList:
+			richBlocks = append(richBlocks, p.richList(block))
+		case commonMarkTable:
+			richBlocks = append(richBlocks, p.richTable(block))
+		case commonMarkRule:
+			richBlocks = append(richBlocks, RichBlockDivider())
+		case commonMarkItem:
+			richBlocks = append(richBlocks, p.richBlocks(block.children)...)
+		}
+	}
+	return richBlocks
+}
+
+// richList converts list into rich list block
+func (p *commonMarkParser) richList(list *commonMarkBlock) *telego.InputRichBlockList {
+	items := make([]telego.InputRichBlockListItem, 0, len(list.children))
+	for i, item := range list.children {
+		richItem := RichBlockListItem(p.richBlocks(item.children)...)
+		richItem.HasCheckbox = item.task
+		richItem.IsChecked = item.checked
+		if list.ordered {
+			richItem.Value = list.start + i
+			richItem.Type = telego.OrderedListDecimal
+		}
+		items = append(items, richItem)
+	}
+	return RichBlockList(items...)
+}
+
+// richTable converts table into rich table block, the first row is a header
+func (p *commonMarkParser) richTable(table *commonMarkBlock) *telego.InputRichBlockTable {
+	cells := make([][]telego.RichBlockTableCell, len(table.rows))
+	for i, row := range table.rows {
+		cells[i] = make([]telego.RichBlockTableCell, len(row))
+		for j, text := range row {
+			cell := RichBlockTableCell(p.richText(p.parseInline(text)))
+			cell.IsHeader = i == 0
+			cell.Align = table.align[j]
+			if cell.Align == "" {
+				cell.Align = telego.CellAlignLeft
+			}
+			cell.Valign = telego.CellValignMiddle
+			cells[i][j] = cell
+		}
+	}
+
+	richTable := RichBlockTableGrid(cells)
+	richTable.IsBordered = true
+	return richTable
+}
+
+// richText converts inline elements into rich text
+func (p *commonMarkParser) richText(nodes []*commonMarkInline) telego.RichText {
+	texts := make([]telego.RichText, 0, len(nodes))
+	plain := strings.Builder{}
+
+	flush := func() {
+		if plain.Len() != 0 {
+			texts = append(texts, RichTextPlain(plain.String()))
+			plain.Reset()
+		}
+	}
+
+	for _, node := range nodes {
+		switch node.kind {
+		case commonMarkText:
+			plain.WriteString(node.text)
+			continue
+		case commonMarkLineBreak:
+			plain.WriteString("\n")
+			continue
+		}
+		flush()
+
+		switch node.kind {
+		case commonMarkStrong:
+			texts = append(texts, RichTextBold(p.richText(node.children)))
+		case commonMarkEmphasis:
+			texts = append(texts, RichTextItalic(p.richText(node.children)))
+		case commonMarkStrikethrough:
+			texts = append(texts, RichTextStrikethrough(p.richText(node.children)))
+		case commonMarkCodeSpan:
+			texts = append(texts, RichTextCode(RichTextPlain(node.text)))
+		case commonMarkLink, commonMarkImage:
+			var text telego.RichText = RichTextPlain(node.url)
+			if len(node.children) != 0 {
+				text = p.richText(node.children)
+			}
+			texts = append(texts, RichTextURL(text, node.url))
+		}
+	}
+	flush()
+
+	switch len(texts) {
+	case 0:
+		return RichTextPlain("")
+	case 1:
+		return texts[0]
+	default:
+		return RichTextList(texts...)
+	}
+}
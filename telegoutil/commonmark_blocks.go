@@ -0,0 +1,518 @@
+package telegoutil
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mymmrac/telego"
+)
+
+// commonMarkBlockKind represents kind of CommonMark block
+type commonMarkBlockKind int
+
+// CommonMark block kinds
+const (
+	commonMarkParagraph commonMarkBlockKind = iota
+	commonMarkHeading
+	commonMarkCode
+	commonMarkQuote
+	commonMarkList
+	commonMarkItem
+	commonMarkTable
+	commonMarkRule
+)
+
+// commonMarkBlock represents parsed CommonMark block
+type commonMarkBlock struct {
+	kind commonMarkBlockKind
+	// text is raw inline text of paragraphs, headings and table cells or content of code blocks
+	text     string
+	level    int
+	language string
+	children []*commonMarkBlock
+
+	ordered bool
+	start   int
+	loose   bool
+
+	task    bool
+	checked bool
+
+	rows  [][]string
+	align []string
+}
+
+// commonMarkParser parses CommonMark blocks and collects link reference definitions
+type commonMarkParser struct {
+	references map[string]string
+}
+
+// Regular expressions used to parse CommonMark blocks
+var (
+	commonMarkHeadingRegexp   = regexp.MustCompile(`^(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	commonMarkFenceRegexp     = regexp.MustCompile("^(`{3,}|~{3,})[ \t]*([^ \t]*)")
+	commonMarkReferenceRegexp = regexp.MustCompile(
+		`^\[((?:[^\]\\]|\\.)+)\]:[ \t]*(?:<([^>]*)>|(\S+))(?:[ \t]+(?:"[^"]*"|'[^']*'|\([^)]*\)))?[ \t]*$`)
+	commonMarkDelimiterRegexp = regexp.MustCompile(`^[ \t]*:?-+:?[ \t]*$`)
+)
+
+// parseBlocks parses lines into blocks
+func (p *commonMarkParser) parseBlocks(lines []string) []*commonMarkBlock {
+	var blocks []*commonMarkBlock
+	var paragraph []string
+
+	flush := func() {
+		if len(paragraph) != 0 {
+			blocks = append(blocks, &commonMarkBlock{
+				kind: commonMarkParagraph,
+				text: strings.TrimRight(strings.Join(paragraph, "\n"), " \t"),
+			})
+			paragraph = nil
+		}
+	}
+
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		indent := indentOf(line)
+
+		if isBlankLine(line) {
+			flush()
+			i++
+			continue
+		}
+
+		if indent >= 4 {
+			if len(paragraph) != 0 {
+				paragraph = append(paragraph, strings.TrimLeft(line, " "))
+				i++
+				continue
+			}
+
+			var code []string
+			for i < len(lines) && (indentOf(lines[i]) >= 4 || isBlankLine(lines[i])) {
+				code = append(code, stripIndent(lines[i], 4))
+				i++
+			}
+			for len(code) != 0 && isBlankLine(code[len(code)-1]) {
+				code = code[:len(code)-1]
+			}
+
+			blocks = append(blocks, &commonMarkBlock{kind: commonMarkCode, text: strings.Join(code, "\n")})
+			continue
+		}
+
+		rest := line[indent:]
+
+		if len(paragraph) != 0 {
+			if level := setextLevel(rest); level != 0 {
+				blocks = append(blocks, &commonMarkBlock{
+					kind:  commonMarkHeading,
+					text:  strings.TrimSpace(strings.Join(paragraph, "\n")),
+					level: level,
+				})
+				paragraph = nil
+				i++
+				continue
+			}
+		}
+
+		if i+1 < len(lines) && strings.Contains(rest, "|") {
+			if table, end, ok := p.parseTable(lines, i); ok {
+				flush()
+				blocks = append(blocks, table)
+				i = end
+				continue
+			}
+		}
+
+		switch {
+		case isThematicBreak(rest):
+			flush()
+			blocks = append(blocks, &commonMarkBlock{kind: commonMarkRule})
+			i++
+		case commonMarkHeadingRegexp.MatchString(rest):
+			flush()
+			match := commonMarkHeadingRegexp.FindStringSubmatch(rest)
+			blocks = append(blocks, &commonMarkBlock{
+				kind:  commonMarkHeading,
+				text:  strings.TrimSpace(match[2]),
+				level: len(match[1]),
+			})
+			i++
+		case isCodeFence(rest):
+			flush()
+			var code *commonMarkBlock
+			code, i = parseFencedCode(lines, i)
+			blocks = append(blocks, code)
+		case strings.HasPrefix(rest, ">"):
+			flush()
+			var quote *commonMarkBlock
+			quote, i = p.parseQuote(lines, i)
+			blocks = append(blocks, quote)
+		case canStartList(rest, len(paragraph) != 0):
+			flush()
+			var list *commonMarkBlock
+			list, i = p.parseList(lines, i)
+			blocks = append(blocks, list)
+		case len(paragraph) == 0 && commonMarkReferenceRegexp.MatchString(rest):
+			match := commonMarkReferenceRegexp.FindStringSubmatch(rest)
+			label := normalizeReferenceLabel(match[1])
+			if _, ok := p.references[label]; !ok {
+				p.references[label] = unescapeCommonMark(match[2] + match[3])
+			}
+			i++
+		default:
+			paragraph = append(paragraph, strings.TrimLeft(line, " "))
+			i++
+		}
+	}
+	flush()
+
+	return blocks
+}
+
+// parseFencedCode parses fenced code block that starts at the line, returns block and index of the next line
+func parseFencedCode(lines []string, i int) (*commonMarkBlock, int) {
+	indent := indentOf(lines[i])
+	match := commonMarkFenceRegexp.FindStringSubmatch(lines[i][indent:])
+	fence := match[1]
+
+	block := &commonMarkBlock{
+		kind:     commonMarkCode,
+		language: unescapeCommonMark(match[2]),
+	}
+
+	var code []string
+	for i++; i < len(lines); i++ {
+		line := lines[i]
+		closing := strings.TrimSpace(line)
+		if indentOf(line) < 4 && strings.HasPrefix(closing, fence) && strings.Trim(closing, fence[:1]) == "" {
+			i++
+			break
+		}
+		code = append(code, stripIndent(line, indent))
+	}
+
+	block.text = strings.Join(code, "\n")
+	return block, i
+}
+
+// parseQuote parses blockquote that starts at the line, returns block and index of the next line
+func (p *commonMarkParser) parseQuote(lines []string, i int) (*commonMarkBlock, int) {
+	var quoted []string
+	for i < len(lines) {
+		line := lines[i]
+		indent := indentOf(line)
+
+		if indent < 4 && strings.HasPrefix(line[indent:], ">") {
+			content := line[indent+1:]
+			content = strings.TrimPrefix(content, " ")
+			quoted = append(quoted, content)
+			i++
+			continue
+		}
+
+		if !isBlankLine(line) && !isBlankLine(quoted[len(quoted)-1]) && !startsCommonMarkBlock(line) {
+			quoted = append(quoted, line)
+			i++
+			continue
+		}
+
+		break
+	}
+
+	return &commonMarkBlock{kind: commonMarkQuote, children: p.parseBlocks(quoted)}, i
+}
+
+// commonMarkListMarker represents marker of list item
+type commonMarkListMarker struct {
+	// delimiter is bullet character for bullet lists or '.' or ')' for ordered lists
+	delimiter byte
+	ordered   bool
+	number    int
+	// content is indentation of item content
+	content int
+	empty   bool
+}
+
+// parseListMarker parses list item marker at the start of the line
+func parseListMarker(line string) (commonMarkListMarker, bool) {
+	indent := indentOf(line)
+	if indent >= 4 || indent == len(line) {
+		return commonMarkListMarker{}, false
+	}
+	rest := line[indent:]
+
+	var marker commonMarkListMarker
+	size := 0
+	switch {
+	case rest[0] == '-' || rest[0] == '+' || rest[0] == '*':
+		marker.delimiter = rest[0]
+		size = 1
+	default:
+		for size < len(rest) && size < 9 && rest[size] >= '0' && rest[size] <= '9' {
+			size++
+		}
+		if size == 0 || size == len(rest) || (rest[size] != '.' && rest[size] != ')') {
+			return commonMarkListMarker{}, false
+		}
+
+		marker.ordered = true
+		marker.number, _ = strconv.Atoi(rest[:size])
+		marker.delimiter = rest[size]
+		size++
+	}
+
+	spaces := 0
+	for size+spaces < len(rest) && rest[size+spaces] == ' ' {
+		spaces++
+	}
+
+	switch {
+	case size+spaces == len(rest):
+		marker.empty = true
+		marker.content = indent + size + 1
+	case spaces == 0:
+		return commonMarkListMarker{}, false
+	case spaces > 4: //nolint:mnd
+		marker.content = indent + size + 1
+	default:
+		marker.content = indent + size + spaces
+	}
+
+	return marker, true
+}
+
+// isListItem reports if the line starts with list item marker
+func isListItem(line string) bool {
+	_, ok := parseListMarker(line)
+	return ok
+}
+
+// canStartList reports if list can start at the line, only non-empty bullet lists or ordered lists starting with 1
+// can interrupt paragraphs
+func canStartList(line string, inParagraph bool) bool {
+	marker, ok := parseListMarker(line)
+	if !ok {
+		return false
+	}
+	return !inParagraph || (!marker.empty && (!marker.ordered || marker.number == 1))
+}
+
+// parseList parses list that starts at the line, returns block and index of the next line
+func (p *commonMarkParser) parseList(lines []string, i int) (*commonMarkBlock, int) {
+	first, _ := parseListMarker(lines[i])
+	list := &commonMarkBlock{
+		kind:    commonMarkList,
+		ordered: first.ordered,
+		start:   first.number,
+	}
+
+	trailingBlank := false
+	for i < len(lines) {
+		if isThematicBreak(strings.TrimLeft(lines[i], " ")) {
+			break
+		}
+
+		marker, ok := parseListMarker(lines[i])
+		if !ok || marker.ordered != first.ordered || marker.delimiter != first.delimiter {
+			break
+		}
+
+		var itemLines []string
+		if !marker.empty {
+			itemLines = append(itemLines, lines[i][marker.content:])
+		}
+
+		for i++; i < len(lines); i++ {
+			line := lines[i]
+			switch {
+			case isBlankLine(line):
+				itemLines = append(itemLines, "")
+				continue
+			case indentOf(line) >= marker.content:
+				itemLines = append(itemLines, line[marker.content:])
+				continue
+			case len(itemLines) != 0 && !isBlankLine(itemLines[len(itemLines)-1]) && !startsCommonMarkBlock(line) &&
+				!isListItem(line):
+				itemLines = append(itemLines, line)
+				continue
+			}
+			break
+		}
+
+		if trailingBlank {
+			list.loose = true
+		}
+		trailingBlank = false
+		for len(itemLines) != 0 && isBlankLine(itemLines[len(itemLines)-1]) {
+			itemLines = itemLines[:len(itemLines)-1]
+			trailingBlank = true
+		}
+
+		item := &commonMarkBlock{kind: commonMarkItem, children: p.parseBlocks(itemLines)}
+		if len(item.children) > 1 && slices.ContainsFunc(itemLines, isBlankLine) {
+			list.loose = true
+		}
+		if len(item.children) != 0 && item.children[0].kind == commonMarkParagraph {
+			paragraph := item.children[0]
+			for _, prefix := range []string{"[ ] ", "[x] ", "[X] "} {
+				if strings.HasPrefix(paragraph.text+" ", prefix) {
+					item.task = true
+					item.checked = prefix != "[ ] "
+					paragraph.text = strings.TrimLeft(strings.TrimPrefix(paragraph.text, prefix[:3]), " ")
+					break
+				}
+			}
+		}
+
+		list.children = append(list.children, item)
+	}
+
+	return list, i
+}
+
+// parseTable parses GFM table that starts at the line, returns block, index of the next line and false if there is
+// no table
+func (p *commonMarkParser) parseTable(lines []string, i int) (*commonMarkBlock, int, bool) {
+	header := splitTableRow(lines[i])
+	delimiters := splitTableRow(lines[i+1])
+	if len(header) != len(delimiters) {
+		return nil, 0, false
+	}
+
+	table := &commonMarkBlock{
+		kind:  commonMarkTable,
+		rows:  [][]string{header},
+		align: make([]string, len(delimiters)),
+	}
+
+	for column, delimiter := range delimiters {
+		if !commonMarkDelimiterRegexp.MatchString(delimiter) {
+			return nil, 0, false
+		}
+
+		switch {
+		case strings.HasPrefix(delimiter, ":") && strings.HasSuffix(delimiter, ":"):
+			table.align[column] = telego.CellAlignCenter
+		case strings.HasSuffix(delimiter, ":"):
+			table.align[column] = telego.CellAlignRight
+		case strings.HasPrefix(delimiter, ":"):
+			table.align[column] = telego.CellAlignLeft
+		}
+	}
+
+	for i += 2; i < len(lines) && !isBlankLine(lines[i]) && !startsCommonMarkBlock(lines[i]); i++ {
+		row := splitTableRow(lines[i])
+		if len(row) > len(header) {
+			row = row[:len(header)]
+		}
+		for len(row) < len(header) {
+			row = append(row, "")
+		}
+		table.rows = append(table.rows, row)
+	}
+
+	return table, i, true
+}
+
+// splitTableRow splits GFM table row into trimmed cells, escaped pipes are unescaped
+func splitTableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+
+	var cells []string
+	cell := strings.Builder{}
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case line[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(line[i])
+		}
+	}
+
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// startsCommonMarkBlock reports if the line starts a block that interrupts paragraphs
+func startsCommonMarkBlock(line string) bool {
+	indent := indentOf(line)
+	if indent >= 4 {
+		return false
+	}
+	rest := line[indent:]
+
+	return isThematicBreak(rest) || commonMarkHeadingRegexp.MatchString(rest) ||
+		isCodeFence(rest) || strings.HasPrefix(rest, ">") || canStartList(rest, true)
+}
+
+// isCodeFence reports if the line is an opening code fence, info string of backtick fences can't contain backticks
+func isCodeFence(line string) bool {
+	match := commonMarkFenceRegexp.FindStringSubmatch(line)
+	if match == nil {
+		return false
+	}
+	return match[1][0] != '`' || !strings.Contains(line[len(match[1]):], "`")
+}
+
+// isThematicBreak reports if the line is a thematic break
+func isThematicBreak(line string) bool {
+	count := 0
+	var char byte
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == ' ' || c == '\t':
+		case (c == '-' || c == '*' || c == '_') && (char == 0 || char == c):
+			char = c
+			count++
+		default:
+			return false
+		}
+	}
+	return count >= 3 //nolint:mnd
+}
+
+// setextLevel returns level of setext heading if the line is its underline, zero otherwise
+func setextLevel(line string) int {
+	line = strings.TrimRight(line, " \t")
+	switch {
+	case line == "":
+		return 0
+	case strings.Trim(line, "=") == "":
+		return 1
+	case strings.Trim(line, "-") == "":
+		return 2 //nolint:mnd
+	default:
+		return 0
+	}
+}
+
+// indentOf returns number of leading spaces
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// stripIndent removes up to provided number of leading spaces
+func stripIndent(line string, indent int) string {
+	return line[min(indent, indentOf(line)):]
+}
+
+// isBlankLine reports if line contains only whitespace
+func isBlankLine(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+// normalizeReferenceLabel normalizes link reference label for case-insensitive matching
+func normalizeReferenceLabel(label string) string {
+	return strings.ToLower(strings.Join(strings.Fields(label), " "))
+}
@@ -0,0 +1,455 @@
+package telegoutil
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// commonMarkInlineKind represents kind of CommonMark inline element
+type commonMarkInlineKind int
+
+// CommonMark inline kinds
+const (
+	commonMarkText commonMarkInlineKind = iota
+	commonMarkLineBreak
+	commonMarkStrong
+	commonMarkEmphasis
+	commonMarkStrikethrough
+	commonMarkCodeSpan
+	commonMarkLink
+	commonMarkImage
+)
+
+// commonMarkInline represents parsed CommonMark inline element
+type commonMarkInline struct {
+	kind     commonMarkInlineKind
+	text     string
+	url      string
+	children []*commonMarkInline
+
+	// delimiter run or bracket data used while parsing
+	delimiter byte
+	count     int
+	original  int
+	canOpen   bool
+	canClose  bool
+	bracket   bool
+	image     bool
+	inactive  bool
+	position  int
+}
+
+// Regular expressions used to parse CommonMark inline elements
+var (
+	commonMarkAutolinkRegexp = regexp.MustCompile(`^<([a-zA-Z][a-zA-Z0-9+.-]{1,31}:[^<>\s]*)>`)
+	commonMarkEmailRegexp    = regexp.MustCompile(
+		`^<([a-zA-Z0-9.!#$%&'*+/=?^_` + "`" + `{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?` +
+			`(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*)>`)
+	commonMarkEntityRegexp = regexp.MustCompile(`^&(?:#[0-9]{1,7}|#[xX][0-9a-fA-F]{1,6}|[a-zA-Z][a-zA-Z0-9]{1,31});`)
+)
+
+// parseInline parses CommonMark inline elements of the text
+func (p *commonMarkParser) parseInline(text string) []*commonMarkInline {
+	var nodes []*commonMarkInline
+	plain := strings.Builder{}
+
+	flush := func() {
+		if plain.Len() != 0 {
+			nodes = append(nodes, &commonMarkInline{kind: commonMarkText, text: plain.String()})
+			plain.Reset()
+		}
+	}
+
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == '\\' && i+1 < len(text) && text[i+1] == '\n':
+			flush()
+			nodes = append(nodes, &commonMarkInline{kind: commonMarkLineBreak})
+			i += 2
+		case c == '\\' && i+1 < len(text) && isASCIIPunctuation(text[i+1]):
+			plain.WriteByte(text[i+1])
+			i += 2
+		case c == '\n':
+			trimmed := strings.TrimRight(plain.String(), " ")
+			plain.Reset()
+			plain.WriteString(trimmed)
+			flush()
+			nodes = append(nodes, &commonMarkInline{kind: commonMarkLineBreak})
+			i++
+			for i < len(text) && text[i] == ' ' {
+				i++
+			}
+		case c == '`':
+			flush()
+			var node *commonMarkInline
+			node, i = parseCodeSpan(text, i)
+			nodes = append(nodes, node)
+		case c == '*' || c == '_' || c == '~':
+			flush()
+			var node *commonMarkInline
+			node, i = parseDelimiterRun(text, i)
+			nodes = append(nodes, node)
+		case c == '[' || (c == '!' && i+1 < len(text) && text[i+1] == '['):
+			flush()
+			node := &commonMarkInline{kind: commonMarkText, text: "[", bracket: true, image: c == '!'}
+			if node.image {
+				node.text = "!["
+			}
+			i += len(node.text)
+			node.position = i
+			nodes = append(nodes, node)
+		case c == ']':
+			flush()
+			nodes, i = p.parseLinkEnd(text, i, nodes)
+		case c == '<' && commonMarkAutolinkRegexp.MatchString(text[i:]):
+			flush()
+			match := commonMarkAutolinkRegexp.FindStringSubmatch(text[i:])
+			nodes = append(nodes, &commonMarkInline{
+				kind:     commonMarkLink,
+				url:      match[1],
+				children: []*commonMarkInline{{kind: commonMarkText, text: match[1]}},
+			})
+			i += len(match[0])
+		case c == '<' && commonMarkEmailRegexp.MatchString(text[i:]):
+			flush()
+			match := commonMarkEmailRegexp.FindStringSubmatch(text[i:])
+			nodes = append(nodes, &commonMarkInline{
+				kind:     commonMarkLink,
+				url:      "mailto:" + match[1],
+				children: []*commonMarkInline{{kind: commonMarkText, text: match[1]}},
+			})
+			i += len(match[0])
+		case c == '&' && commonMarkEntityRegexp.MatchString(text[i:]):
+			entity := commonMarkEntityRegexp.FindString(text[i:])
+			plain.WriteString(html.UnescapeString(entity))
+			i += len(entity)
+		default:
+			plain.WriteByte(c)
+			i++
+		}
+	}
+	flush()
+
+	return processEmphasis(nodes)
+}
+
+// parseCodeSpan parses code span that starts at the position, returns node and position after it, unmatched
+// backticks are returned as text
+func parseCodeSpan(text string, i int) (*commonMarkInline, int) {
+	start := i
+	for i < len(text) && text[i] == '`' {
+		i++
+	}
+	fence := text[start:i]
+
+	for j := i; j < len(text); {
+		end := strings.Index(text[j:], fence)
+		if end == -1 {
+			break
+		}
+		end += j
+
+		closing := end + len(fence)
+		if closing < len(text) && text[closing] == '`' {
+			for closing < len(text) && text[closing] == '`' {
+				closing++
+			}
+			j = closing
+			continue
+		}
+
+		code := strings.ReplaceAll(text[i:end], "\n", " ")
+		if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.Trim(code, " ") != "" {
+			code = code[1 : len(code)-1]
+		}
+		return &commonMarkInline{kind: commonMarkCodeSpan, text: code}, closing
+	}
+
+	return &commonMarkInline{kind: commonMarkText, text: fence}, i
+}
+
+// parseDelimiterRun parses run of '*', '_' or '~' characters, returns node and position after it
+func parseDelimiterRun(text string, i int) (*commonMarkInline, int) {
+	c := text[i]
+	start := i
+	for i < len(text) && text[i] == c {
+		i++
+	}
+
+	before, after := ' ', ' '
+	if start > 0 {
+		before, _ = utf8.DecodeLastRuneInString(text[:start])
+	}
+	if i < len(text) {
+		after, _ = utf8.DecodeRuneInString(text[i:])
+	}
+
+	leftFlanking := !unicode.IsSpace(after) &&
+		(!isUnicodePunctuation(after) || unicode.IsSpace(before) || isUnicodePunctuation(before))
+	rightFlanking := !unicode.IsSpace(before) &&
+		(!isUnicodePunctuation(before) || unicode.IsSpace(after) || isUnicodePunctuation(after))
+
+	node := &commonMarkInline{
+		kind:      commonMarkText,
+		text:      text[start:i],
+		delimiter: c,
+		count:     i - start,
+		original:  i - start,
+		canOpen:   leftFlanking,
+		canClose:  rightFlanking,
+	}
+	if c == '_' {
+		node.canOpen = leftFlanking && (!rightFlanking || isUnicodePunctuation(before))
+		node.canClose = rightFlanking && (!leftFlanking || isUnicodePunctuation(after))
+	}
+	if c == '~' && node.count > 2 {
+		node.delimiter = 0
+	}
+
+	return node, i
+}
+
+// parseLinkEnd parses link or image ending with ']' at the position, returns nodes and position after the link,
+// if there is no link ']' is added as text
+func (p *commonMarkParser) parseLinkEnd(
+	text string, i int, nodes []*commonMarkInline,
+) ([]*commonMarkInline, int) {
+	opener := -1
+	for j := len(nodes) - 1; j >= 0; j-- {
+		if nodes[j].bracket {
+			opener = j
+			break
+		}
+	}
+	if opener == -1 {
+		return append(nodes, &commonMarkInline{kind: commonMarkText, text: "]"}), i + 1
+	}
+
+	bracket := nodes[opener]
+	if bracket.inactive {
+		bracket.bracket = false
+		return append(nodes, &commonMarkInline{kind: commonMarkText, text: "]"}), i + 1
+	}
+
+	label := text[bracket.position:i]
+	link, end, ok := p.parseLinkTarget(text, i+1, label)
+	if !ok {
+		bracket.bracket = false
+		return append(nodes, &commonMarkInline{kind: commonMarkText, text: "]"}), i + 1
+	}
+
+	node := &commonMarkInline{
+		kind:     commonMarkLink,
+		url:      link,
+		children: processEmphasis(nodes[opener+1:]),
+	}
+	if bracket.image {
+		node.kind = commonMarkImage
+	} else {
+		for _, previous := range nodes[:opener] {
+			if previous.bracket && !previous.image {
+				previous.inactive = true
+			}
+		}
+	}
+
+	return append(nodes[:opener], node), end
+}
+
+// parseLinkTarget parses inline link destination or link reference after the link text, returns link and
+// position after it
+func (p *commonMarkParser) parseLinkTarget(text string, i int, label string) (string, int, bool) {
+	if i < len(text) && text[i] == '(' {
+		if link, end, ok := parseLinkDestination(text, i+1); ok {
+			return link, end, true
+		}
+	}
+
+	if i < len(text) && text[i] == '[' {
+		end := strings.IndexByte(text[i+1:], ']')
+		if end != -1 {
+			reference := text[i+1 : i+1+end]
+			if reference == "" {
+				reference = label
+			}
+
+			link, ok := p.references[normalizeReferenceLabel(reference)]
+			return link, i + end + 2, ok
+		}
+	}
+
+	link, ok := p.references[normalizeReferenceLabel(label)]
+	return link, i, ok
+}
+
+// parseLinkDestination parses link destination and optional title until closing ')', returns unescaped destination
+// and position after ')'
+func parseLinkDestination(text string, i int) (string, int, bool) {
+	i = skipCommonMarkSpaces(text, i)
+
+	start := i
+	var link string
+	if i < len(text) && text[i] == '<' {
+		end := strings.IndexAny(text[i+1:], ">\n")
+		if end == -1 || text[i+1+end] != '>' {
+			return "", 0, false
+		}
+		link = text[i+1 : i+1+end]
+		i += end + 2
+	} else {
+		depth := 0
+	loop:
+		for ; i < len(text); i++ {
+			switch c := text[i]; {
+			case c == '\\' && i+1 < len(text) && isASCIIPunctuation(text[i+1]):
+				i++
+			case c == '(':
+				depth++
+			case c == ')' && depth == 0:
+				break loop
+			case c == ')':
+				depth--
+			case c == ' ' || c == '\t' || c == '\n':
+				break loop
+			}
+		}
+		link = text[start:i]
+	}
+
+	i = skipCommonMarkSpaces(text, i)
+	if i < len(text) && (text[i] == '"' || text[i] == '\'' || text[i] == '(') && i != start {
+		closing := text[i]
+		if closing == '(' {
+			closing = ')'
+		}
+
+		end := strings.IndexByte(text[i+1:], closing)
+		if end == -1 {
+			return "", 0, false
+		}
+		i = skipCommonMarkSpaces(text, i+end+2)
+	}
+
+	if i >= len(text) || text[i] != ')' {
+		return "", 0, false
+	}
+
+	return unescapeCommonMark(link), i + 1, true
+}
+
+// processEmphasis matches delimiter runs and wraps elements between them into emphasis, strong emphasis and
+// strikethrough elements, unmatched delimiters and brackets become text
+func processEmphasis(nodes []*commonMarkInline) []*commonMarkInline {
+	for closer := 0; closer < len(nodes); closer++ {
+		closeNode := nodes[closer]
+		if closeNode.delimiter == 0 || !closeNode.canClose || closeNode.count == 0 {
+			continue
+		}
+
+		opener := -1
+		for j := closer - 1; j >= 0; j-- {
+			openNode := nodes[j]
+			if openNode.delimiter != closeNode.delimiter || !openNode.canOpen || openNode.count == 0 {
+				continue
+			}
+
+			if openNode.delimiter == '~' {
+				if openNode.count != closeNode.count {
+					continue
+				}
+			} else if (openNode.canClose || closeNode.canOpen) && (openNode.original+closeNode.original)%3 == 0 &&
+				(openNode.original%3 != 0 || closeNode.original%3 != 0) {
+				continue
+			}
+
+			opener = j
+			break
+		}
+		if opener == -1 {
+			continue
+		}
+
+		openNode := nodes[opener]
+		used := 1
+		kind := commonMarkEmphasis
+		switch {
+		case openNode.delimiter == '~':
+			used = openNode.count
+			kind = commonMarkStrikethrough
+		case openNode.count >= 2 && closeNode.count >= 2:
+			used = 2
+			kind = commonMarkStrong
+		}
+
+		openNode.count -= used
+		closeNode.count -= used
+		openNode.text = openNode.text[:openNode.count]
+		closeNode.text = closeNode.text[:closeNode.count]
+
+		node := &commonMarkInline{kind: kind, children: textNodes(nodes[opener+1 : closer])}
+
+		replaced := append([]*commonMarkInline{}, nodes[:opener+1]...)
+		replaced = append(replaced, node)
+		replaced = append(replaced, nodes[closer:]...)
+		nodes = replaced
+		closer = opener + 1
+	}
+
+	return textNodes(nodes)
+}
+
+// textNodes converts unmatched delimiter runs and brackets into text nodes, empty text nodes are removed
+func textNodes(nodes []*commonMarkInline) []*commonMarkInline {
+	result := make([]*commonMarkInline, 0, len(nodes))
+	for _, node := range nodes {
+		if node.delimiter != 0 || node.bracket {
+			node = &commonMarkInline{kind: commonMarkText, text: node.text}
+		}
+		if node.kind == commonMarkText && node.text == "" {
+			continue
+		}
+		result = append(result, node)
+	}
+	return result
+}
+
+// unescapeCommonMark removes backslash escapes and decodes entity references
+func unescapeCommonMark(text string) string {
+	result := strings.Builder{}
+	for i := 0; i < len(text); i++ {
+		switch {
+		case text[i] == '\\' && i+1 < len(text) && isASCIIPunctuation(text[i+1]):
+			result.WriteByte(text[i+1])
+			i++
+		case text[i] == '&' && commonMarkEntityRegexp.MatchString(text[i:]):
+			entity := commonMarkEntityRegexp.FindString(text[i:])
+			result.WriteString(html.UnescapeString(entity))
+			i += len(entity) - 1
+		default:
+			result.WriteByte(text[i])
+		}
+	}
+	return result.String()
+}
+
+// skipCommonMarkSpaces returns position of the first non-whitespace character
+func skipCommonMarkSpaces(text string, i int) int {
+	for i < len(text) && (text[i] == ' ' || text[i] == '\t' || text[i] == '\n') {
+		i++
+	}
+	return i
+}
+
+// isASCIIPunctuation reports if character is ASCII punctuation that can be escaped
+func isASCIIPunctuation(c byte) bool {
+	return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) != -1
+}
+
+// isUnicodePunctuation reports if rune is Unicode punctuation or symbol
+func isUnicodePunctuation(r rune) bool {
+	return unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
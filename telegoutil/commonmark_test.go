@@ -0,0 +1,164 @@
+package telegoutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mymmrac/telego"
+)
+
+func TestParseCommonMark(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		text     string
+		entities []telego.MessageEntity
+	}{
+		{
+			name:     "empty",
+			markdown: "",
+			text:     "",
+		},
+		{
+			name:     "inline",
+			markdown: "*it* __bold__ ~~strike~~ `a*b` ***both*** snake_case_word 2*3*4 \\*esc\\* &amp; **open",
+			text:     "it bold strike a*b both snake_case_word 234 *esc* & **open",
+			entities: []telego.MessageEntity{
+				{Type: telego.EntityTypeItalic, Offset: 0, Length: 2},
+				{Type: telego.EntityTypeBold, Offset: 3, Length: 4},
+				{Type: telego.EntityTypeStrikethrough, Offset: 8, Length: 6},
+				{Type: telego.EntityTypeCode, Offset: 15, Length: 3},
+				{Type: telego.EntityTypeBold, Offset: 19, Length: 4},
+				{Type: telego.EntityTypeItalic, Offset: 19, Length: 4},
+				{Type: telego.EntityTypeItalic, Offset: 41, Length: 1},
+			},
+		},
+		{
+			name: "links",
+			markdown: "[a *b*](https://example.com \"title\") ![img](https://example.com/i.png) " +
+				"<https://example.org> [ref] [x][Ref] [not a link] [rel](/path)\n\n[ref]: <https://ref.example>",
+			text: "a b img https://example.org ref x [not a link] rel",
+			entities: []telego.MessageEntity{
+				{Type: telego.EntityTypeTextLink, Offset: 0, Length: 3, URL: "https://example.com"},
+				{Type: telego.EntityTypeItalic, Offset: 2, Length: 1},
+				{Type: telego.EntityTypeTextLink, Offset: 4, Length: 3, URL: "https://example.com/i.png"},
+				{Type: telego.EntityTypeTextLink, Offset: 8, Length: 19, URL: "https://example.org"},
+				{Type: telego.EntityTypeTextLink, Offset: 28, Length: 3, URL: "https://ref.example"},
+				{Type: telego.EntityTypeTextLink, Offset: 32, Length: 1, URL: "https://ref.example"},
+			},
+		},
+		{
+			name:     "line_breaks",
+			markdown: "soft\nbreak  \nhard\\\nslash",
+			text:     "soft\nbreak\nhard\nslash",
+		},
+		{
+			name:     "headings",
+			markdown: "# Title `code`\nText\n\nSetext\n---\n\n###### Small ###",
+			text:     "Title code\n\nText\n\nSetext\n\nSmall",
+			entities: []telego.MessageEntity{
+				{Type: telego.EntityTypeBold, Offset: 0, Length: 10},
+				{Type: telego.EntityTypeCode, Offset: 6, Length: 4},
+				{Type: telego.EntityTypeBold, Offset: 18, Length: 6},
+				{Type: telego.EntityTypeBold, Offset: 26, Length: 5},
+			},
+		},
+		{
+			name: "lists",
+			markdown: "- one\n- **two**\n  * nested\n    lazy\n  * [x] done\n- [ ] todo\n\n" +
+				"3) three\n4) four\n\n1. a\n\n2. b",
+			text: "• one\n• two\n  ◦ nested\n    lazy\n  ☑ done\n☐ todo\n\n" +
+				"3. three\n4. four\n\n1. a\n\n2. b",
+			entities: []telego.MessageEntity{
+				{Type: telego.EntityTypeBold, Offset: 8, Length: 3},
+			},
+		},
+		{
+			name:     "code",
+			markdown: "```python title\nprint(1)\n\n```\n\n    indented\n    code\n\n- item\n\n  ~~~\n  nested\n  ~~~",
+			text:     "print(1)\n\n\nindented\ncode\n\n• item\n\n  nested",
+			entities: []telego.MessageEntity{
+				{Type: telego.EntityTypePre, Offset: 0, Length: 9, Language: "python"},
+				{Type: telego.EntityTypePre, Offset: 11, Length: 13},
+				{Type: telego.EntityTypePre, Offset: 36, Length: 6},
+			},
+		},
+		{
+			name:     "quote",
+			markdown: "> quote *a*\nlazy\n> > nested\n\nafter",
+			text:     "quote a\nlazy\n\nnested\n\nafter",
+			entities: []telego.MessageEntity{
+				{Type: telego.EntityTypeBlockquote, Offset: 0, Length: 20},
+				{Type: telego.EntityTypeItalic, Offset: 6, Length: 1},
+			},
+		},
+		{
+			name:     "table",
+			markdown: "| Name | Age | Note |\n|:-----|----:|:---:|\n| **Alice** | 30 | a \\| b |\n| Bob | 4\n\n---",
+			text:     "Name  | Age | Note\n------+-----+------\nAlice |  30 | a | b\nBob   |   4 |\n\n———",
+			entities: []telego.MessageEntity{
+				{Type: telego.EntityTypePre, Offset: 0, Length: 72},
+			},
+		},
+		{
+			name:     "not_table",
+			markdown: "a | b\n--- | ---x",
+			text:     "a | b\n--- | ---x",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, entities := ParseCommonMark(tt.markdown)
+			assert.Equal(t, tt.text, text)
+			assert.Equal(t, tt.entities, entities)
+		})
+	}
+}
+
+func TestRichMessageCommonMark(t *testing.T) {
+	code := RichBlockPreformatted(RichTextPlain("x := 1"))
+	code.Language = "go"
+
+	item1 := RichBlockListItem(RichBlockParagraph(
+		RichTextList(RichTextPlain("a "), RichTextItalic(RichTextPlain("b"))),
+	))
+	item1.Value = 1
+	item1.Type = telego.OrderedListDecimal
+	item2 := RichBlockListItem(RichBlockParagraph(RichTextPlain("c")))
+	item2.Value = 2
+	item2.Type = telego.OrderedListDecimal
+	item2.HasCheckbox = true
+	item2.IsChecked = true
+
+	header := RichBlockTableCell(RichTextPlain("x"))
+	header.IsHeader = true
+	header.Align = telego.CellAlignLeft
+	header.Valign = telego.CellValignMiddle
+	cell := RichBlockTableCell(RichTextURL(RichTextPlain("y"), "https://example.com"))
+	cell.Align = telego.CellAlignLeft
+	cell.Valign = telego.CellValignMiddle
+	table := RichBlockTable(RichBlockTableRow(header), RichBlockTableRow(cell))
+	table.IsBordered = true
+
+	expected := RichMessage(
+		RichBlockSectionHeading(RichTextBold(RichTextPlain("Title")), 2),
+		RichBlockParagraph(RichTextList(
+			RichTextPlain("text\n"),
+			RichTextStrikethrough(RichTextPlain("strike")),
+			RichTextPlain(" "),
+			RichTextCode(RichTextPlain("code")),
+		)),
+		code,
+		RichBlockBlockQuotation(RichBlockParagraph(RichTextPlain("quote"))),
+		RichBlockList(item1, item2),
+		table,
+		RichBlockDivider(),
+	)
+
+	assert.Equal(t, expected, RichMessageCommonMark(
+		"## **Title**\ntext\n~~strike~~ `code`\n```go\nx := 1\n```\n> quote\n\n1. a *b*\n2. [x] c\n\n"+
+			"| x |\n| - |\n| [y](https://example.com) |\n\n***",
+	))
+}
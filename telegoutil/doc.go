@@ -4,10 +4,14 @@ Package telegoutil provides utility methods for Telego.
 Those utility methods provide a convenient way of construction Telegram methods parameters and other types.
 
 Utilities by files:
-* api.go     - low-level API of Telego
-* methods.go - Telegram methods parameters
-* types.go   - types used in methods parameters
-* handler.go - handler and predicate helpers
+* api.go        - low-level API of Telego
+* methods.go    - Telegram methods parameters
+* types.go      - types used in methods parameters
+* handler.go    - handler and predicate helpers
+* split.go      - splitting of long messages
+* parse.go      - parsing of HTML and MarkdownV2 formatted text
+* render.go     - rendering of text and entities into HTML and MarkdownV2
+* commonmark.go - conversion of CommonMark and GFM Markdown into entities and rich messages
 
 Dev Note: This package is designed to be self-contained, and other packages of Telego should not depend on utilities.
 */
@@ -0,0 +1,296 @@
+package telegoutil
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mymmrac/telego"
+)
+
+// ParseError represents error of parsing formatted text, returned by [ParseHTML] and [ParseMarkdownV2]
+type ParseError struct {
+	// Offset in bytes of the formatted text where error occurred
+	Offset int
+	// Message describes what is wrong
+	Message string
+}
+
+// Error returns error message with its position
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("telegoutil: can't parse entities: %s at byte offset %d", e.Message, e.Offset)
+}
+
+// parseErrorf returns new [ParseError]
+func parseErrorf(offset int, format string, args ...any) error {
+	return &ParseError{
+		Offset:  offset,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// ParseFormatted parses text formatted using [telego.ModeHTML] or [telego.ModeMarkdownV2] parse mode into collection
+// of text and entities, that can be merged with other collections using [MessageEntities]
+func ParseFormatted(text, parseMode string) (MessageEntityCollection, error) {
+	var entities []telego.MessageEntity
+	var err error
+
+	switch parseMode {
+	case telego.ModeHTML:
+		text, entities, err = ParseHTML(text)
+	case telego.ModeMarkdownV2:
+		text, entities, err = ParseMarkdownV2(text)
+	default:
+		return MessageEntityCollection{}, fmt.Errorf("telegoutil: unsupported parse mode %q", parseMode)
+	}
+	if err != nil {
+		return MessageEntityCollection{}, err
+	}
+
+	return MessageEntityCollection{
+		text:     text,
+		entities: entities,
+	}, nil
+}
+
+// entityBuilder accumulates plain text and entities of it
+type entityBuilder struct {
+	text     strings.Builder
+	offset   int
+	entities []telego.MessageEntity
+}
+
+// writeString appends text and updates offset in UTF-16 code units, text can be a part of UTF-8 encoded rune
+func (b *entityBuilder) writeString(text string) {
+	b.text.WriteString(text)
+	b.offset += UTF16TextLen(text)
+}
+
+// add adds entity that starts at offset and ends at the current offset, empty entities are ignored
+func (b *entityBuilder) add(offset int, entity telego.MessageEntity) {
+	if b.offset <= offset {
+		return
+	}
+
+	entity.Offset = offset
+	entity.Length = b.offset - offset
+	b.entities = append(b.entities, entity)
+}
+
+// result returns text and entities normalized the same way Telegram does
+func (b *entityBuilder) result() (string, []telego.MessageEntity) {
+	return b.text.String(), normalizeEntities(b.entities)
+}
+
+// normalizeEntities returns entities sorted by offset (outer entities first), entities that can't be nested (like
+// formatting inside code) or partially overlap code entities and blockquotes are removed, and overlapping or adjacent
+// entities of the same splittable type (like bold) are merged the same way Telegram does
+func normalizeEntities(entities []telego.MessageEntity) []telego.MessageEntity {
+	valid := make([]telego.MessageEntity, 0, len(entities))
+	for _, entity := range entities {
+		if entity.Offset >= 0 && entity.Length > 0 {
+			valid = append(valid, entity)
+		}
+	}
+	sortEntities(valid)
+
+	structural := make([]telego.MessageEntity, 0, len(valid))
+	for _, entity := range valid {
+		if (isCodeEntity(entity) || isBlockquoteEntity(entity)) && isEntityAllowed(entity, structural) {
+			structural = append(structural, entity)
+		}
+	}
+
+	allowed := structural
+	for _, entity := range valid {
+		if !isCodeEntity(entity) && !isBlockquoteEntity(entity) && isEntityAllowed(entity, structural) {
+			allowed = append(allowed, entity)
+		}
+	}
+
+	result := make([]telego.MessageEntity, 0, len(allowed))
+	for _, entity := range allowed {
+		merged := false
+		if isSplittableEntity(entity) {
+			for i := range result {
+				other := &result[i]
+				if other.Type != entity.Type || entityEnd(*other) < entity.Offset {
+					continue
+				}
+
+				other.Length = max(entityEnd(*other), entityEnd(entity)) - other.Offset
+				merged = true
+				break
+			}
+		}
+
+		if !merged {
+			result = append(result, entity)
+		}
+	}
+	sortEntities(result)
+
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// isEntityAllowed reports if entity can be nested into or contain already allowed code entities and blockquotes
+func isEntityAllowed(entity telego.MessageEntity, structural []telego.MessageEntity) bool {
+	for _, other := range structural {
+		outer, inner := other, entity
+		if compareEntities(entity, other) < 0 {
+			outer, inner = entity, other
+		}
+
+		if entityEnd(outer) <= inner.Offset {
+			continue
+		}
+
+		if entityEnd(outer) < entityEnd(inner) ||
+			isCodeEntity(outer) || (isBlockquoteEntity(outer) && isBlockquoteEntity(inner)) {
+			return false
+		}
+	}
+	return true
+}
+
+// sortEntities sorts entities by offset, entities that contain others go first
+func sortEntities(entities []telego.MessageEntity) {
+	slices.SortStableFunc(entities, compareEntities)
+}
+
+// compareEntities compares entities by offset, entities that contain others go first, entities with the same range
+// are ordered by type, so the order doesn't depend on how entities were nested
+func compareEntities(a, b telego.MessageEntity) int {
+	if a.Offset != b.Offset {
+		return a.Offset - b.Offset
+	}
+	if a.Length != b.Length {
+		return b.Length - a.Length
+	}
+	if priority := entityPriority(a) - entityPriority(b); priority != 0 {
+		return priority
+	}
+	return strings.Compare(a.Type, b.Type)
+}
+
+// entityEnd returns offset of entity end
+func entityEnd(entity telego.MessageEntity) int {
+	return entity.Offset + entity.Length
+}
+
+// entityPriority returns priority of entity used to order entities with the same range, entities that contain others
+// go first
+func entityPriority(entity telego.MessageEntity) int {
+	switch {
+	case isCodeEntity(entity):
+		return 0
+	case isBlockquoteEntity(entity):
+		return 1
+	default:
+		return 2 //nolint:mnd
+	}
+}
+
+// isCodeEntity reports if entity can't contain other entities
+func isCodeEntity(entity telego.MessageEntity) bool {
+	return entity.Type == telego.EntityTypeCode || entity.Type == telego.EntityTypePre
+}
+
+// isSplittableEntity reports if entity can be split into parts or merged with other entities of the same type
+func isSplittableEntity(entity telego.MessageEntity) bool {
+	switch entity.Type {
+	case telego.EntityTypeBold, telego.EntityTypeItalic, telego.EntityTypeUnderline,
+		telego.EntityTypeStrikethrough, telego.EntityTypeSpoiler:
+		return true
+	default:
+		return false
+	}
+}
+
+// isBlockquoteEntity reports if entity is blockquote
+func isBlockquoteEntity(entity telego.MessageEntity) bool {
+	return entity.Type == telego.EntityTypeBlockquote || entity.Type == telego.EntityTypeExpandableBlockquote
+}
+
+// linkEntity returns text mention entity for tg://user links or text link entity for other links, false if link is not
+// a valid URL
+func linkEntity(link string) (telego.MessageEntity, bool) {
+	link = strings.TrimSpace(link)
+	if link == "" {
+		return telego.MessageEntity{}, false
+	}
+
+	if !strings.Contains(link, "://") && !strings.HasPrefix(link, "mailto:") {
+		link = "http://" + link
+	}
+
+	linkURL, err := url.Parse(link)
+	if err != nil || linkURL.Scheme == "" {
+		return telego.MessageEntity{}, false
+	}
+
+	if linkURL.Scheme == "tg" && linkURL.Host == "user" {
+		userID, idErr := strconv.ParseInt(linkURL.Query().Get("id"), 10, 64)
+		if idErr == nil && userID > 0 {
+			return telego.MessageEntity{Type: telego.EntityTypeTextMention, User: &telego.User{ID: userID}}, true
+		}
+	}
+
+	if (linkURL.Scheme == "http" || linkURL.Scheme == "https") && linkURL.Host == "" {
+		return telego.MessageEntity{}, false
+	}
+
+	return telego.MessageEntity{Type: telego.EntityTypeTextLink, URL: link}, true
+}
+
+// customEmojiEntity returns custom emoji entity with provided ID
+func customEmojiEntity(emojiID string) (telego.MessageEntity, error) {
+	id, err := strconv.ParseInt(emojiID, 10, 64)
+	if err != nil || id == 0 {
+		return telego.MessageEntity{}, errors.New("invalid custom emoji identifier specified")
+	}
+
+	return telego.MessageEntity{Type: telego.EntityTypeCustomEmoji, CustomEmojiID: emojiID}, nil
+}
+
+// dateTimeFormatRegexp matches valid date-time entity formats
+var dateTimeFormatRegexp = regexp.MustCompile(`^(r|w?[dD]?[tT]?)$`)
+
+// dateTimeEntity returns date-time entity with provided Unix time and format
+func dateTimeEntity(unixTime, format string) (telego.MessageEntity, error) {
+	unix, err := strconv.ParseInt(unixTime, 10, 64)
+	if err != nil {
+		return telego.MessageEntity{}, errors.New("invalid date-time Unix time specified")
+	}
+
+	if !dateTimeFormatRegexp.MatchString(format) {
+		return telego.MessageEntity{}, fmt.Errorf("invalid date-time format %q specified", format)
+	}
+
+	return telego.MessageEntity{Type: telego.EntityTypeDateTime, UnixTime: unix, DateTimeFormat: format}, nil
+}
+
+// tgLinkEntity returns custom emoji or date-time entity for tg://emoji and tg://time links
+func tgLinkEntity(link string) (telego.MessageEntity, error) {
+	linkURL, err := url.Parse(link)
+	if err != nil || linkURL.Scheme != "tg" {
+		return telego.MessageEntity{}, errors.New("custom emoji entity must contain a tg://emoji URL")
+	}
+
+	query := linkURL.Query()
+	switch linkURL.Host {
+	case "emoji":
+		return customEmojiEntity(query.Get("id"))
+	case "time":
+		return dateTimeEntity(query.Get("unix"), query.Get("format"))
+	default:
+		return telego.MessageEntity{}, errors.New("custom emoji entity must contain a tg://emoji URL")
+	}
+}
@@ -0,0 +1,353 @@
+package telegoutil
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mymmrac/telego"
+)
+
+// htmlTag represents open HTML tag
+type htmlTag struct {
+	name       string
+	position   int
+	offset     int
+	textStart  int
+	argument   string
+	expandable bool
+	unixTime   string
+	format     string
+}
+
+// htmlTagNames supported HTML tags
+var htmlTagNames = map[string]bool{
+	"a": true, "b": true, "strong": true, "i": true, "em": true, "s": true, "strike": true, "del": true, "u": true,
+	"ins": true, "tg-spoiler": true, "tg-emoji": true, "tg-time": true, "span": true, "pre": true, "code": true,
+	"blockquote": true,
+}
+
+// ParseHTML parses text formatted using Telegram HTML style into plain text and entities, the same rules as
+// Telegram's are used: supported tags are b, strong, i, em, u, ins, s, strike, del, span class="tg-spoiler",
+// tg-spoiler, a href, tg-emoji emoji-id, tg-time unix and format, code, pre (with nested code class="language-x")
+// and blockquote (optionally expandable), only &lt;, &gt;, &amp;, &quot; and numeric HTML entities are supported.
+// Malformed input results in [ParseError] with the position of the error.
+//
+// Note: Leading and trailing whitespace of the text is not trimmed, while Telegram trims it when sending message
+func ParseHTML(text string) (string, []telego.MessageEntity, error) {
+	builder := &entityBuilder{}
+	var tags []htmlTag
+
+	for i := 0; i < len(text); {
+		switch text[i] {
+		case '&':
+			decoded, size := decodeHTMLEntity(text[i:])
+			if size == 0 {
+				builder.writeString("&")
+				i++
+				continue
+			}
+
+			builder.writeString(decoded)
+			i += size
+		case '<':
+			if strings.HasPrefix(text[i:], "</") {
+				end, err := parseHTMLEndTag(text, i, tags, builder)
+				if err != nil {
+					return "", nil, err
+				}
+
+				tags = tags[:len(tags)-1]
+				i = end
+				continue
+			}
+
+			tag, end, err := parseHTMLStartTag(text, i)
+			if err != nil {
+				return "", nil, err
+			}
+
+			tag.offset = builder.offset
+			tag.textStart = builder.text.Len()
+			tags = append(tags, tag)
+			i = end
+		default:
+			builder.writeString(text[i : i+1])
+			i++
+		}
+	}
+
+	if len(tags) != 0 {
+		tag := tags[len(tags)-1]
+		return "", nil, parseErrorf(tag.position, "can't find end tag corresponding to start tag %q", tag.name)
+	}
+
+	resultText, entities := builder.result()
+	return resultText, entities, nil
+}
+
+// decodeHTMLEntity decodes supported HTML entity at the start of the text, returns size of zero if there is no valid
+// entity
+func decodeHTMLEntity(text string) (string, int) {
+	end := strings.IndexByte(text, ';')
+	if end <= 1 {
+		return "", 0
+	}
+	name := text[1:end]
+
+	switch name {
+	case "lt":
+		return "<", end + 1
+	case "gt":
+		return ">", end + 1
+	case "amp":
+		return "&", end + 1
+	case "quot":
+		return "\"", end + 1
+	}
+
+	if !strings.HasPrefix(name, "#") {
+		return "", 0
+	}
+
+	var code uint64
+	var err error
+	if strings.HasPrefix(name, "#x") || strings.HasPrefix(name, "#X") {
+		code, err = strconv.ParseUint(name[2:], 16, 32)
+	} else {
+		code, err = strconv.ParseUint(name[1:], 10, 32)
+	}
+	if err != nil || code == 0 || !utf8.ValidRune(rune(code)) {
+		return "", 0
+	}
+
+	return string(rune(code)), end + 1
+}
+
+// parseHTMLStartTag parses start tag at the position with its attributes, returns position after the tag
+func parseHTMLStartTag(text string, position int) (htmlTag, int, error) {
+	i := position + 1
+	for i < len(text) && !isHTMLSpace(text[i]) && text[i] != '>' {
+		i++
+	}
+	if i == len(text) {
+		return htmlTag{}, 0, parseErrorf(position, "unclosed start tag")
+	}
+
+	tag := htmlTag{
+		name:     strings.ToLower(text[position+1 : i]),
+		position: position,
+	}
+	if !htmlTagNames[tag.name] {
+		return htmlTag{}, 0, parseErrorf(position, "unsupported start tag %q", tag.name)
+	}
+
+	for {
+		for i < len(text) && isHTMLSpace(text[i]) {
+			i++
+		}
+		if i == len(text) {
+			return htmlTag{}, 0, parseErrorf(position, "unclosed start tag %q", tag.name)
+		}
+		if text[i] == '>' {
+			break
+		}
+
+		nameStart := i
+		for i < len(text) && !isHTMLSpace(text[i]) && text[i] != '=' && text[i] != '>' {
+			i++
+		}
+		attributeName := strings.ToLower(text[nameStart:i])
+
+		for i < len(text) && isHTMLSpace(text[i]) {
+			i++
+		}
+		if i == len(text) || text[i] != '=' {
+			if tag.name == "blockquote" && attributeName == "expandable" {
+				tag.expandable = true
+				continue
+			}
+			return htmlTag{}, 0, parseErrorf(position,
+				"expected equal sign in declaration of an attribute of the tag %q", tag.name)
+		}
+		i++
+
+		for i < len(text) && isHTMLSpace(text[i]) {
+			i++
+		}
+		if i == len(text) {
+			return htmlTag{}, 0, parseErrorf(position, "unclosed start tag %q", tag.name)
+		}
+
+		var value string
+		var err error
+		value, i, err = parseHTMLAttributeValue(text, i)
+		if err != nil {
+			return htmlTag{}, 0, err
+		}
+		if i == len(text) {
+			return htmlTag{}, 0, parseErrorf(position, "unclosed start tag %q", tag.name)
+		}
+
+		switch {
+		case tag.name == "a" && attributeName == "href":
+			tag.argument = value
+		case tag.name == "code" && attributeName == "class" && strings.HasPrefix(value, "language-"):
+			tag.argument = strings.TrimPrefix(value, "language-")
+		case tag.name == "span" && attributeName == "class" && strings.HasPrefix(value, "tg-"):
+			tag.argument = strings.TrimPrefix(value, "tg-")
+		case tag.name == "tg-emoji" && attributeName == "emoji-id":
+			tag.argument = value
+		case tag.name == "tg-time" && attributeName == "unix":
+			tag.unixTime = value
+		case tag.name == "tg-time" && attributeName == "format":
+			tag.format = value
+		}
+	}
+
+	if tag.name == "span" && tag.argument != "spoiler" {
+		return htmlTag{}, 0, parseErrorf(position, "tag \"span\" must have class \"tg-spoiler\"")
+	}
+
+	return tag, i + 1, nil
+}
+
+// parseHTMLAttributeValue parses quoted or unquoted attribute value, returns position after the value
+func parseHTMLAttributeValue(text string, i int) (string, int, error) {
+	if text[i] != '"' && text[i] != '\'' {
+		start := i
+		for i < len(text) && isHTMLNameChar(text[i]) {
+			i++
+		}
+		if i < len(text) && !isHTMLSpace(text[i]) && text[i] != '>' {
+			return "", 0, parseErrorf(start, "unexpected end of name token")
+		}
+		return strings.ToLower(text[start:i]), i, nil
+	}
+
+	quote := text[i]
+	i++
+
+	value := strings.Builder{}
+	for i < len(text) && text[i] != quote {
+		if text[i] == '&' {
+			if decoded, size := decodeHTMLEntity(text[i:]); size != 0 {
+				value.WriteString(decoded)
+				i += size
+				continue
+			}
+		}
+
+		value.WriteByte(text[i])
+		i++
+	}
+	if i < len(text) {
+		i++
+	}
+
+	return value.String(), i, nil
+}
+
+// parseHTMLEndTag parses end tag at the position and adds entity of the last open tag, returns position after the tag
+func parseHTMLEndTag(text string, position int, tags []htmlTag, builder *entityBuilder) (int, error) {
+	if len(tags) == 0 {
+		return 0, parseErrorf(position, "unexpected end tag")
+	}
+
+	i := position + 2
+	for i < len(text) && !isHTMLSpace(text[i]) && text[i] != '>' {
+		i++
+	}
+	name := strings.ToLower(text[position+2 : i])
+	for i < len(text) && isHTMLSpace(text[i]) {
+		i++
+	}
+	if i == len(text) || text[i] != '>' {
+		return 0, parseErrorf(position, "unclosed end tag")
+	}
+
+	tag := tags[len(tags)-1]
+	if name != tag.name {
+		return 0, parseErrorf(position, "unmatched end tag, expected \"</%s>\", found \"</%s>\"", tag.name, name)
+	}
+
+	if err := addHTMLEntity(tag, tags[:len(tags)-1], builder); err != nil {
+		return 0, parseErrorf(tag.position, "%s", err)
+	}
+
+	return i + 1, nil
+}
+
+// addHTMLEntity adds entity of the closed tag
+func addHTMLEntity(tag htmlTag, parents []htmlTag, builder *entityBuilder) error {
+	if builder.offset <= tag.offset {
+		return nil
+	}
+
+	switch tag.name {
+	case "b", "strong":
+		builder.add(tag.offset, telego.MessageEntity{Type: telego.EntityTypeBold})
+	case "i", "em":
+		builder.add(tag.offset, telego.MessageEntity{Type: telego.EntityTypeItalic})
+	case "u", "ins":
+		builder.add(tag.offset, telego.MessageEntity{Type: telego.EntityTypeUnderline})
+	case "s", "strike", "del":
+		builder.add(tag.offset, telego.MessageEntity{Type: telego.EntityTypeStrikethrough})
+	case "tg-spoiler", "span":
+		builder.add(tag.offset, telego.MessageEntity{Type: telego.EntityTypeSpoiler})
+	case "a":
+		link := tag.argument
+		if link == "" {
+			link = builder.text.String()[tag.textStart:]
+		}
+		if entity, ok := linkEntity(link); ok {
+			builder.add(tag.offset, entity)
+		}
+	case "tg-emoji":
+		entity, err := customEmojiEntity(tag.argument)
+		if err != nil {
+			return err
+		}
+		builder.add(tag.offset, entity)
+	case "tg-time":
+		entity, err := dateTimeEntity(tag.unixTime, tag.format)
+		if err != nil {
+			return err
+		}
+		builder.add(tag.offset, entity)
+	case "blockquote":
+		if tag.expandable {
+			builder.add(tag.offset, telego.MessageEntity{Type: telego.EntityTypeExpandableBlockquote})
+		} else {
+			builder.add(tag.offset, telego.MessageEntity{Type: telego.EntityTypeBlockquote})
+		}
+	case "code":
+		language := ""
+		if len(parents) != 0 && parents[len(parents)-1].name == "pre" {
+			language = tag.argument
+		}
+		builder.add(tag.offset, telego.MessageEntity{Type: telego.EntityTypeCode, Language: language})
+	case "pre":
+		if last := len(builder.entities) - 1; last >= 0 {
+			entity := &builder.entities[last]
+			if entity.Type == telego.EntityTypeCode && entity.Offset == tag.offset &&
+				entity.Length == builder.offset-tag.offset {
+				entity.Type = telego.EntityTypePre
+				return nil
+			}
+		}
+		builder.add(tag.offset, telego.MessageEntity{Type: telego.EntityTypePre})
+	}
+
+	return nil
+}
+
+// isHTMLSpace reports if character is HTML whitespace
+func isHTMLSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+// isHTMLNameChar reports if character can be used in unquoted attribute value
+func isHTMLNameChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '.' || c == '-'
+}
@@ -0,0 +1,315 @@
+package telegoutil
+
+import (
+	"strings"
+
+	"github.com/mymmrac/telego"
+)
+
+// markdownV2Reserved characters that must be escaped in MarkdownV2 outside of code entities
+const markdownV2Reserved = "_*[]()~`>#+-=|{}.!"
+
+// markdownEntity represents open MarkdownV2 entity
+type markdownEntity struct {
+	entityType string
+	position   int
+	offset     int
+	textStart  int
+	language   string
+}
+
+// markdownQuote represents state of MarkdownV2 blockquote
+type markdownQuote struct {
+	active     bool
+	offset     int
+	expandable bool
+}
+
+// ParseMarkdownV2 parses text formatted using Telegram MarkdownV2 style into plain text and entities, the same rules as
+// Telegram's are used: *bold*, _italic_, __underline__, ~strikethrough~, ||spoiler||, [text link](URL), [text
+// mention](tg://user?id=1), ![👍](tg://emoji?id=1), ![date](tg://time?unix=1&format=wDT), `code`, ```language pre```,
+// >blockquote and **>expandable blockquote||, all reserved characters must be escaped with a preceding '\'. Not
+// escaped '\r' characters are ignored and can be used to resolve ambiguity between italic and underline entities.
+// Malformed input results in [ParseError] with the position of the error.
+//
+// Note: Leading and trailing whitespace of the text is not trimmed, while Telegram trims it when sending message
+func ParseMarkdownV2(text string) (string, []telego.MessageEntity, error) {
+	builder := &entityBuilder{}
+	var entities []markdownEntity
+	var quote markdownQuote
+
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+
+		inCode := false
+		if len(entities) != 0 {
+			topType := entities[len(entities)-1].entityType
+			inCode = topType == telego.EntityTypeCode || topType == telego.EntityTypePre
+		}
+
+		if (i == 0 || text[i-1] == '\n') && !inCode {
+			switch {
+			case c == '>':
+				quote.start(builder)
+				continue
+			case strings.HasPrefix(text[i:], "**>"):
+				quote.start(builder)
+				quote.expandable = true
+				i += 2
+				continue
+			case quote.active:
+				quote.end(builder, builder.offset-1)
+			}
+		}
+
+		if c == '\r' {
+			continue
+		}
+
+		if c == '\\' && i+1 < len(text) && text[i+1] > 0 && text[i+1] <= 126 {
+			i++
+			builder.writeString(text[i : i+1])
+			continue
+		}
+
+		reserved := markdownV2Reserved
+		if inCode {
+			reserved = "`"
+		}
+		if strings.IndexByte(reserved, c) == -1 {
+			builder.writeString(text[i : i+1])
+			continue
+		}
+
+		if quote.active && !inCode && isMarkdownQuoteEnd(text, i, entities) {
+			quote.expandable = true
+			quote.end(builder, builder.offset)
+			i++
+			continue
+		}
+
+		if len(entities) != 0 && isMarkdownEntityEnd(text, i, entities[len(entities)-1].entityType) {
+			entity := entities[len(entities)-1]
+			entities = entities[:len(entities)-1]
+
+			var err error
+			i, err = addMarkdownEntity(text, i, entity, builder)
+			if err != nil {
+				return "", nil, err
+			}
+			continue
+		}
+
+		entity := markdownEntity{
+			position:  i,
+			offset:    builder.offset,
+			textStart: builder.text.Len(),
+		}
+
+		switch {
+		case c == '_' && nextByte(text, i) == '_':
+			entity.entityType = telego.EntityTypeUnderline
+			i++
+		case c == '_':
+			entity.entityType = telego.EntityTypeItalic
+		case c == '*':
+			entity.entityType = telego.EntityTypeBold
+		case c == '~':
+			entity.entityType = telego.EntityTypeStrikethrough
+		case c == '|' && nextByte(text, i) == '|':
+			entity.entityType = telego.EntityTypeSpoiler
+			i++
+		case c == '[':
+			entity.entityType = telego.EntityTypeTextLink
+		case c == '!' && nextByte(text, i) == '[':
+			entity.entityType = telego.EntityTypeCustomEmoji
+			i++
+		case strings.HasPrefix(text[i:], "```"):
+			entity.entityType = telego.EntityTypePre
+			entity.language, i = parseMarkdownPreStart(text, i+3)
+			i--
+		case c == '`':
+			entity.entityType = telego.EntityTypeCode
+		default:
+			return "", nil, parseErrorf(i, "character '%c' is reserved and must be escaped with the preceding '\\'", c)
+		}
+
+		entities = append(entities, entity)
+	}
+
+	if len(entities) != 0 {
+		entity := entities[len(entities)-1]
+		return "", nil, parseErrorf(entity.position, "can't find end of %s entity", entity.entityType)
+	}
+
+	if quote.active {
+		quote.end(builder, builder.offset)
+	}
+
+	resultText, resultEntities := builder.result()
+	return resultText, resultEntities, nil
+}
+
+// start starts blockquote if it's not active
+func (q *markdownQuote) start(builder *entityBuilder) {
+	if q.active {
+		return
+	}
+
+	q.active = true
+	q.offset = builder.offset
+	q.expandable = false
+}
+
+// end adds blockquote entity that ends at the offset
+func (q *markdownQuote) end(builder *entityBuilder, end int) {
+	q.active = false
+	if end <= q.offset {
+		return
+	}
+
+	entityType := telego.EntityTypeBlockquote
+	if q.expandable {
+		entityType = telego.EntityTypeExpandableBlockquote
+	}
+
+	builder.entities = append(builder.entities, telego.MessageEntity{
+		Type:   entityType,
+		Offset: q.offset,
+		Length: end - q.offset,
+	})
+}
+
+// isMarkdownQuoteEnd reports if there is an expandable blockquote mark (|| at the end of the line) at the position,
+// that doesn't close a spoiler
+func isMarkdownQuoteEnd(text string, i int, entities []markdownEntity) bool {
+	if !strings.HasPrefix(text[i:], "||") || (i+2 < len(text) && text[i+2] != '\n') {
+		return false
+	}
+	return len(entities) == 0 || entities[len(entities)-1].entityType != telego.EntityTypeSpoiler
+}
+
+// isMarkdownEntityEnd reports if there is an end of entity of provided type at the position
+func isMarkdownEntityEnd(text string, i int, entityType string) bool {
+	c := text[i]
+	switch entityType {
+	case telego.EntityTypeBold:
+		return c == '*'
+	case telego.EntityTypeItalic:
+		return c == '_' && nextByte(text, i) != '_'
+	case telego.EntityTypeUnderline:
+		return c == '_' && nextByte(text, i) == '_'
+	case telego.EntityTypeStrikethrough:
+		return c == '~'
+	case telego.EntityTypeSpoiler:
+		return c == '|' && nextByte(text, i) == '|'
+	case telego.EntityTypeCode:
+		return c == '`'
+	case telego.EntityTypePre:
+		return strings.HasPrefix(text[i:], "```")
+	case telego.EntityTypeTextLink, telego.EntityTypeCustomEmoji:
+		return c == ']'
+	default:
+		return false
+	}
+}
+
+// parseMarkdownPreStart parses optional language of pre entity and skips one new line after it, returns language and
+// position of pre entity content
+func parseMarkdownPreStart(text string, i int) (string, int) {
+	language := ""
+
+	languageEnd := i
+	for languageEnd < len(text) && !isHTMLSpace(text[languageEnd]) && text[languageEnd] != '`' {
+		languageEnd++
+	}
+	if i != languageEnd && languageEnd < len(text) && text[languageEnd] != '`' {
+		language = text[i:languageEnd]
+		i = languageEnd
+	}
+
+	if i < len(text) && (text[i] == '\n' || text[i] == '\r') {
+		if next := nextByte(text, i); (next == '\n' || next == '\r') && next != text[i] {
+			i += 2
+		} else {
+			i++
+		}
+	}
+
+	return language, i
+}
+
+// addMarkdownEntity adds closed entity which end is at the position, returns the last position of entity end
+func addMarkdownEntity(text string, i int, entity markdownEntity, builder *entityBuilder) (int, error) {
+	switch entity.entityType {
+	case telego.EntityTypeUnderline, telego.EntityTypeSpoiler:
+		i++
+	case telego.EntityTypePre:
+		i += 2
+		builder.add(entity.offset, telego.MessageEntity{Type: telego.EntityTypePre, Language: entity.language})
+		return i, nil
+	case telego.EntityTypeTextLink:
+		link := builder.text.String()[entity.textStart:]
+		if nextByte(text, i) == '(' {
+			var err error
+			link, i, err = parseMarkdownURL(text, i+2)
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		if textLink, ok := linkEntity(link); ok {
+			builder.add(entity.offset, textLink)
+		}
+		return i, nil
+	case telego.EntityTypeCustomEmoji:
+		if nextByte(text, i) != '(' {
+			return 0, parseErrorf(entity.position, "custom emoji entity must contain a tg://emoji URL")
+		}
+
+		link, end, err := parseMarkdownURL(text, i+2)
+		if err != nil {
+			return 0, err
+		}
+
+		emojiEntity, err := tgLinkEntity(link)
+		if err != nil {
+			return 0, parseErrorf(entity.position, "%s", err)
+		}
+
+		builder.add(entity.offset, emojiEntity)
+		return end, nil
+	}
+
+	builder.add(entity.offset, telego.MessageEntity{Type: entity.entityType})
+	return i, nil
+}
+
+// parseMarkdownURL parses URL that starts at the position until closing ')', returns URL and position of ')'
+func parseMarkdownURL(text string, i int) (string, int, error) {
+	start := i
+	link := strings.Builder{}
+	for i < len(text) && text[i] != ')' {
+		if text[i] == '\\' && i+1 < len(text) && text[i+1] > 0 && text[i+1] <= 126 {
+			link.WriteByte(text[i+1])
+			i += 2
+			continue
+		}
+
+		link.WriteByte(text[i])
+		i++
+	}
+
+	if i == len(text) {
+		return "", 0, parseErrorf(start, "can't find end of a URL")
+	}
+	return link.String(), i, nil
+}
+
+// nextByte returns byte after the position or zero if there is none
+func nextByte(text string, i int) byte {
+	if i+1 < len(text) {
+		return text[i+1]
+	}
+	return 0
+}
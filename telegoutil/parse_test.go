@@ -0,0 +1,230 @@
+package telegoutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mymmrac/telego"
+)
+
+type parseTest struct {
+	name     string
+	text     string
+	result   string
+	entities []telego.MessageEntity
+	err      string
+}
+
+func runParseTests(t *testing.T, parse func(text string) (string, []telego.MessageEntity, error), tests []parseTest) {
+	t.Helper()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, entities, err := parse(tt.text)
+			if tt.err != "" {
+				var parseErr *ParseError
+				require.ErrorAs(t, err, &parseErr)
+				assert.Contains(t, err.Error(), tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.result, text)
+			assert.Equal(t, tt.entities, entities)
+		})
+	}
+}
+
+func TestParseHTML(t *testing.T) {
+	runParseTests(t, ParseHTML, []parseTest{
+		{
+			name:   "plain",
+			text:   "a &lt;b&gt; &amp; &quot;c&quot; &#128512; &#x1F600; &unknown; & x",
+			result: "a <b> & \"c\" 😀 😀 &unknown; & x",
+		},
+		{
+			name:   "nested",
+			text:   "<b>bold <I>italic</I></b> <u>u</u><ins>i</ins><s>s</s><strike>s</strike><del>d</del><em>e</em>",
+			result: "bold italic uissde",
+			entities: []telego.MessageEntity{
+				{Type: telego.EntityTypeBold, Offset: 0, Length: 11},
+				{Type: telego.EntityTypeItalic, Offset: 5, Length: 6},
+				{Type: telego.EntityTypeUnderline, Offset: 12, Length: 2},
+				{Type: telego.EntityTypeStrikethrough, Offset: 14, Length: 3},
+				{Type: telego.EntityTypeItalic, Offset: 17, Length: 1},
+			},
+		},
+		{
+			name:   "spoiler",
+			text:   `<span class="tg-spoiler">a</span><tg-spoiler>🙂</tg-spoiler><strong></strong>`,
+			result: "a🙂",
+			entities: []telego.MessageEntity{
+				{Type: telego.EntityTypeSpoiler, Offset: 0, Length: 3},
+			},
+		},
+		{
+			name: "links",
+			text: `<a href="https://example.com/?a=1&amp;b=2">a</a><a href='tg://user?id=1'>b</a>` +
+				`<a>example.org</a><a href="">c</a>`,
+			result: "abexample.orgc",
+			entities: []telego.MessageEntity{
+				{Type: telego.EntityTypeTextLink, Offset: 0, Length: 1, URL: "https://example.com/?a=1&b=2"},
+				{Type: telego.EntityTypeTextMention, Offset: 1, Length: 1, User: &telego.User{ID: 1}},
+				{Type: telego.EntityTypeTextLink, Offset: 2, Length: 11, URL: "http://example.org"},
+				{Type: telego.EntityTypeTextLink, Offset: 13, Length: 1, URL: "http://c"},
+			},
+		},
+		{
+			name: "code",
+			text: `<pre><code class="language-go">x</code></pre><code><b>y</b></code><pre>z<i>w</i></pre>` +
+				`<code class=language-go>v</code>`,
+			result: "xyzwv",
+			entities: []telego.MessageEntity{
+				{Type: telego.EntityTypePre, Offset: 0, Length: 1, Language: "go"},
+				{Type: telego.EntityTypeCode, Offset: 1, Length: 1},
+				{Type: telego.EntityTypePre, Offset: 2, Length: 2},
+				{Type: telego.EntityTypeCode, Offset: 4, Length: 1},
+			},
+		},
+		{
+			name:   "blockquote",
+			text:   "<blockquote expandable>a<blockquote>b</blockquote></blockquote>\n<blockquote>c</blockquote>",
+			result: "ab\nc",
+			entities: []telego.MessageEntity{
+				{Type: telego.EntityTypeExpandableBlockquote, Offset: 0, Length: 2},
+				{Type: telego.EntityTypeBlockquote, Offset: 3, Length: 1},
+			},
+		},
+		{
+			name: "custom_emoji_and_time",
+			text: `<tg-emoji emoji-id="5368324170671202286">👍</tg-emoji>` +
+				`<tg-time unix="1647531900" format="wDT">today</tg-time><tg-time unix=1>t</tg-time>`,
+			result: "👍todayt",
+			entities: []telego.MessageEntity{
+				{Type: telego.EntityTypeCustomEmoji, Offset: 0, Length: 2, CustomEmojiID: "5368324170671202286"},
+				{Type: telego.EntityTypeDateTime, Offset: 2, Length: 5, UnixTime: 1647531900, DateTimeFormat: "wDT"},
+				{Type: telego.EntityTypeDateTime, Offset: 7, Length: 1, UnixTime: 1},
+			},
+		},
+		{name: "error_unsupported_tag", text: "a <br> b", err: `unsupported start tag "br" at byte offset 2`},
+		{name: "error_unclosed_start", text: "<b", err: "unclosed start tag at byte offset 0"},
+		{name: "error_unclosed_attribute", text: `<a href="x`, err: `unclosed start tag "a" at byte offset 0`},
+		{name: "error_attribute", text: "<a href>x</a>", err: "expected equal sign"},
+		{name: "error_name_token", text: "<a href=x/y>x</a>", err: "unexpected end of name token at byte offset 8"},
+		{name: "error_span", text: `<span class="x">x</span>`, err: `tag "span" must have class "tg-spoiler"`},
+		{name: "error_unexpected_end", text: "x</b>", err: "unexpected end tag at byte offset 1"},
+		{name: "error_unclosed_end", text: "<b>x</b", err: "unclosed end tag at byte offset 4"},
+		{name: "error_unmatched_end", text: "<b><i>x</b></i>", err: `expected "</i>", found "</b>" at byte offset 7`},
+		{name: "error_not_closed", text: "<b>x<i>y</i>", err: `can't find end tag corresponding to start tag "b"`},
+		{name: "error_emoji", text: `<tg-emoji emoji-id="x">👍</tg-emoji>`, err: "invalid custom emoji identifier"},
+		{name: "error_time", text: `<tg-time unix="1" format="x">t</tg-time>`, err: "invalid date-time format"},
+	})
+}
+
+func TestParseMarkdownV2(t *testing.T) {
+	runParseTests(t, ParseMarkdownV2, []parseTest{
+		{
+			name:   "plain",
+			text:   `a\.b\!\\ \c 🙂`,
+			result: `a.b!\ c 🙂`,
+		},
+		{
+			name:   "nested",
+			text:   "*bold \\*text _italic bold ~strike ||spoiler||~ __underline italic bold___ bold*",
+			result: "bold *text italic bold strike spoiler underline italic bold bold",
+			entities: []telego.MessageEntity{
+				{Type: telego.EntityTypeBold, Offset: 0, Length: 64},
+				{Type: telego.EntityTypeItalic, Offset: 11, Length: 48},
+				{Type: telego.EntityTypeStrikethrough, Offset: 23, Length: 14},
+				{Type: telego.EntityTypeSpoiler, Offset: 30, Length: 7},
+				{Type: telego.EntityTypeUnderline, Offset: 38, Length: 21},
+			},
+		},
+		{
+			name:   "ambiguity",
+			text:   "___italic underline_\r__ *a**b*",
+			result: "italic underline ab",
+			entities: []telego.MessageEntity{
+				{Type: telego.EntityTypeItalic, Offset: 0, Length: 16},
+				{Type: telego.EntityTypeUnderline, Offset: 0, Length: 16},
+				{Type: telego.EntityTypeBold, Offset: 17, Length: 2},
+			},
+		},
+		{
+			name:   "links",
+			text:   `[link](http://www.example.com/\)) [mention](tg://user?id=123) [example\.org] [bad](http://)`,
+			result: "link mention example.org bad",
+			entities: []telego.MessageEntity{
+				{Type: telego.EntityTypeTextLink, Offset: 0, Length: 4, URL: "http://www.example.com/)"},
+				{Type: telego.EntityTypeTextMention, Offset: 5, Length: 7, User: &telego.User{ID: 123}},
+				{Type: telego.EntityTypeTextLink, Offset: 13, Length: 11, URL: "http://example.org"},
+			},
+		},
+		{
+			name:   "custom_emoji_and_time",
+			text:   "![👍](tg://emoji?id=5368324170671202286)![22:45](tg://time?unix=1647531900&format=t)",
+			result: "👍22:45",
+			entities: []telego.MessageEntity{
+				{Type: telego.EntityTypeCustomEmoji, Offset: 0, Length: 2, CustomEmojiID: "5368324170671202286"},
+				{Type: telego.EntityTypeDateTime, Offset: 2, Length: 5, UnixTime: 1647531900, DateTimeFormat: "t"},
+			},
+		},
+		{
+			name:   "code",
+			text:   "`a*b\\`` ```go\nfmt.Println(\"\\`\")\n``` ```\r\nx```",
+			result: "a*b` fmt.Println(\"`\")\n x",
+			entities: []telego.MessageEntity{
+				{Type: telego.EntityTypeCode, Offset: 0, Length: 4},
+				{Type: telego.EntityTypePre, Offset: 5, Length: 17, Language: "go"},
+				{Type: telego.EntityTypePre, Offset: 23, Length: 1},
+			},
+		},
+		{
+			name:   "blockquote",
+			text:   ">quote *bold*\n>continued\nplain\n**>expandable\n>hidden||\n>last",
+			result: "quote bold\ncontinued\nplain\nexpandable\nhidden\nlast",
+			entities: []telego.MessageEntity{
+				{Type: telego.EntityTypeBlockquote, Offset: 0, Length: 20},
+				{Type: telego.EntityTypeBold, Offset: 6, Length: 4},
+				{Type: telego.EntityTypeExpandableBlockquote, Offset: 27, Length: 17},
+				{Type: telego.EntityTypeBlockquote, Offset: 45, Length: 4},
+			},
+		},
+		{
+			name: "error_reserved",
+			text: "a.b",
+			err:  "character '.' is reserved and must be escaped with the preceding '\\' at byte offset 1",
+		},
+		{name: "error_pipe", text: "a|b", err: "character '|' is reserved"},
+		{name: "error_not_closed", text: "*bold _italic*", err: "can't find end of bold entity at byte offset 13"},
+		{name: "error_url", text: "[link](http://x", err: "can't find end of a URL at byte offset 7"},
+		{
+			name: "error_emoji_url",
+			text: "![👍]",
+			err:  "custom emoji entity must contain a tg://emoji URL at byte offset 0",
+		},
+		{name: "error_emoji_link", text: "![👍](https://example.com)", err: "custom emoji entity must contain"},
+		{name: "error_emoji_id", text: "![👍](tg://emoji?id=x)", err: "invalid custom emoji identifier"},
+	})
+}
+
+func TestParseFormatted(t *testing.T) {
+	collection, err := ParseFormatted("<b>bold</b>", telego.ModeHTML)
+	require.NoError(t, err)
+
+	text, entities := MessageEntities(Entity("Hi "), collection)
+	assert.Equal(t, "Hi bold", text)
+	assert.Equal(t, []telego.MessageEntity{{Type: telego.EntityTypeBold, Offset: 3, Length: 4}}, entities)
+
+	collection, err = ParseFormatted("_italic_", telego.ModeMarkdownV2)
+	require.NoError(t, err)
+	assert.Equal(t, "italic", collection.Text())
+
+	_, err = ParseFormatted("*bold", telego.ModeMarkdownV2)
+	require.Error(t, err)
+
+	_, err = ParseFormatted("*bold*", telego.ModeMarkdown)
+	require.Error(t, err)
+}
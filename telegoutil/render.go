@@ -0,0 +1,435 @@
+package telegoutil
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mymmrac/telego"
+)
+
+// entityFormat describes how text and entities are written in formatted text
+type entityFormat interface {
+	// line writes prefix of the line that starts at the offset, it's not called inside code entities
+	line(out *strings.Builder, offset int)
+	// start writes start of the entity
+	start(out *strings.Builder, entity telego.MessageEntity)
+	// end writes end of the entity
+	end(out *strings.Builder, entity telego.MessageEntity)
+	// text writes escaped character located at the offset
+	text(out *strings.Builder, r rune, offset int, inCode bool)
+}
+
+// RenderFormatted renders text and its entities into text formatted using [telego.ModeHTML] or
+// [telego.ModeMarkdownV2] parse mode, the result can be parsed back using [ParseFormatted]
+func RenderFormatted(text string, entities []telego.MessageEntity, parseMode string) (string, error) {
+	switch parseMode {
+	case telego.ModeHTML:
+		return RenderHTML(text, entities), nil
+	case telego.ModeMarkdownV2:
+		return RenderMarkdownV2(text, entities), nil
+	default:
+		return "", fmt.Errorf("telegoutil: unsupported parse mode %q", parseMode)
+	}
+}
+
+// RenderMessage renders text and entities of the message (or its caption and caption entities if there is no text)
+// into text formatted using [telego.ModeHTML] or [telego.ModeMarkdownV2] parse mode
+func RenderMessage(message *telego.Message, parseMode string) (string, error) {
+	if message == nil {
+		return "", errors.New("telegoutil: nil message")
+	}
+
+	if message.Text == "" && message.Caption != "" {
+		return RenderFormatted(message.Caption, message.CaptionEntities, parseMode)
+	}
+	return RenderFormatted(message.Text, message.Entities, parseMode)
+}
+
+// RenderHTML renders text and its entities into text formatted using Telegram HTML style, all special characters
+// are escaped. Entities that have no markup (like mentions or URLs that are detected by Telegram) are ignored,
+// overlapping entities are split into nested tags, entities that can't be nested (like formatting inside code) are
+// removed the same way Telegram does. Result parsed with [ParseHTML] produces the same text and entities.
+func RenderHTML(text string, entities []telego.MessageEntity) string {
+	return renderEntities(text, renderableEntities(text, entities), htmlFormat{})
+}
+
+// RenderMarkdownV2 renders text and its entities into text formatted using Telegram MarkdownV2 style, all reserved
+// characters are escaped. Entities that have no markup (like mentions or URLs that are detected by Telegram) are
+// ignored, overlapping entities are split into nested ones, entities that can't be nested (like formatting inside
+// code) are removed the same way Telegram does. Result parsed with [ParseMarkdownV2] produces the same text and
+// entities.
+//
+// Note: Blockquotes that don't start at the beginning of the line or don't end at the end of the line are ignored and
+// blockquotes separated only by a new line are merged, as MarkdownV2 can't represent them
+func RenderMarkdownV2(text string, entities []telego.MessageEntity) string {
+	format := &markdownFormat{}
+	entities = renderableEntities(text, entities)
+
+	lineStarts := map[int]bool{0: true}
+	offset := 0
+	for _, r := range text {
+		offset += UTF16TextLen(string(r))
+		if r == '\n' {
+			lineStarts[offset] = true
+		}
+	}
+
+	entities = slices.DeleteFunc(entities, func(entity telego.MessageEntity) bool {
+		if !isBlockquoteEntity(entity) {
+			return false
+		}
+
+		end := entity.Offset + entity.Length
+		if !lineStarts[entity.Offset] || (!lineStarts[end] && end != offset && !lineStarts[end+1]) {
+			return true
+		}
+
+		format.quotes = append(format.quotes, entity)
+		return false
+	})
+
+	return renderEntities(text, entities, format)
+}
+
+// renderableEntities returns normalized entities that have markup and are within the text
+func renderableEntities(text string, entities []telego.MessageEntity) []telego.MessageEntity {
+	textLength := UTF16TextLen(text)
+	result := make([]telego.MessageEntity, 0, len(entities))
+
+	for _, entity := range entities {
+		switch entity.Type {
+		case telego.EntityTypeBold, telego.EntityTypeItalic, telego.EntityTypeUnderline,
+			telego.EntityTypeStrikethrough, telego.EntityTypeSpoiler, telego.EntityTypeCode, telego.EntityTypePre,
+			telego.EntityTypeTextLink, telego.EntityTypeCustomEmoji, telego.EntityTypeBlockquote,
+			telego.EntityTypeExpandableBlockquote, telego.EntityTypeDateTime:
+		case telego.EntityTypeTextMention:
+			if entity.User == nil {
+				continue
+			}
+		default:
+			continue
+		}
+
+		if entity.Offset >= textLength {
+			continue
+		}
+		entity.Length = min(entity.Length, textLength-entity.Offset)
+
+		result = append(result, entity)
+	}
+
+	return normalizeEntities(result)
+}
+
+// renderEntities renders text with normalized entities using provided format, entities that partially overlap are
+// split into nested ones, splittable entities (like bold) are split in favor of others
+func renderEntities(text string, entities []telego.MessageEntity, format entityFormat) string {
+	out := &strings.Builder{}
+	out.Grow(len(text))
+
+	var open []telego.MessageEntity
+	next := 0
+
+	step := func(offset int) {
+		var reopen []telego.MessageEntity
+
+		closeFrom := slices.IndexFunc(open, func(entity telego.MessageEntity) bool {
+			return entityEnd(entity) <= offset
+		})
+		if closeFrom != -1 {
+			for i := len(open) - 1; i >= closeFrom; i-- {
+				format.end(out, open[i])
+				if entityEnd(open[i]) > offset {
+					reopen = append(reopen, open[i])
+				}
+			}
+			open = open[:closeFrom]
+		}
+
+		var starting []telego.MessageEntity
+		for next < len(entities) && entities[next].Offset <= offset {
+			starting = append(starting, entities[next])
+			next++
+		}
+
+		if len(starting) != 0 {
+			maxEnd := entityEnd(starting[0])
+			for len(open) != 0 {
+				last := open[len(open)-1]
+				if !isSplittableEntity(last) || entityEnd(last) >= maxEnd {
+					break
+				}
+
+				format.end(out, last)
+				reopen = append(reopen, last)
+				open = open[:len(open)-1]
+			}
+		}
+
+		slices.Reverse(reopen)
+		reopen = append(reopen, starting...)
+		slices.SortStableFunc(reopen, func(a, b telego.MessageEntity) int {
+			return entityEnd(b) - entityEnd(a)
+		})
+
+		for _, entity := range reopen {
+			format.start(out, entity)
+			open = append(open, entity)
+		}
+	}
+
+	offset := 0
+	lineStart := true
+	for _, r := range text {
+		inCode := len(open) != 0 && isCodeEntity(open[len(open)-1])
+		if lineStart && !inCode {
+			format.line(out, offset)
+		}
+
+		step(offset)
+
+		inCode = len(open) != 0 && isCodeEntity(open[len(open)-1])
+		format.text(out, r, offset, inCode)
+
+		offset += UTF16TextLen(string(r))
+		lineStart = r == '\n'
+	}
+	step(offset)
+
+	return out.String()
+}
+
+// htmlFormat writes text and entities using HTML style
+type htmlFormat struct{}
+
+// line does nothing, as lines don't have special meaning in HTML
+func (htmlFormat) line(_ *strings.Builder, _ int) {}
+
+// start writes start tag of the entity
+func (htmlFormat) start(out *strings.Builder, entity telego.MessageEntity) {
+	switch entity.Type {
+	case telego.EntityTypeBold:
+		out.WriteString("<b>")
+	case telego.EntityTypeItalic:
+		out.WriteString("<i>")
+	case telego.EntityTypeUnderline:
+		out.WriteString("<u>")
+	case telego.EntityTypeStrikethrough:
+		out.WriteString("<s>")
+	case telego.EntityTypeSpoiler:
+		out.WriteString("<tg-spoiler>")
+	case telego.EntityTypeCode:
+		out.WriteString("<code>")
+	case telego.EntityTypePre:
+		if entity.Language == "" {
+			out.WriteString("<pre>")
+		} else {
+			out.WriteString(`<pre><code class="language-` + escapeHTML(entity.Language, true) + `">`)
+		}
+	case telego.EntityTypeTextLink:
+		out.WriteString(`<a href="` + escapeHTML(entity.URL, true) + `">`)
+	case telego.EntityTypeTextMention:
+		out.WriteString(`<a href="tg://user?id=` + strconv.FormatInt(entity.User.ID, 10) + `">`)
+	case telego.EntityTypeCustomEmoji:
+		out.WriteString(`<tg-emoji emoji-id="` + escapeHTML(entity.CustomEmojiID, true) + `">`)
+	case telego.EntityTypeDateTime:
+		out.WriteString(`<tg-time unix="` + strconv.FormatInt(entity.UnixTime, 10) + `"`)
+		if entity.DateTimeFormat != "" {
+			out.WriteString(` format="` + escapeHTML(entity.DateTimeFormat, true) + `"`)
+		}
+		out.WriteString(">")
+	case telego.EntityTypeBlockquote:
+		out.WriteString("<blockquote>")
+	case telego.EntityTypeExpandableBlockquote:
+		out.WriteString("<blockquote expandable>")
+	}
+}
+
+// end writes end tag of the entity
+func (htmlFormat) end(out *strings.Builder, entity telego.MessageEntity) {
+	switch entity.Type {
+	case telego.EntityTypeBold:
+		out.WriteString("</b>")
+	case telego.EntityTypeItalic:
+		out.WriteString("</i>")
+	case telego.EntityTypeUnderline:
+		out.WriteString("</u>")
+	case telego.EntityTypeStrikethrough:
+		out.WriteString("</s>")
+	case telego.EntityTypeSpoiler:
+		out.WriteString("</tg-spoiler>")
+	case telego.EntityTypeCode:
+		out.WriteString("</code>")
+	case telego.EntityTypePre:
+		if entity.Language == "" {
+			out.WriteString("</pre>")
+		} else {
+			out.WriteString("</code></pre>")
+		}
+	case telego.EntityTypeTextLink, telego.EntityTypeTextMention:
+		out.WriteString("</a>")
+	case telego.EntityTypeCustomEmoji:
+		out.WriteString("</tg-emoji>")
+	case telego.EntityTypeDateTime:
+		out.WriteString("</tg-time>")
+	case telego.EntityTypeBlockquote, telego.EntityTypeExpandableBlockquote:
+		out.WriteString("</blockquote>")
+	}
+}
+
+// text writes escaped character
+func (htmlFormat) text(out *strings.Builder, r rune, _ int, _ bool) {
+	switch r {
+	case '<':
+		out.WriteString("&lt;")
+	case '>':
+		out.WriteString("&gt;")
+	case '&':
+		out.WriteString("&amp;")
+	default:
+		out.WriteRune(r)
+	}
+}
+
+// escapeHTML escapes special HTML characters, quotes are escaped only in attributes
+func escapeHTML(text string, attribute bool) string {
+	replacer := htmlTextReplacer
+	if attribute {
+		replacer = htmlAttributeReplacer
+	}
+	return replacer.Replace(text)
+}
+
+// HTML special characters replacers
+var (
+	htmlTextReplacer      = strings.NewReplacer("<", "&lt;", ">", "&gt;", "&", "&amp;")
+	htmlAttributeReplacer = strings.NewReplacer("<", "&lt;", ">", "&gt;", "&", "&amp;", `"`, "&quot;")
+)
+
+// markdownFormat writes text and entities using MarkdownV2 style
+type markdownFormat struct {
+	// quotes are blockquotes that start and end at line boundaries
+	quotes []telego.MessageEntity
+	// underscore reports if the last written character is '_' of entity markup
+	underscore bool
+	// quoteEnded reports if end of expandable blockquote is already written
+	quoteEnded bool
+}
+
+// line writes blockquote mark if the line is quoted
+func (f *markdownFormat) line(out *strings.Builder, offset int) {
+	for _, quote := range f.quotes {
+		if quote.Offset > offset || entityEnd(quote) <= offset {
+			continue
+		}
+
+		if quote.Offset == offset && quote.Type == telego.EntityTypeExpandableBlockquote {
+			f.markup(out, "**>")
+		} else {
+			f.markup(out, ">")
+		}
+		return
+	}
+}
+
+// start writes start markup of the entity
+func (f *markdownFormat) start(out *strings.Builder, entity telego.MessageEntity) {
+	switch entity.Type {
+	case telego.EntityTypeBold:
+		f.markup(out, "*")
+	case telego.EntityTypeItalic:
+		f.markup(out, "_")
+	case telego.EntityTypeUnderline:
+		f.markup(out, "__")
+	case telego.EntityTypeStrikethrough:
+		f.markup(out, "~")
+	case telego.EntityTypeSpoiler:
+		f.markup(out, "||")
+	case telego.EntityTypeCode:
+		f.markup(out, "`")
+	case telego.EntityTypePre:
+		f.markup(out, "```"+entity.Language+"\n")
+	case telego.EntityTypeTextLink, telego.EntityTypeTextMention:
+		f.markup(out, "[")
+	case telego.EntityTypeCustomEmoji, telego.EntityTypeDateTime:
+		f.markup(out, "![")
+	case telego.EntityTypeBlockquote, telego.EntityTypeExpandableBlockquote:
+		f.quoteEnded = false
+	}
+}
+
+// end writes end markup of the entity
+func (f *markdownFormat) end(out *strings.Builder, entity telego.MessageEntity) {
+	switch entity.Type {
+	case telego.EntityTypeBold:
+		f.markup(out, "*")
+	case telego.EntityTypeItalic:
+		f.markup(out, "_")
+	case telego.EntityTypeUnderline:
+		f.markup(out, "__")
+	case telego.EntityTypeStrikethrough:
+		f.markup(out, "~")
+	case telego.EntityTypeSpoiler:
+		f.markup(out, "||")
+	case telego.EntityTypeCode:
+		f.markup(out, "`")
+	case telego.EntityTypePre:
+		f.markup(out, "```")
+	case telego.EntityTypeTextLink:
+		f.markup(out, "]("+markdownURLReplacer.Replace(entity.URL)+")")
+	case telego.EntityTypeTextMention:
+		f.markup(out, "](tg://user?id="+strconv.FormatInt(entity.User.ID, 10)+")")
+	case telego.EntityTypeCustomEmoji:
+		f.markup(out, "](tg://emoji?id="+markdownURLReplacer.Replace(entity.CustomEmojiID)+")")
+	case telego.EntityTypeDateTime:
+		link := "tg://time?unix=" + strconv.FormatInt(entity.UnixTime, 10)
+		if entity.DateTimeFormat != "" {
+			link += "&format=" + entity.DateTimeFormat
+		}
+		f.markup(out, "]("+markdownURLReplacer.Replace(link)+")")
+	case telego.EntityTypeExpandableBlockquote:
+		if !f.quoteEnded {
+			f.markup(out, "||")
+		}
+	}
+}
+
+// text writes escaped character, expandable blockquote that ends with a new line is closed before it
+func (f *markdownFormat) text(out *strings.Builder, r rune, offset int, inCode bool) {
+	f.underscore = false
+
+	if r == '\n' && !inCode {
+		for _, quote := range f.quotes {
+			if quote.Type == telego.EntityTypeExpandableBlockquote && entityEnd(quote) == offset+1 {
+				f.markup(out, "||")
+				f.quoteEnded = true
+				f.underscore = false
+			}
+		}
+	}
+
+	reserved := markdownV2Reserved
+	if inCode {
+		reserved = "`"
+	}
+	if r == '\\' || r == '\r' || (r < utf8.RuneSelf && strings.IndexByte(reserved, byte(r)) != -1) {
+		out.WriteByte('\\')
+	}
+	out.WriteRune(r)
+}
+
+// markup writes entity markup, '\r' is used to separate '_' of italic and underline entities
+func (f *markdownFormat) markup(out *strings.Builder, markup string) {
+	if f.underscore && markup[0] == '_' {
+		out.WriteByte('\r')
+	}
+	out.WriteString(markup)
+	f.underscore = markup[len(markup)-1] == '_'
+}
+
+// markdownURLReplacer escapes characters of URL in MarkdownV2
+var markdownURLReplacer = strings.NewReplacer(`\`, `\\`, ")", `\)`)
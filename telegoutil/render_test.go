@@ -0,0 +1,223 @@
+package telegoutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mymmrac/telego"
+)
+
+var renderTests = []struct {
+	name     string
+	text     string
+	entities []telego.MessageEntity
+	html     string
+	markdown string
+}{
+	{
+		name:     "plain",
+		text:     "a <b> & \"c\" 1.5! _*[]()~`>#+-=|{}\\",
+		html:     "a &lt;b&gt; &amp; \"c\" 1.5! _*[]()~`&gt;#+-=|{}\\",
+		markdown: "a <b\\> & \"c\" 1\\.5\\! \\_\\*\\[\\]\\(\\)\\~\\`\\>\\#\\+\\-\\=\\|\\{\\}\\\\",
+	},
+	{
+		name: "nested",
+		text: "bold italic underline strike spoiler",
+		entities: []telego.MessageEntity{
+			{Type: telego.EntityTypeBold, Offset: 0, Length: 36},
+			{Type: telego.EntityTypeItalic, Offset: 5, Length: 31},
+			{Type: telego.EntityTypeUnderline, Offset: 12, Length: 24},
+			{Type: telego.EntityTypeStrikethrough, Offset: 22, Length: 6},
+			{Type: telego.EntityTypeSpoiler, Offset: 29, Length: 7},
+		},
+		html:     "<b>bold <i>italic <u>underline <s>strike</s> <tg-spoiler>spoiler</tg-spoiler></u></i></b>",
+		markdown: "*bold _italic __underline ~strike~ ||spoiler||__\r_*",
+	},
+	{
+		name: "overlapping",
+		text: "abcdefgh",
+		entities: []telego.MessageEntity{
+			{Type: telego.EntityTypeItalic, Offset: 3, Length: 5},
+			{Type: telego.EntityTypeBold, Offset: 0, Length: 5},
+			{Type: telego.EntityTypeTextLink, Offset: 2, Length: 4, URL: "https://example.com/(x)"},
+		},
+		html:     `<b>ab</b><a href="https://example.com/(x)"><b>c</b><i><b>de</b>f</i></a><i>gh</i>`,
+		markdown: `*ab*[*c*_*de*f_](https://example.com/(x\))_gh_`,
+	},
+	{
+		name: "overlapping_split",
+		text: "hello world",
+		entities: []telego.MessageEntity{
+			{Type: telego.EntityTypeBold, Offset: 0, Length: 7},
+			{Type: telego.EntityTypeItalic, Offset: 3, Length: 8},
+		},
+		html:     `<b>hel</b><i><b>lo w</b>orld</i>`,
+		markdown: `*hel*_*lo w*orld_`,
+	},
+	{
+		name: "same_range",
+		text: "secret",
+		entities: []telego.MessageEntity{
+			{Type: telego.EntityTypeSpoiler, Offset: 0, Length: 6},
+			{Type: telego.EntityTypeStrikethrough, Offset: 0, Length: 6},
+		},
+		html:     `<tg-spoiler><s>secret</s></tg-spoiler>`,
+		markdown: `||~secret~||`,
+	},
+	{
+		name: "utf16",
+		text: "🙂 emoji 🙂",
+		entities: []telego.MessageEntity{
+			{Type: telego.EntityTypeBold, Offset: 0, Length: 2},
+			{Type: telego.EntityTypeCustomEmoji, Offset: 9, Length: 2, CustomEmojiID: "5368324170671202286"},
+			{Type: telego.EntityTypeMention, Offset: 3, Length: 5},
+		},
+		html:     `<b>🙂</b> emoji <tg-emoji emoji-id="5368324170671202286">🙂</tg-emoji>`,
+		markdown: "*🙂* emoji ![🙂](tg://emoji?id=5368324170671202286)",
+	},
+	{
+		name: "links",
+		text: "user time <link>",
+		entities: []telego.MessageEntity{
+			{Type: telego.EntityTypeTextMention, Offset: 0, Length: 4, User: &telego.User{ID: 123}},
+			{Type: telego.EntityTypeDateTime, Offset: 5, Length: 4, UnixTime: 1647531900, DateTimeFormat: "wDT"},
+			{Type: telego.EntityTypeTextLink, Offset: 10, Length: 6, URL: `https://example.com/?a="1"&b=\`},
+		},
+		html: `<a href="tg://user?id=123">user</a> <tg-time unix="1647531900" format="wDT">time</tg-time> ` +
+			`<a href="https://example.com/?a=&quot;1&quot;&amp;b=\">&lt;link&gt;</a>`,
+		markdown: `[user](tg://user?id=123) ![time](tg://time?unix=1647531900&format=wDT) ` +
+			`[<link\>](https://example.com/?a="1"&b=\\)`,
+	},
+	{
+		name: "code",
+		text: "code `x` go\nfmt.Println(\"\\\")\n",
+		entities: []telego.MessageEntity{
+			{Type: telego.EntityTypeCode, Offset: 0, Length: 8},
+			{Type: telego.EntityTypeBold, Offset: 0, Length: 4},
+			{Type: telego.EntityTypePre, Offset: 12, Length: 18, Language: "go"},
+			{Type: telego.EntityTypeItalic, Offset: 10, Length: 5},
+		},
+		html: "<code>code `x`</code> go\n" +
+			"<pre><code class=\"language-go\">fmt.Println(\"\\\")\n</code></pre>",
+		markdown: "`code \\`x\\`` go\n```go\nfmt.Println(\"\\\\\")\n```",
+	},
+	{
+		name: "blockquote",
+		text: "quote\nline\nplain\nexpandable\nhidden",
+		entities: []telego.MessageEntity{
+			{Type: telego.EntityTypeBlockquote, Offset: 0, Length: 10},
+			{Type: telego.EntityTypeBold, Offset: 3, Length: 5},
+			{Type: telego.EntityTypeExpandableBlockquote, Offset: 17, Length: 17},
+		},
+		html: "<blockquote>quo<b>te\nli</b>ne</blockquote>\nplain\n" +
+			"<blockquote expandable>expandable\nhidden</blockquote>",
+		markdown: ">quo*te\n>li*ne\nplain\n**>expandable\n>hidden||",
+	},
+}
+
+func TestRenderHTML(t *testing.T) {
+	for _, tt := range renderTests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.html, RenderHTML(tt.text, tt.entities))
+		})
+	}
+}
+
+func TestRenderMarkdownV2(t *testing.T) {
+	for _, tt := range renderTests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.markdown, RenderMarkdownV2(tt.text, tt.entities))
+		})
+	}
+
+	t.Run("unaligned_blockquote", func(t *testing.T) {
+		assert.Equal(t, "a b\nc", RenderMarkdownV2("a b\nc", []telego.MessageEntity{
+			{Type: telego.EntityTypeBlockquote, Offset: 2, Length: 3},
+		}))
+	})
+}
+
+func TestRender_RoundTrip(t *testing.T) {
+	for _, tt := range renderTests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected := renderableEntities(tt.text, tt.entities)
+
+			text, entities, err := ParseHTML(RenderHTML(tt.text, tt.entities))
+			require.NoError(t, err)
+			assert.Equal(t, tt.text, text)
+			assert.Equal(t, expected, entities)
+
+			text, entities, err = ParseMarkdownV2(RenderMarkdownV2(tt.text, tt.entities))
+			require.NoError(t, err)
+			assert.Equal(t, tt.text, text)
+			assert.Equal(t, expected, entities)
+		})
+	}
+}
+
+func TestRenderFormatted(t *testing.T) {
+	entities := []telego.MessageEntity{{Type: telego.EntityTypeBold, Offset: 0, Length: 4}}
+
+	text, err := RenderFormatted("bold", entities, telego.ModeHTML)
+	require.NoError(t, err)
+	assert.Equal(t, "<b>bold</b>", text)
+
+	text, err = RenderFormatted("bold", entities, telego.ModeMarkdownV2)
+	require.NoError(t, err)
+	assert.Equal(t, "*bold*", text)
+
+	_, err = RenderFormatted("bold", entities, telego.ModeMarkdown)
+	require.Error(t, err)
+}
+
+func TestRenderMessage(t *testing.T) {
+	entities := []telego.MessageEntity{{Type: telego.EntityTypeItalic, Offset: 0, Length: 1}}
+
+	text, err := RenderMessage(&telego.Message{Text: "a", Entities: entities}, telego.ModeHTML)
+	require.NoError(t, err)
+	assert.Equal(t, "<i>a</i>", text)
+
+	text, err = RenderMessage(&telego.Message{Caption: "b", CaptionEntities: entities}, telego.ModeHTML)
+	require.NoError(t, err)
+	assert.Equal(t, "<i>b</i>", text)
+
+	_, err = RenderMessage(nil, telego.ModeHTML)
+	require.Error(t, err)
+}
+
+func TestRender_ParseRoundTrip(t *testing.T) {
+	formatted := []string{
+		"<b>bold <i>italic <u>underline</u></i> <s>strike</s></b> <tg-spoiler>spoiler</tg-spoiler>",
+		`<a href="https://example.com">link <b>bold</b></a> <a href="tg://user?id=1">user</a>`,
+		`<tg-emoji emoji-id="5368324170671202286">👍</tg-emoji> <tg-time unix="1" format="r">now</tg-time>`,
+		"<code>code</code> <pre>pre</pre> <pre><code class=\"language-go\">go</code></pre>",
+		"<blockquote>a <b>b</b>\nc</blockquote>\nd\n<blockquote expandable>e\n<i>f</i></blockquote>",
+		"*bold _italic __underline italic bold___ bold* ~strike ||spoiler||~",
+		"[link *bold*](https://example.com) ![👍](tg://emoji?id=5368324170671202286) `code` ```go\ngo```",
+		">quote *bold*\n>quote\nplain\n**>expandable\n>_hidden_||",
+	}
+
+	for _, text := range formatted {
+		t.Run(text, func(t *testing.T) {
+			parse := ParseMarkdownV2
+			if text[0] == '<' {
+				parse = ParseHTML
+			}
+
+			plainText, entities, err := parse(text)
+			require.NoError(t, err)
+
+			for _, parseMode := range []string{telego.ModeHTML, telego.ModeMarkdownV2} {
+				rendered, err := RenderFormatted(plainText, entities, parseMode)
+				require.NoError(t, err)
+
+				collection, err := ParseFormatted(rendered, parseMode)
+				require.NoError(t, err)
+				assert.Equal(t, plainText, collection.Text())
+				assert.Equal(t, entities, collection.Entities())
+			}
+		})
+	}
+}
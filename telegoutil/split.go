@@ -0,0 +1,38 @@
+package telegoutil
+
+import (
+	"github.com/mymmrac/telego"
+	"github.com/mymmrac/telego/internal/textsplit"
+)
+
+// Telegram limits of text length in UTF-16 code units
+const (
+	// MaxMessageTextLength is max length of message text
+	MaxMessageTextLength = textsplit.MaxMessageTextLength
+	// MaxCaptionLength is max length of media caption
+	MaxCaptionLength = textsplit.MaxCaptionLength
+)
+
+// SplitMessage splits text and its entities into chunks no longer than limit UTF-16 code units (like
+// [MaxMessageTextLength] or [MaxCaptionLength]). Text is split at paragraph, line, sentence or word boundaries if
+// possible, surrogate pairs are never split, whitespace around split points is trimmed. Entities are re-based to the
+// chunk they belong to, formatting entities that span multiple chunks are split between them, while entities that
+// can't be split (like custom emoji, code or mentions) are moved to the next chunk, unless they don't fit into one.
+//
+// Chunks can be sent using [MessageWithEntities] or their [MessageEntityCollection.Text] and
+// [MessageEntityCollection.Entities]
+func SplitMessage(text string, entities []telego.MessageEntity, limit int) []MessageEntityCollection {
+	chunks := textsplit.SplitEntities(text, entities, limit, func(entity *telego.MessageEntity) (*int, *int, string) {
+		return &entity.Offset, &entity.Length, entity.Type
+	})
+
+	collections := make([]MessageEntityCollection, 0, len(chunks))
+	for _, chunk := range chunks {
+		collections = append(collections, MessageEntityCollection{
+			text:     chunk.Text,
+			entities: chunk.Entities,
+		})
+	}
+
+	return collections
+}
@@ -0,0 +1,159 @@
+package telegoutil
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mymmrac/telego"
+)
+
+func splitTexts(collections []MessageEntityCollection) []string {
+	texts := make([]string, 0, len(collections))
+	for _, collection := range collections {
+		texts = append(texts, collection.Text())
+	}
+	return texts
+}
+
+func TestSplitMessage(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		limit int
+		texts []string
+	}{
+		{
+			name:  "fits",
+			text:  "  Hello world  ",
+			limit: 20,
+			texts: []string{"  Hello world  "},
+		},
+		{
+			name:  "no_limit",
+			text:  "Hello world",
+			limit: 0,
+			texts: []string{"Hello world"},
+		},
+		{
+			name:  "paragraphs",
+			text:  "First paragraph.\n\nSecond one. Still second.",
+			limit: 30,
+			texts: []string{"First paragraph.", "Second one. Still second."},
+		},
+		{
+			name:  "lines",
+			text:  "Line one, then\nline two. And more",
+			limit: 25,
+			texts: []string{"Line one, then", "line two. And more"},
+		},
+		{
+			name:  "sentences",
+			text:  "One sentence. Two sentence and more words",
+			limit: 25,
+			texts: []string{"One sentence.", "Two sentence and more", "words"},
+		},
+		{
+			name:  "words",
+			text:  "aaaa bbbb cccc dddd",
+			limit: 10,
+			texts: []string{"aaaa bbbb", "cccc dddd"},
+		},
+		{
+			name:  "hard",
+			text:  "aaaaaaaaaabbbbb",
+			limit: 10,
+			texts: []string{"aaaaaaaaaa", "bbbbb"},
+		},
+		{
+			name:  "surrogate_pair",
+			text:  "aaa🙂🙂",
+			limit: 4,
+			texts: []string{"aaa", "🙂🙂"},
+		},
+		{
+			name:  "whitespace",
+			text:  "aaaa \n \n \n bbbb",
+			limit: 6,
+			texts: []string{"aaaa", "bbbb"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collections := SplitMessage(tt.text, nil, tt.limit)
+			assert.Equal(t, tt.texts, splitTexts(collections))
+
+			for _, collection := range collections {
+				if tt.limit > 0 {
+					assert.LessOrEqual(t, UTF16TextLen(collection.Text()), tt.limit)
+				}
+				assert.Empty(t, collection.Entities())
+			}
+		})
+	}
+}
+
+func TestSplitMessage_entities(t *testing.T) {
+	text, entities := MessageEntities(
+		Entity("🙂 Bold text").Bold(), Entity(" plain. "),
+		Entity("Link that spans chunks").TextLink("https://example.com"),
+	)
+
+	collections := SplitMessage(text, entities, 20)
+	require.Equal(t, []string{"🙂 Bold text plain.", "Link that spans", "chunks"}, splitTexts(collections))
+
+	assert.Equal(t, []telego.MessageEntity{
+		{Type: telego.EntityTypeBold, Offset: 0, Length: 12},
+	}, collections[0].Entities())
+	assert.Equal(t, []telego.MessageEntity{
+		{Type: telego.EntityTypeTextLink, Offset: 0, Length: 15, URL: "https://example.com"},
+	}, collections[1].Entities())
+	assert.Equal(t, []telego.MessageEntity{
+		{Type: telego.EntityTypeTextLink, Offset: 0, Length: 6, URL: "https://example.com"},
+	}, collections[2].Entities())
+
+	long := strings.Repeat("word ", 2000)
+	for _, collection := range SplitMessage(long, nil, MaxMessageTextLength) {
+		assert.LessOrEqual(t, UTF16TextLen(collection.Text()), MaxMessageTextLength)
+	}
+}
+
+func TestSplitMessage_unsplittableEntities(t *testing.T) {
+	text, entities := MessageEntities(
+		Entity("Hello world, see "), Entity("code value").Code(), Entity(" and "), Entity("🙂").CustomEmoji("1"),
+	)
+
+	collections := SplitMessage(text, entities, 22)
+	require.Equal(t, []string{"Hello world, see", "code value and 🙂"}, splitTexts(collections))
+
+	assert.Empty(t, collections[0].Entities())
+	assert.Equal(t, []telego.MessageEntity{
+		{Type: telego.EntityTypeCode, Offset: 0, Length: 10},
+		{Type: telego.EntityTypeCustomEmoji, Offset: 15, Length: 2, CustomEmojiID: "1"},
+	}, collections[1].Entities())
+
+	collections = SplitMessage("aaaa 🙂🙂", []telego.MessageEntity{
+		{Type: telego.EntityTypeCustomEmoji, Offset: 5, Length: 2, CustomEmojiID: "1"},
+		{Type: telego.EntityTypeCustomEmoji, Offset: 7, Length: 2, CustomEmojiID: "2"},
+	}, 8)
+	require.Equal(t, []string{"aaaa", "🙂🙂"}, splitTexts(collections))
+	assert.Empty(t, collections[0].Entities())
+	assert.Equal(t, []telego.MessageEntity{
+		{Type: telego.EntityTypeCustomEmoji, Offset: 0, Length: 2, CustomEmojiID: "1"},
+		{Type: telego.EntityTypeCustomEmoji, Offset: 2, Length: 2, CustomEmojiID: "2"},
+	}, collections[1].Entities())
+
+	collections = SplitMessage("abcdefghijkl", []telego.MessageEntity{
+		{Type: telego.EntityTypeCode, Offset: 0, Length: 12},
+	}, 8)
+	require.Equal(t, []string{"abcdefgh", "ijkl"}, splitTexts(collections))
+	assert.Equal(t, []telego.MessageEntity{
+		{Type: telego.EntityTypeCode, Offset: 0, Length: 8},
+	}, collections[0].Entities())
+	assert.Equal(t, []telego.MessageEntity{
+		{Type: telego.EntityTypeCode, Offset: 0, Length: 4},
+	}, collections[1].Entities())
+}
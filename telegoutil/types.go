@@ -3,6 +3,7 @@ package telegoutil
 import (
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/valyala/fasthttp"
 
@@ -52,6 +53,14 @@ func FileFromURL(url string) telego.InputFile {
 	}
 }
 
+// FileFromLocalPath creates [telego.InputFile] from path of the file stored on disk of local Bot API server, see
+// [telego.Bot.LocalFileURI] if server sees files under a different path
+func FileFromLocalPath(path string) telego.InputFile {
+	return telego.InputFile{
+		URL: telego.FileURIScheme + path,
+	}
+}
+
 // FileFromID creates [telego.InputFile] from file ID
 func FileFromID(id string) telego.InputFile {
 	return telego.InputFile{
@@ -59,8 +68,18 @@ func FileFromID(id string) telego.InputFile {
 	}
 }
 
-// DownloadFile returns downloaded file bytes or error
+// DownloadFile returns downloaded file bytes or error, only HTTP(S) URLs are supported, files stored on disk of local
+// Bot API server (file:// URLs returned by [telego.Bot.FileDownloadURL]) can be read using [telego.Bot.DownloadFile]
+// or [telego.Bot.OpenLocalFile]
+// Note: File is fully loaded into memory, use [telego.Bot.DownloadFile] to stream file using bot's API caller
 func DownloadFile(url string) ([]byte, error) {
+	if strings.HasPrefix(url, telego.FileURIScheme) {
+		return nil, fmt.Errorf("telego: local file should be read using bot: %q", url)
+	}
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return nil, fmt.Errorf("telego: unsupported url scheme: %q", url)
+	}
+
 	var file []byte
 	status, file, err := fasthttp.Get(file, url)
 	if err != nil {
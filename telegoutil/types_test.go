@@ -3,6 +3,8 @@ package telegoutil
 import (
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -53,6 +55,11 @@ func TestFileByURL(t *testing.T) {
 	assert.Equal(t, text1, f.URL)
 }
 
+func TestFileFromLocalPath(t *testing.T) {
+	f := FileFromLocalPath("/tmp/file")
+	assert.Equal(t, "file:///tmp/file", f.URL)
+}
+
 func TestDownloadFile(t *testing.T) {
 	expectedData := []byte("OK")
 	srv := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
@@ -83,6 +90,15 @@ func TestDownloadFile(t *testing.T) {
 		require.Error(t, err)
 		assert.Nil(t, data)
 	})
+
+	t.Run("error_scheme", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "file")
+		require.NoError(t, os.WriteFile(path, expectedData, 0o600))
+
+		data, err := DownloadFile(telego.FileURIScheme + path)
+		require.Error(t, err)
+		assert.Nil(t, data)
+	})
 }
 
 func TestID(t *testing.T) {
@@ -0,0 +1,121 @@
+package telego
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+
+	ta "github.com/mymmrac/telego/telegoapi"
+)
+
+// UploadProgress reports progress of file upload, sent is number of file bytes sent, total is the size of all files
+// in the request or zero if size of any file is unknown
+// Note: Progress is reported from the goroutine that constructs request body, as files are read while the request is
+// being sent
+type UploadProgress func(sent, total int64)
+
+// uploadProgressKey context key of upload progress
+type uploadProgressKey struct{}
+
+// WithUploadProgress returns context that reports upload progress of files sent in the request made with it, size of
+// files is known if reader implements Len method (like [bytes.Reader]) or is an [os.File]
+func WithUploadProgress(ctx context.Context, progress UploadProgress) context.Context {
+	return context.WithValue(ctx, uploadProgressKey{}, progress)
+}
+
+// uploadProgressFromContext returns upload progress from context, nil if not set
+func uploadProgressFromContext(ctx context.Context) UploadProgress {
+	progress, _ := ctx.Value(uploadProgressKey{}).(UploadProgress)
+	return progress
+}
+
+// uploadCounter counts sent bytes of all files in the request
+type uploadCounter struct {
+	progress UploadProgress
+	total    int64
+
+	mutex sync.Mutex
+	sent  int64
+}
+
+// add adds sent bytes and reports progress
+func (c *uploadCounter) add(n int) {
+	c.mutex.Lock()
+	c.sent += int64(n)
+	sent := c.sent
+	c.mutex.Unlock()
+
+	c.progress(sent, c.total)
+}
+
+// progressReader represents [ta.NamedReader] that reports read bytes to counter
+type progressReader struct {
+	ta.NamedReader
+	counter *uploadCounter
+}
+
+// Read reads data and reports progress
+func (r progressReader) Read(p []byte) (int, error) {
+	n, err := r.NamedReader.Read(p)
+	if n > 0 {
+		r.counter.add(n)
+	}
+	return n, err
+}
+
+// trackUploadProgress returns files that report upload progress
+func trackUploadProgress(files map[string]ta.NamedReader, progress UploadProgress) map[string]ta.NamedReader {
+	counter := &uploadCounter{
+		progress: progress,
+	}
+
+	tracked := make(map[string]ta.NamedReader, len(files))
+	for field, file := range files {
+		if isNil(file) {
+			continue
+		}
+
+		if counter.total >= 0 {
+			size, ok := readerSize(file)
+			if ok {
+				counter.total += size
+			} else {
+				counter.total = -1
+			}
+		}
+
+		tracked[field] = progressReader{
+			NamedReader: file,
+			counter:     counter,
+		}
+	}
+
+	if counter.total < 0 {
+		counter.total = 0
+	}
+
+	return tracked
+}
+
+// readerSize returns size of unread data of the reader if it's known
+func readerSize(reader io.Reader) (int64, bool) {
+	switch r := reader.(type) {
+	case interface{ Len() int }:
+		return int64(r.Len()), true
+	case *os.File:
+		info, err := r.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return 0, false
+		}
+
+		offset, err := r.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return 0, false
+		}
+
+		return info.Size() - offset, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,112 @@
+package telego
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	ta "github.com/mymmrac/telego/telegoapi"
+)
+
+type testSizedReader struct {
+	*bytes.Reader
+}
+
+func (testSizedReader) Name() string {
+	return "sized"
+}
+
+type testUnsizedReader struct {
+	io.Reader
+}
+
+func (testUnsizedReader) Name() string {
+	return "unsized"
+}
+
+func TestWithUploadProgress(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	m := newMockedBot(ctrl)
+	m.Bot.constructor = ta.DefaultConstructor{}
+
+	var mutex sync.Mutex
+	var sent, total int64
+	ctx := WithUploadProgress(t.Context(), func(s, tl int64) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		assert.GreaterOrEqual(t, s, sent)
+		sent, total = s, tl
+	})
+
+	m.MockAPICaller.EXPECT().
+		Call(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, data *ta.RequestData) (*ta.Response, error) {
+			_, err := io.Copy(io.Discard, data.BodyStream)
+			require.NoError(t, err)
+			return telegoResponse(t, expectedMessage), nil
+		})
+
+	_, err := m.Bot.SendDocument(ctx, &SendDocumentParams{
+		Document:  InputFile{File: testSizedReader{Reader: bytes.NewReader(make([]byte, 100_000))}},
+		Thumbnail: &InputFile{File: testSizedReader{Reader: bytes.NewReader(make([]byte, 10))}},
+	})
+	require.NoError(t, err)
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	assert.EqualValues(t, 100_010, sent)
+	assert.EqualValues(t, 100_010, total)
+}
+
+func Test_trackUploadProgress(t *testing.T) {
+	var sent, total int64
+	files := trackUploadProgress(map[string]ta.NamedReader{
+		"a": testSizedReader{Reader: bytes.NewReader([]byte("abc"))},
+		"b": testUnsizedReader{Reader: strings.NewReader("de")},
+		"c": nil,
+	}, func(s, tl int64) {
+		sent, total = s, tl
+	})
+	require.Len(t, files, 2)
+
+	for _, file := range files {
+		_, err := io.ReadAll(file)
+		require.NoError(t, err)
+	}
+
+	assert.EqualValues(t, 5, sent)
+	assert.EqualValues(t, 0, total)
+	assert.Equal(t, "sized", files["a"].Name())
+}
+
+func Test_readerSize(t *testing.T) {
+	size, ok := readerSize(bytes.NewReader([]byte("abc")))
+	assert.True(t, ok)
+	assert.EqualValues(t, 3, size)
+
+	_, ok = readerSize(testUnsizedReader{})
+	assert.False(t, ok)
+
+	path := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(path, []byte("abcdef"), 0o600))
+
+	file, err := os.Open(path) //nolint:gosec
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = file.Close() })
+
+	_, err = file.Seek(2, io.SeekStart)
+	require.NoError(t, err)
+
+	size, ok = readerSize(file)
+	assert.True(t, ok)
+	assert.EqualValues(t, 4, size)
+}
@@ -3,8 +3,11 @@ package telego
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"sync"
 
 	"github.com/mymmrac/telego/internal/json"
+	tm "github.com/mymmrac/telego/telegometrics"
 )
 
 const defaultWebhookUpdateChanBuffer = 128
@@ -59,20 +62,33 @@ func (b *Bot) UpdatesViaWebhook(
 
 	updatesChan := make(chan Update, wh.updateChanBuffer)
 
-	err = registerHandler(func(ctx context.Context, data []byte) error {
-		b.log.Debugf("Webhook request with data: %s", string(data))
+	// Handlers send updates holding read lock, so updates chan is closed only when nothing can send to it
+	var updatesMutex sync.RWMutex
+	closed := false
+
+	err = registerHandler(func(requestCtx context.Context, data []byte) error {
+		b.logger().DebugContext(requestCtx, "Webhook request", slog.String(logKeyData, string(data)))
 
 		var update Update
-		err = json.Unmarshal(data, &update)
-		if err != nil {
-			b.log.Errorf("Webhook decoding error: %s", err)
+		if err := json.Unmarshal(data, &update); err != nil {
+			b.logger().ErrorContext(requestCtx, "Webhook decoding error", slog.Any(logKeyError, err))
 			return fmt.Errorf("telego: webhook decoding update: %w", err)
 		}
+		b.Instrumentation().AddCounter(tm.MetricUpdates, 1, tm.L(tm.LabelSource, tm.SourceWebhook))
+
+		updatesMutex.RLock()
+		defer updatesMutex.RUnlock()
+
+		if closed {
+			return fmt.Errorf("telego: webhook context: %w", ctx.Err())
+		}
 
 		select {
+		case <-requestCtx.Done():
+			return fmt.Errorf("telego: webhook handler context: %w", requestCtx.Err())
 		case <-ctx.Done():
-			return fmt.Errorf("telego: webhook handler context: %w", ctx.Err())
-		case updatesChan <- update.WithContext(ctx):
+			return fmt.Errorf("telego: webhook context: %w", ctx.Err())
+		case updatesChan <- update.WithContext(requestCtx):
 			return nil
 		}
 	})
@@ -84,7 +100,11 @@ func (b *Bot) UpdatesViaWebhook(
 	go func() {
 		<-ctx.Done()
 		b.running.Store(runningNone)
+
+		updatesMutex.Lock()
+		closed = true
 		close(updatesChan)
+		updatesMutex.Unlock()
 	}()
 
 	return updatesChan, nil